@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"gorm.io/gorm"
+)
+
+const (
+	createAdminUsage = "usage : library-api create-admin [flags] USERNAME"
+	envAdminPassword = "LIBRARY_ADMIN_PASSWORD"
+)
+
+// runCreateAdmin handles the create-admin subcommand. Registration only
+// creates members, so this is how a fresh deployment gets its first admin.
+// The password comes from LIBRARY_ADMIN_PASSWORD to keep it out of the shell
+// history.
+func runCreateAdmin(database *gorm.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New(createAdminUsage)
+	}
+
+	password := os.Getenv(envAdminPassword)
+	if password == "" {
+		return fmt.Errorf("%s wajib diisi", envAdminPassword)
+	}
+
+	userService := service.NewUserService(repository.NewUserRepository(database), repository.NewTokenRepository(database), repository.NewAuditRepository(database), repository.NewTransactor(database), nil)
+
+	err := userService.CreateAdmin(data.Actor{}, request.User{Username: args[0], Password: password})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("admin %s berhasil dibuat\n", args[0])
+
+	return nil
+}
@@ -1,54 +1,159 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/config"
 	"github.com/ilhaamms/library-api/controller"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/jwtkey"
 	"github.com/ilhaamms/library-api/middleware"
 )
 
 type API struct {
-	authorController controller.AuthorController
-	userController   controller.UserController
-	bookController   controller.BookController
+	config              *config.Config
+	keys                *jwtkey.KeySet
+	authorController    controller.AuthorController
+	userController      controller.UserController
+	bookController      controller.BookController
+	copyController      controller.BookCopyController
+	loanController      controller.LoanController
+	holdController      controller.HoldController
+	fineController      controller.FineController
+	publisherController controller.PublisherController
+	categoryController  controller.CategoryController
+	trashController     controller.TrashController
+	auditController     controller.AuditController
+	isbnController      controller.IsbnController
+	jwksController      controller.JwksController
+	denylist            middleware.Denylist
 }
 
 func NewAPI(
+	cfg *config.Config,
+	keys *jwtkey.KeySet,
 	authorController controller.AuthorController,
 	userController controller.UserController,
 	bookController controller.BookController,
+	copyController controller.BookCopyController,
+	loanController controller.LoanController,
+	holdController controller.HoldController,
+	fineController controller.FineController,
+	publisherController controller.PublisherController,
+	categoryController controller.CategoryController,
+	trashController controller.TrashController,
+	auditController controller.AuditController,
+	isbnController controller.IsbnController,
+	jwksController controller.JwksController,
+	denylist middleware.Denylist,
 ) *API {
 	return &API{
-		authorController: authorController,
-		userController:   userController,
-		bookController:   bookController,
+		config:              cfg,
+		keys:                keys,
+		authorController:    authorController,
+		userController:      userController,
+		bookController:      bookController,
+		copyController:      copyController,
+		loanController:      loanController,
+		holdController:      holdController,
+		fineController:      fineController,
+		publisherController: publisherController,
+		categoryController:  categoryController,
+		trashController:     trashController,
+		auditController:     auditController,
+		isbnController:      isbnController,
+		jwksController:      jwksController,
+		denylist:            denylist,
 	}
 }
 
 func (a *API) RegisterRoutes() *gin.Engine {
 	r := gin.Default()
+	r.Use(middleware.RequestId(), middleware.Locale(), middleware.ErrorHandler())
+
+	r.GET("/.well-known/jwks.json", a.jwksController.GetJwks)
+
+	// Routes registered after this point go through request signing, the
+	// JWKS above stays public for services that verify our tokens.
+	r.Use(middleware.Signature(a.config.SigningKeys, time.Duration(a.config.SigningMaxSkew)*time.Second, a.config.RequireSignedRequests))
 
 	auth := r.Group("/auth")
 	{
 		auth.POST("/register", a.userController.Register)
 		auth.POST("/login", a.userController.Login)
+		auth.POST("/refresh", a.userController.Refresh)
+		auth.POST("/logout", middleware.Auth(a.keys, a.denylist), a.userController.Logout)
 	}
 
-	r.POST("/authors", middleware.Auth(), a.authorController.CreateAuthor)
-	r.GET("/authors", middleware.Auth(), a.authorController.GetAllAuthor)
-	r.GET("/authors/:id", middleware.Auth(), a.authorController.GetAuthorsById)
-	r.DELETE("/authors/:id", middleware.Auth(), a.authorController.DeleteAuthorsById)
-	r.PUT("/authors/:id", middleware.Auth(), a.authorController.UpdateAuthorsById)
+	staff := middleware.RequireRole(data.StaffRoles...)
+	admin := middleware.RequireRole(data.RoleAdmin)
+
+	r.POST("/authors", middleware.Auth(a.keys, a.denylist), staff, a.authorController.CreateAuthor)
+	r.GET("/authors", middleware.Auth(a.keys, a.denylist), a.authorController.GetAllAuthor)
+	r.GET("/authors/:id", middleware.Auth(a.keys, a.denylist), a.authorController.GetAuthorsById)
+	r.DELETE("/authors/:id", middleware.Auth(a.keys, a.denylist), staff, a.authorController.DeleteAuthorsById)
+	r.PUT("/authors/:id", middleware.Auth(a.keys, a.denylist), staff, a.authorController.UpdateAuthorsById)
+	r.PATCH("/authors/:id", middleware.Auth(a.keys, a.denylist), staff, a.authorController.PatchAuthorsById)
+	r.POST("/authors/:id/restore", middleware.Auth(a.keys, a.denylist), staff, a.authorController.RestoreAuthorsById)
+	r.GET("/authors/:id/history", middleware.Auth(a.keys, a.denylist), staff, a.auditController.GetAuthorHistory)
+
+	r.POST("/publishers", middleware.Auth(a.keys, a.denylist), staff, a.publisherController.CreatePublisher)
+	r.GET("/publishers", middleware.Auth(a.keys, a.denylist), a.publisherController.GetAllPublisher)
+	r.GET("/publishers/:id", middleware.Auth(a.keys, a.denylist), a.publisherController.GetPublisherById)
+	r.DELETE("/publishers/:id", middleware.Auth(a.keys, a.denylist), staff, a.publisherController.DeletePublisherById)
+	r.PUT("/publishers/:id", middleware.Auth(a.keys, a.denylist), staff, a.publisherController.UpdatePublisherById)
+
+	r.POST("/categories", middleware.Auth(a.keys, a.denylist), staff, a.categoryController.CreateCategory)
+	r.GET("/categories", middleware.Auth(a.keys, a.denylist), a.categoryController.GetCategoryTree)
+	r.GET("/categories/:id", middleware.Auth(a.keys, a.denylist), a.categoryController.GetCategoryById)
+	r.GET("/categories/:id/books", middleware.Auth(a.keys, a.denylist), a.categoryController.GetCategoryBooks)
+	r.PUT("/categories/:id", middleware.Auth(a.keys, a.denylist), staff, a.categoryController.UpdateCategoryById)
+	r.DELETE("/categories/:id", middleware.Auth(a.keys, a.denylist), staff, a.categoryController.DeleteCategoryById)
+
+	r.GET("/isbn/:value/validate", middleware.Auth(a.keys, a.denylist), a.isbnController.Validate)
+
+	r.POST("/books", middleware.Auth(a.keys, a.denylist), staff, a.bookController.CreateBook)
+	r.GET("/books", middleware.Auth(a.keys, a.denylist), a.bookController.GetAllBook)
+	r.GET("/books/:id", middleware.Auth(a.keys, a.denylist), a.bookController.GetBookById)
+	r.DELETE("/books/:id", middleware.Auth(a.keys, a.denylist), staff, a.bookController.DeleteBookById)
+	r.PUT("/books/:id", middleware.Auth(a.keys, a.denylist), staff, a.bookController.Update)
+	r.PATCH("/books/:id", middleware.Auth(a.keys, a.denylist), staff, a.bookController.Patch)
+	r.POST("/books/:id/restore", middleware.Auth(a.keys, a.denylist), staff, a.bookController.Restore)
+	r.GET("/books/:id/history", middleware.Auth(a.keys, a.denylist), staff, a.auditController.GetBookHistory)
+
+	r.GET("/trash", middleware.Auth(a.keys, a.denylist), staff, a.trashController.GetTrash)
+	r.POST("/trash/purge", middleware.Auth(a.keys, a.denylist), admin, a.trashController.PurgeTrash)
+
+	r.GET("/audit", middleware.Auth(a.keys, a.denylist), staff, a.auditController.GetAudit)
+
+	r.POST("/books/:id/copies", middleware.Auth(a.keys, a.denylist), staff, a.copyController.CreateBookCopy)
+	r.GET("/books/:id/copies", middleware.Auth(a.keys, a.denylist), a.copyController.GetAllBookCopy)
+	r.GET("/books/:id/copies/:copyId", middleware.Auth(a.keys, a.denylist), a.copyController.GetBookCopyById)
+	r.PUT("/books/:id/copies/:copyId", middleware.Auth(a.keys, a.denylist), staff, a.copyController.UpdateBookCopy)
+	r.DELETE("/books/:id/copies/:copyId", middleware.Auth(a.keys, a.denylist), staff, a.copyController.DeleteBookCopy)
+
+	r.GET("/search", middleware.Auth(a.keys, a.denylist), a.bookController.Search)
+
+	r.POST("/loans", middleware.Auth(a.keys, a.denylist), a.loanController.Checkout)
+	r.POST("/loans/:id/return", middleware.Auth(a.keys, a.denylist), staff, a.loanController.Return)
+	r.POST("/loans/:id/renew", middleware.Auth(a.keys, a.denylist), a.loanController.Renew)
+	r.GET("/users/me/loans", middleware.Auth(a.keys, a.denylist), a.loanController.GetMyLoans)
+
+	r.POST("/books/:id/holds", middleware.Auth(a.keys, a.denylist), a.holdController.PlaceHold)
+	r.DELETE("/holds/:id", middleware.Auth(a.keys, a.denylist), a.holdController.CancelHold)
+	r.GET("/users/me/holds", middleware.Auth(a.keys, a.denylist), a.holdController.GetMyHolds)
 
-	r.POST("/books", middleware.Auth(), a.bookController.CreateBook)
-	r.GET("/books", middleware.Auth(), a.bookController.GetAllBook)
-	r.GET("/books/:id", middleware.Auth(), a.bookController.GetBookById)
-	r.DELETE("/books/:id", middleware.Auth(), a.bookController.DeleteBookById)
-	r.PUT("/books/:id", middleware.Auth(), a.bookController.Update)
+	r.PUT("/users/:id/role", middleware.Auth(a.keys, a.denylist), admin, a.userController.UpdateRole)
+	r.PUT("/users/:id/category", middleware.Auth(a.keys, a.denylist), staff, a.userController.UpdateCategory)
+	r.GET("/users/me/fines", middleware.Auth(a.keys, a.denylist), a.fineController.GetMyFines)
+	r.POST("/fines/payments", middleware.Auth(a.keys, a.denylist), staff, a.fineController.RecordPayment)
+	r.POST("/fines/waivers", middleware.Auth(a.keys, a.denylist), staff, a.fineController.Waive)
 
 	return r
 }
 
 func (a *API) Run() {
 	r := a.RegisterRoutes()
-	r.Run(":8080")
+	r.Run(a.config.Addr())
 }
@@ -0,0 +1,212 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+// Codes are part of the API contract, clients branch on them instead of on
+// the human readable message.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodePrecondition = "precondition_failed"
+	CodeMediaType    = "unsupported_media_type"
+	CodeTooLarge     = "payload_too_large"
+	CodeInternal     = "internal_error"
+)
+
+// FieldError names a field and the rule it broke. Code is the stable name
+// of the rule, Message is filled from Key in the language of the request
+// when the error is rendered.
+type FieldError struct {
+	Field   string      `json:"field"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Key     string      `json:"-"`
+	Params  i18n.Params `json:"-"`
+}
+
+// CodeInvalid is the field code of rules checked by hand in the services.
+const CodeInvalid = "invalid"
+
+// Error is a failure the API knows how to report: the HTTP status and code
+// it maps to, the message key and params of the i18n catalogue and, for
+// validation failures, the offending fields. Data is anything else the
+// client needs to act on the error, such as the rows blocking a delete. Err
+// keeps the underlying cause of internal errors.
+type Error struct {
+	Status int
+	Code   string
+	Key    string
+	Params i18n.Params
+	Fields []FieldError
+	Data   interface{}
+	Err    error
+}
+
+// The kinds below carry no message key and only serve as errors.Is targets,
+// e.g. errors.Is(err, apperror.ErrNotFound) holds for every NotFound error.
+var (
+	ErrBadRequest   = &Error{Status: http.StatusBadRequest, Code: CodeBadRequest}
+	ErrValidation   = &Error{Status: http.StatusUnprocessableEntity, Code: CodeValidation}
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Status: http.StatusForbidden, Code: CodeForbidden}
+	ErrNotFound     = &Error{Status: http.StatusNotFound, Code: CodeNotFound}
+	ErrConflict     = &Error{Status: http.StatusConflict, Code: CodeConflict}
+	ErrPrecondition = &Error{Status: http.StatusPreconditionFailed, Code: CodePrecondition}
+	ErrMediaType    = &Error{Status: http.StatusUnsupportedMediaType, Code: CodeMediaType}
+	ErrTooLarge     = &Error{Status: http.StatusRequestEntityTooLarge, Code: CodeTooLarge}
+	ErrInternal     = &Error{Status: http.StatusInternalServerError, Code: CodeInternal}
+)
+
+// Error renders the message in the default language.
+func (e *Error) Error() string {
+	return e.Message(i18n.Default)
+}
+
+// Message renders the message in lang, followed by the cause if there is one.
+func (e *Error) Message(lang string) string {
+	message := i18n.T(lang, e.Key, e.Params)
+	if e.Err != nil {
+		return message + " : " + e.Err.Error()
+	}
+
+	return message
+}
+
+// Details returns the fields with their message rendered in lang.
+func (e *Error) Details(lang string) []FieldError {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	details := make([]FieldError, len(e.Fields))
+	for i, field := range e.Fields {
+		field.Message = i18n.T(lang, field.Key, field.Params)
+		details[i] = field
+	}
+
+	return details
+}
+
+// With sets the params of the message and of the fields sharing its key.
+func (e *Error) With(params i18n.Params) *Error {
+	e.Params = params
+	for i := range e.Fields {
+		if e.Fields[i].Key == e.Key {
+			e.Fields[i].Params = params
+		}
+	}
+
+	return e
+}
+
+// WithData returns a copy of e carrying data, so a shared error such as a
+// repository's ErrX can be returned with the details of one request.
+func (e *Error) WithData(data interface{}) *Error {
+	copied := *e
+	copied.Data = data
+
+	return &copied
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches a kind on its code alone, and an error with a message key on
+// both code and key.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(*Error)
+
+	return ok && kind.Code == e.Code && (kind.Key == "" || kind.Key == e.Key)
+}
+
+func newError(kind *Error, key string) *Error {
+	return &Error{Status: kind.Status, Code: kind.Code, Key: key}
+}
+
+// BadRequest is a request the server could not read, such as a malformed
+// body or a non numeric id in the path.
+func BadRequest(key string, fields ...FieldError) *Error {
+	err := newError(ErrBadRequest, key)
+	err.Fields = fields
+
+	return err
+}
+
+// Validation is a well formed request whose content breaks a rule.
+func Validation(key string, fields ...FieldError) *Error {
+	err := newError(ErrValidation, key)
+	err.Fields = fields
+
+	return err
+}
+
+// Invalid is a validation error about a single field.
+func Invalid(field, key string) *Error {
+	return Validation(key, FieldError{Field: field, Code: CodeInvalid, Key: key})
+}
+
+// Malformed is a body that could not be bound. The binding error is shown
+// as is, it is not part of the catalogue.
+func Malformed(err error) *Error {
+	return BadRequest("request.malformed").With(i18n.Params{"error": err.Error()})
+}
+
+func Unauthorized(key string) *Error {
+	return newError(ErrUnauthorized, key)
+}
+
+func Forbidden(key string) *Error {
+	return newError(ErrForbidden, key)
+}
+
+func NotFound(key string) *Error {
+	return newError(ErrNotFound, key)
+}
+
+func Conflict(key string) *Error {
+	return newError(ErrConflict, key)
+}
+
+// PreconditionFailed is a conditional request, such as one with If-Match,
+// whose condition no longer holds.
+func PreconditionFailed(key string) *Error {
+	return newError(ErrPrecondition, key)
+}
+
+// MediaType is a body sent with a Content-Type the endpoint does not read.
+func MediaType(key string) *Error {
+	return newError(ErrMediaType, key)
+}
+
+// TooLarge is a body over the size the endpoint accepts.
+func TooLarge(key string) *Error {
+	return newError(ErrTooLarge, key)
+}
+
+func Internal(key string, err error) *Error {
+	internal := newError(ErrInternal, key)
+	internal.Err = err
+
+	return internal
+}
+
+// From returns err as an *Error. Errors that are not typed are reported as
+// internal errors with err as the cause.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return Internal("error.internal", err)
+}
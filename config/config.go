@@ -0,0 +1,401 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	DBDriver       string `yaml:"db_driver" toml:"db_driver"`
+	DBPath         string `yaml:"db_path" toml:"db_path"`
+	DBDSN          string `yaml:"db_dsn" toml:"db_dsn"`
+	Port           int    `yaml:"port" toml:"port"`
+	JwtSecret      string `yaml:"jwt_secret" toml:"jwt_secret"`
+	LoanDays       int    `yaml:"loan_days" toml:"loan_days"`
+	MaxRenewals    int    `yaml:"max_renewals" toml:"max_renewals"`
+	HoldPickupDays int    `yaml:"hold_pickup_days" toml:"hold_pickup_days"`
+
+	// Deleted books and authors stay in the trash, where they can be
+	// restored, for TrashRetentionDays before they are purged.
+	TrashRetentionDays int `yaml:"trash_retention_days" toml:"trash_retention_days"`
+
+	// Fine amounts are integer minor units (sen), rates are charged per day
+	// overdue and looked up by patron category.
+	FineGraceDays  int              `yaml:"fine_grace_days" toml:"fine_grace_days"`
+	FineMaxPerItem int64            `yaml:"fine_max_per_item" toml:"fine_max_per_item"`
+	FineRates      map[string]int64 `yaml:"fine_rates" toml:"fine_rates"`
+
+	// JwtKeys switches token signing from the HS256 jwt_secret to asymmetric
+	// keys. Only the key named by JwtActiveKey signs, the others still verify
+	// tokens issued before a rotation.
+	JwtKeys      []JwtKey `yaml:"jwt_keys" toml:"jwt_keys"`
+	JwtActiveKey string   `yaml:"jwt_active_key" toml:"jwt_active_key"`
+
+	// SigningKeys maps the key id of a partner client to its HMAC secret.
+	// Signed requests older or newer than SigningMaxSkew seconds are refused.
+	SigningKeys           map[string]string `yaml:"signing_keys" toml:"signing_keys"`
+	SigningMaxSkew        int               `yaml:"signing_max_skew" toml:"signing_max_skew"`
+	RequireSignedRequests bool              `yaml:"require_signed_requests" toml:"require_signed_requests"`
+
+	MigrateOnStart bool `yaml:"migrate_on_start" toml:"migrate_on_start"`
+}
+
+type JwtKey struct {
+	Kid        string `yaml:"kid" toml:"kid"`
+	Algorithm  string `yaml:"algorithm" toml:"algorithm"`
+	PrivateKey string `yaml:"private_key" toml:"private_key"`
+	PublicKey  string `yaml:"public_key" toml:"public_key"`
+}
+
+const (
+	EnvConfigFile     = "LIBRARY_CONFIG"
+	EnvDBDriver       = "LIBRARY_DB_DRIVER"
+	EnvDBPath         = "LIBRARY_DB_PATH"
+	EnvDBDSN          = "LIBRARY_DB_DSN"
+	EnvPort           = "LIBRARY_PORT"
+	EnvJwtSecret      = "LIBRARY_JWT_SECRET"
+	EnvLoanDays       = "LIBRARY_LOAN_DAYS"
+	EnvMaxRenewals    = "LIBRARY_MAX_RENEWALS"
+	EnvHoldPickupDays = "LIBRARY_HOLD_PICKUP_DAYS"
+	EnvTrashRetention = "LIBRARY_TRASH_RETENTION_DAYS"
+	EnvFineGraceDays  = "LIBRARY_FINE_GRACE_DAYS"
+	EnvFineMaxPerItem = "LIBRARY_FINE_MAX_PER_ITEM"
+	EnvJwtActiveKey   = "LIBRARY_JWT_ACTIVE_KEY"
+	EnvSigningMaxSkew = "LIBRARY_SIGNING_MAX_SKEW"
+	EnvRequireSigned  = "LIBRARY_REQUIRE_SIGNED_REQUESTS"
+	EnvMigrateOnStart = "LIBRARY_MIGRATE_ON_START"
+)
+
+// ExampleJwtSecrets are the secrets that were published as examples, the one
+// docker-compose.yaml once fell back to and the placeholder config.example.yaml
+// once shipped. They are public, so a deployment still using one is refused.
+var ExampleJwtSecrets = []string{"library-api-change-me", "ganti-dengan-secret-minimal-16-karakter"}
+
+func Default() Config {
+	return Config{
+		DBDriver:       DriverSQLite,
+		DBPath:         "db/library.db",
+		Port:           8080,
+		LoanDays:       14,
+		MaxRenewals:    2,
+		HoldPickupDays: 3,
+
+		TrashRetentionDays: 30,
+
+		FineGraceDays:  1,
+		FineMaxPerItem: 5000000,
+		FineRates: map[string]int64{
+			data.UserCategoryRegular: 100000,
+			data.UserCategoryStudent: 50000,
+			data.UserCategorySenior:  50000,
+		},
+		SigningMaxSkew: 300,
+		MigrateOnStart: true,
+	}
+}
+
+// Load builds the runtime configuration. Values are applied in order of
+// precedence: defaults, the optional config file, environment variables and
+// finally command-line flags.
+func Load(args []string) (*Config, error) {
+	cfg, _, err := Parse(args)
+
+	return cfg, err
+}
+
+// Parse works like Load and also returns the arguments left after the
+// flags, used by subcommands such as migrate.
+func Parse(args []string) (*Config, []string, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("library-api", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(EnvConfigFile), "path to a YAML or TOML config file")
+	dbDriver := fs.String("db-driver", "", "database driver: sqlite, postgres or mysql")
+	dbPath := fs.String("db", "", "path to the SQLite database file")
+	dbDSN := fs.String("db-dsn", "", "connection string for postgres or mysql")
+	port := fs.Int("port", 0, "HTTP port to listen on")
+	jwtSecret := fs.String("jwt-secret", "", "secret used to sign JWT tokens")
+	loanDays := fs.Int("loan-days", 0, "number of days a copy may be borrowed")
+	maxRenewals := fs.Int("max-renewals", 0, "maximum number of times a loan may be renewed")
+	holdPickupDays := fs.Int("hold-pickup-days", 0, "number of days a reserved copy waits for pickup")
+	trashRetentionDays := fs.Int("trash-retention-days", 0, "number of days deleted books and authors can be restored")
+	fineGraceDays := fs.Int("fine-grace-days", 0, "number of overdue days that are not fined")
+	fineMaxPerItem := fs.Int64("fine-max-per-item", 0, "maximum fine for a single loan in minor units, 0 means no cap")
+	requireSigned := fs.Bool("require-signed-requests", false, "reject requests without an HMAC signature")
+	migrateOnStart := fs.Bool("migrate-on-start", true, "apply pending migrations when the server starts")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		err = cfg.loadFile(*configFile)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = cfg.loadEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "db-driver":
+			cfg.DBDriver = *dbDriver
+		case "db":
+			cfg.DBPath = *dbPath
+		case "db-dsn":
+			cfg.DBDSN = *dbDSN
+		case "port":
+			cfg.Port = *port
+		case "jwt-secret":
+			cfg.JwtSecret = *jwtSecret
+		case "loan-days":
+			cfg.LoanDays = *loanDays
+		case "max-renewals":
+			cfg.MaxRenewals = *maxRenewals
+		case "hold-pickup-days":
+			cfg.HoldPickupDays = *holdPickupDays
+		case "trash-retention-days":
+			cfg.TrashRetentionDays = *trashRetentionDays
+		case "fine-grace-days":
+			cfg.FineGraceDays = *fineGraceDays
+		case "fine-max-per-item":
+			cfg.FineMaxPerItem = *fineMaxPerItem
+		case "require-signed-requests":
+			cfg.RequireSignedRequests = *requireSigned
+		case "migrate-on-start":
+			cfg.MigrateOnStart = *migrateOnStart
+		}
+	})
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, fs.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca file config : %w", err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, c)
+	case ".toml":
+		err = toml.Unmarshal(content, c)
+	default:
+		return errors.New("file config harus berformat .yaml, .yml atau .toml")
+	}
+
+	if err != nil {
+		return fmt.Errorf("gagal membaca file config : %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	if value, ok := os.LookupEnv(EnvDBDriver); ok {
+		c.DBDriver = value
+	}
+
+	if value, ok := os.LookupEnv(EnvDBPath); ok {
+		c.DBPath = value
+	}
+
+	if value, ok := os.LookupEnv(EnvDBDSN); ok {
+		c.DBDSN = value
+	}
+
+	if value, ok := os.LookupEnv(EnvJwtSecret); ok {
+		c.JwtSecret = value
+	}
+
+	if value, ok := os.LookupEnv(EnvJwtActiveKey); ok {
+		c.JwtActiveKey = value
+	}
+
+	for name, target := range map[string]*int{
+		EnvPort:           &c.Port,
+		EnvLoanDays:       &c.LoanDays,
+		EnvMaxRenewals:    &c.MaxRenewals,
+		EnvHoldPickupDays: &c.HoldPickupDays,
+		EnvTrashRetention: &c.TrashRetentionDays,
+		EnvFineGraceDays:  &c.FineGraceDays,
+		EnvSigningMaxSkew: &c.SigningMaxSkew,
+	} {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s harus berupa angka", name)
+		}
+
+		*target = number
+	}
+
+	if value, ok := os.LookupEnv(EnvFineMaxPerItem); ok {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s harus berupa angka", EnvFineMaxPerItem)
+		}
+
+		c.FineMaxPerItem = number
+	}
+
+	for name, target := range map[string]*bool{
+		EnvRequireSigned:  &c.RequireSignedRequests,
+		EnvMigrateOnStart: &c.MigrateOnStart,
+	} {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s harus berupa true atau false", name)
+		}
+
+		*target = enabled
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	switch c.DBDriver {
+	case DriverSQLite:
+		if c.DBPath == "" {
+			return errors.New("db_path tidak boleh kosong")
+		}
+	case DriverPostgres, DriverMySQL:
+		if c.DBDSN == "" {
+			return fmt.Errorf("db_dsn wajib diisi untuk driver %s", c.DBDriver)
+		}
+	default:
+		return fmt.Errorf("db_driver hanya boleh salah satu dari : %s", strings.Join(Drivers, ", "))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return errors.New("port harus di antara 1 dan 65535")
+	}
+
+	if len(c.JwtKeys) == 0 && len(c.JwtSecret) < 16 {
+		return errors.New("jwt_secret minimal 16 karakter")
+	}
+
+	if len(c.JwtKeys) == 0 && slices.Contains(ExampleJwtSecrets, c.JwtSecret) {
+		return errors.New("jwt_secret masih memakai contoh yang sudah diketahui umum, ganti dengan secret acak")
+	}
+
+	err := c.validateJwtKeys()
+	if err != nil {
+		return err
+	}
+
+	if c.LoanDays < 1 {
+		return errors.New("loan_days minimal 1 hari")
+	}
+
+	if c.MaxRenewals < 0 {
+		return errors.New("max_renewals tidak boleh negatif")
+	}
+
+	if c.HoldPickupDays < 1 {
+		return errors.New("hold_pickup_days minimal 1 hari")
+	}
+
+	if c.TrashRetentionDays < 1 {
+		return errors.New("trash_retention_days minimal 1 hari")
+	}
+
+	if c.FineGraceDays < 0 {
+		return errors.New("fine_grace_days tidak boleh negatif")
+	}
+
+	if c.FineMaxPerItem < 0 {
+		return errors.New("fine_max_per_item tidak boleh negatif")
+	}
+
+	if _, ok := c.FineRates[data.UserCategoryRegular]; !ok {
+		return errors.New("fine_rates wajib memiliki tarif regular")
+	}
+
+	for category, rate := range c.FineRates {
+		if rate < 0 {
+			return fmt.Errorf("fine_rates.%s tidak boleh negatif", category)
+		}
+	}
+
+	if c.SigningMaxSkew < 1 {
+		return errors.New("signing_max_skew minimal 1 detik")
+	}
+
+	if c.RequireSignedRequests && len(c.SigningKeys) == 0 {
+		return errors.New("require_signed_requests membutuhkan signing_keys")
+	}
+
+	for keyId, secret := range c.SigningKeys {
+		if len(secret) < 16 {
+			return fmt.Errorf("signing_keys.%s minimal 16 karakter", keyId)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateJwtKeys() error {
+	if len(c.JwtKeys) == 0 {
+		return nil
+	}
+
+	kids := map[string]bool{}
+
+	for _, key := range c.JwtKeys {
+		if key.Kid == "" {
+			return errors.New("jwt_keys.kid tidak boleh kosong")
+		}
+
+		if kids[key.Kid] {
+			return fmt.Errorf("jwt_keys.%s terdaftar lebih dari sekali", key.Kid)
+		}
+
+		kids[key.Kid] = true
+
+		if key.PrivateKey == "" && key.PublicKey == "" {
+			return fmt.Errorf("jwt_keys.%s wajib memiliki private_key atau public_key", key.Kid)
+		}
+	}
+
+	if !kids[c.JwtActiveKey] {
+		return errors.New("jwt_active_key harus salah satu kid pada jwt_keys")
+	}
+
+	return nil
+}
+
+func (c *Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+
+	driver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+var Drivers = []string{DriverSQLite, DriverPostgres, DriverMySQL}
+
+// InitDB opens the database selected by db_driver. SQLite reads db_path, the
+// other drivers connect with db_dsn.
+func InitDB(cfg *Config) (*gorm.DB, error) {
+	switch cfg.DBDriver {
+	case DriverSQLite, "":
+		return InitDbSQLite(cfg.DBPath)
+	case DriverPostgres:
+		return InitDbPostgres(cfg.DBDSN)
+	case DriverMySQL:
+		return InitDbMySQL(cfg.DBDSN)
+	}
+
+	return nil, fmt.Errorf("db_driver %s tidak didukung", cfg.DBDriver)
+}
+
+func InitDbPostgres(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
+
+// InitDbMySQL turns on the DSN options the application relies on: times are
+// parsed into time.Time and migration files may hold several statements.
+func InitDbMySQL(dsn string) (*gorm.DB, error) {
+	mysqlConfig, err := driver.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlConfig.ParseTime = true
+	mysqlConfig.MultiStatements = true
+
+	return gorm.Open(mysql.Open(mysqlConfig.FormatDSN()), &gorm.Config{})
+}
@@ -1,20 +1,25 @@
 package config
 
 import (
+	"strings"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-type Config struct {
-}
+// InitDbSQLite opens the database at path. SQLite enforces foreign keys per
+// connection, so they are switched on in the DSN for every connection the
+// pool opens instead of with a PRAGMA that only reaches one of them.
+func InitDbSQLite(path string) (*gorm.DB, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
 
-func InitDbSQLite() (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open("/app/db/library.db"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(path+separator+"_foreign_keys=on"), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 
-	db.Exec("PRAGMA foreign_keys = ON;")
-
 	return db, nil
 }
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type AuditController interface {
+	GetAudit(c *gin.Context)
+	GetBookHistory(c *gin.Context)
+	GetAuthorHistory(c *gin.Context)
+}
+
+type auditController struct {
+	auditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &auditController{auditService: auditService}
+}
+
+func (ac *auditController) GetAudit(c *gin.Context) {
+	var query request.AuditQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	ac.audit(c, query)
+}
+
+func (ac *auditController) GetBookHistory(c *gin.Context) {
+	ac.history(c, data.AuditBook)
+}
+
+func (ac *auditController) GetAuthorHistory(c *gin.Context) {
+	ac.history(c, data.AuditAuthor)
+}
+
+// history lists the audit entries of the entity named by the id in the
+// path, which may since have been deleted.
+func (ac *auditController) history(c *gin.Context, entity string) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var query request.AuditQuery
+
+	err = c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	query.Entity, query.Id = entity, id
+
+	ac.audit(c, query)
+}
+
+func (ac *auditController) audit(c *gin.Context, query request.AuditQuery) {
+	entries, pagination, err := ac.auditService.FindAll(query)
+	if err != nil {
+		if errors.Is(err, service.ErrAuditEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseAudit{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "audit.empty"),
+				Data:       entries,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseAudit{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "audit.fetched"),
+		Pagination: pagination,
+		Data:       entries,
+	})
+}
+
+// actor is who sends the request, as the audit trail records it. Requests
+// that need no token, such as a registration, have no username or role.
+func actor(c *gin.Context) data.Actor {
+	actor := data.Actor{
+		RequestId: c.GetString(data.RequestIdKey),
+		ClientIp:  c.ClientIP(),
+	}
+
+	if claims, ok := c.Get("claims"); ok {
+		actor.Username = claims.(*data.Claims).Username
+		actor.Role = claims.(*data.Claims).Role
+	}
+
+	return actor
+}
@@ -1,11 +1,11 @@
 package controller
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
 	"github.com/ilhaamms/library-api/service"
@@ -17,6 +17,8 @@ type AuthorController interface {
 	GetAuthorsById(c *gin.Context)
 	DeleteAuthorsById(c *gin.Context)
 	UpdateAuthorsById(c *gin.Context)
+	PatchAuthorsById(c *gin.Context)
+	RestoreAuthorsById(c *gin.Context)
 }
 
 type authorController struct {
@@ -33,138 +35,118 @@ func (ac *authorController) CreateAuthor(c *gin.Context) {
 
 	err := c.ShouldBind(&author)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
 		return
 	}
 
-	_, err = ac.AuthorService.Save(author)
+	_, err = ac.AuthorService.Save(actor(c), author)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, response.WebResponseAuthor{
 		StatusCode: http.StatusCreated,
-		Message:    "Berhasil menyimpan data author",
+		Message:    message(c, "author.created"),
 		Data:       author,
 	})
 }
 
 func (ac *authorController) GetAllAuthor(c *gin.Context) {
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
-		return
-	}
+	var query request.AuthorQuery
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	err := c.ShouldBindQuery(&query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
 		return
 	}
 
-	authors, totalPage, err := ac.AuthorService.FindAll(page, limit)
+	authors, pagination, err := ac.AuthorService.FindAll(query)
 	if err != nil {
-		if err.Error() == "data author kosong" {
+		if errors.Is(err, service.ErrAuthorEmpty) {
 			c.JSON(http.StatusOK, response.WebResponseAuthor{
 				StatusCode: http.StatusOK,
-				Message:    "Data author kosong",
+				Message:    message(c, "author.empty"),
 				Data:       authors,
 			})
 			return
 		}
 
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseAuthors{
 		StatusCode: http.StatusOK,
-		Message:    "Berhasil mengambil data list author",
-		Pagination: response.Pagination{
-			CurrentPage: page,
-			TotalPage:   totalPage,
-			Limit:       limit,
-		},
-		Data: authors,
+		Message:    message(c, "author.listed"),
+		Pagination: *pagination,
+		Data:       authors,
 	})
 }
 
 func (ac *authorController) GetAuthorsById(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	author, err := ac.AuthorService.FindById(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
+		return
+	}
+
+	if notModified(c, author.Version, author) {
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseAuthor{
 		StatusCode: http.StatusOK,
-		Message:    "Berhasil mengambil data author",
+		Message:    message(c, "author.fetched"),
 		Data:       author,
 	})
 }
 
 func (ac *authorController) DeleteAuthorsById(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var options request.DeleteAuthor
+
+	err = c.ShouldBindQuery(&options)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	version, err := ifMatch(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
-	author, err := ac.AuthorService.DeleteById(id)
+	deletion, err := ac.AuthorService.DeleteById(actor(c), id, version, options)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseAuthor{
 		StatusCode: http.StatusOK,
-		Message:    "Berhasil menghapus data author",
-		Data:       author,
+		Message:    message(c, "author.deleted"),
+		Data:       deletion,
 	})
 }
 
 func (ac *authorController) UpdateAuthorsById(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -172,25 +154,83 @@ func (ac *authorController) UpdateAuthorsById(c *gin.Context) {
 
 	err = c.ShouldBind(&author)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	authorResponse, err := ac.AuthorService.UpdateById(id, author)
+	authorResponse, err := ac.AuthorService.UpdateById(actor(c), id, version, author)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
+	c.Header("ETag", representationTag(authorResponse.Version, authorResponse))
+
 	c.JSON(http.StatusOK, response.WebResponseAuthor{
 		StatusCode: http.StatusOK,
-		Message:    "Berhasil mengupdate data author",
+		Message:    message(c, "author.updated"),
 		Data:       authorResponse,
 	})
 }
+
+func (ac *authorController) PatchAuthorsById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	patch, err := mergePatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	author, err := ac.AuthorService.PatchById(actor(c), id, version, patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", representationTag(author.Version, author))
+
+	c.JSON(http.StatusOK, response.WebResponseAuthor{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "author.updated"),
+		Data:       author,
+	})
+}
+
+func (ac *authorController) RestoreAuthorsById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	author, err := ac.AuthorService.Restore(actor(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", representationTag(author.Version, author))
+
+	c.JSON(http.StatusOK, response.WebResponseAuthor{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "author.restored"),
+		Data:       author,
+	})
+}
@@ -1,11 +1,11 @@
 package controller
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
 	"github.com/ilhaamms/library-api/service"
@@ -17,6 +17,9 @@ type BookController interface {
 	GetBookById(c *gin.Context)
 	DeleteBookById(c *gin.Context)
 	Update(c *gin.Context)
+	Patch(c *gin.Context)
+	Restore(c *gin.Context)
+	Search(c *gin.Context)
 }
 
 type bookController struct {
@@ -33,139 +36,114 @@ func (bc *bookController) CreateBook(c *gin.Context) {
 
 	err := c.ShouldBind(&book)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
 		return
 	}
 
-	_, err = bc.bookService.Save(book)
+	created, err := bc.bookService.Save(actor(c), book)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
+	book.Isbn = created.Isbn
+
 	c.JSON(http.StatusCreated, response.WebResponseBook{
 		StatusCode: http.StatusCreated,
-		Message:    "Berhasil menyimpan data book",
+		Message:    message(c, "book.created"),
 		Data:       book,
 	})
 }
 
 func (bc *bookController) GetAllBook(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
-		return
-	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var query request.BookQuery
+
+	err := c.ShouldBindQuery(&query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
 		return
 	}
 
-	books, totalPages, err := bc.bookService.FindAll(page, limit)
+	books, pagination, err := bc.bookService.FindAll(query)
 	if err != nil {
 
-		if err.Error() == "data book kosong" {
+		if errors.Is(err, service.ErrBookEmpty) {
 			c.JSON(http.StatusOK, response.WebResponseBook{
 				StatusCode: http.StatusOK,
-				Message:    "Data book kosong",
+				Message:    message(c, "book.empty"),
 				Data:       books,
 			})
 			return
 		}
 
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseBooks{
 		StatusCode: http.StatusOK,
-		Message:    "Data buku berhasil diambil",
-		Pagination: response.Pagination{
-			CurrentPage: page,
-			TotalPage:   totalPages,
-			Limit:       limit,
-		},
-		Data: books,
+		Message:    message(c, "book.fetched"),
+		Pagination: *pagination,
+		Data:       books,
 	})
 }
 
 func (bc *bookController) GetBookById(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	book, err := bc.bookService.FindById(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
+		return
+	}
+
+	if notModified(c, book.Version, book) {
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseBook{
 		StatusCode: http.StatusOK,
-		Message:    "Data buku berhasil diambil",
+		Message:    message(c, "book.fetched"),
 		Data:       book,
 	})
 }
 
 func (bc *bookController) DeleteBookById(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	version, err := ifMatch(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
-	book, err := bc.bookService.DeleteById(id)
+	book, err := bc.bookService.DeleteById(actor(c), id, version)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.WebResponseBook{
 		StatusCode: http.StatusOK,
-		Message:    "Data buku berhasil dihapus",
+		Message:    message(c, "book.deleted"),
 		Data:       book,
 	})
 }
 
 func (bc *bookController) Update(c *gin.Context) {
 
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := paramId(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -173,25 +151,118 @@ func (bc *bookController) Update(c *gin.Context) {
 
 	err = c.ShouldBind(&book)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := bc.bookService.Update(actor(c), id, version, book)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", representationTag(updated.Version, updated))
+
+	c.JSON(http.StatusOK, response.WebResponseBook{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "book.updated"),
+		Data:       updated,
+	})
+}
+
+func (bc *bookController) Patch(c *gin.Context) {
+
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	patch, err := mergePatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	_, err = bc.bookService.Update(id, book)
+	book, err := bc.bookService.Patch(actor(c), id, version, patch)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		c.Error(err)
 		return
 	}
 
+	c.Header("ETag", representationTag(book.Version, book))
+
+	c.JSON(http.StatusOK, response.WebResponseBook{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "book.updated"),
+		Data:       book,
+	})
+}
+
+func (bc *bookController) Restore(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	book, err := bc.bookService.Restore(actor(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", representationTag(book.Version, book))
+
 	c.JSON(http.StatusOK, response.WebResponseBook{
 		StatusCode: http.StatusOK,
-		Message:    "Data buku berhasil diupdate",
+		Message:    message(c, "book.restored"),
 		Data:       book,
 	})
 }
+
+func (bc *bookController) Search(c *gin.Context) {
+
+	var query request.SearchBook
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	books, pagination, err := bc.bookService.Search(query)
+	if err != nil {
+
+		if errors.Is(err, service.ErrBookEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseBook{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "book.empty"),
+				Data:       books,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBooks{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "book.searched"),
+		Pagination: *pagination,
+		Data:       books,
+	})
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type BookCopyController interface {
+	CreateBookCopy(c *gin.Context)
+	GetAllBookCopy(c *gin.Context)
+	GetBookCopyById(c *gin.Context)
+	UpdateBookCopy(c *gin.Context)
+	DeleteBookCopy(c *gin.Context)
+}
+
+type bookCopyController struct {
+	bookCopyService service.BookCopyService
+}
+
+func NewBookCopyController(bookCopyService service.BookCopyService) BookCopyController {
+	return &bookCopyController{bookCopyService: bookCopyService}
+}
+
+func (bc *bookCopyController) CreateBookCopy(c *gin.Context) {
+	bookId, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var bookCopy request.CreateBookCopy
+
+	err = c.ShouldBind(&bookCopy)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	copyResponse, err := bc.bookCopyService.Save(bookId, bookCopy)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseBookCopy{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "copy.created"),
+		Data:       copyResponse,
+	})
+}
+
+func (bc *bookCopyController) GetAllBookCopy(c *gin.Context) {
+	bookId, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	copies, err := bc.bookCopyService.FindAllByBookId(bookId)
+	if err != nil {
+		if errors.Is(err, service.ErrBookCopyEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseBookCopy{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "copy.empty"),
+				Data:       copies,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBookCopy{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "copy.listed"),
+		Data:       copies,
+	})
+}
+
+func (bc *bookCopyController) GetBookCopyById(c *gin.Context) {
+	bookId, id, err := bookCopyParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	bookCopy, err := bc.bookCopyService.FindById(bookId, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBookCopy{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "copy.fetched"),
+		Data:       bookCopy,
+	})
+}
+
+func (bc *bookCopyController) UpdateBookCopy(c *gin.Context) {
+	bookId, id, err := bookCopyParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var bookCopy request.UpdateBookCopy
+
+	err = c.ShouldBind(&bookCopy)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	copyResponse, err := bc.bookCopyService.Update(bookId, id, bookCopy)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBookCopy{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "copy.updated"),
+		Data:       copyResponse,
+	})
+}
+
+func (bc *bookCopyController) DeleteBookCopy(c *gin.Context) {
+	bookId, id, err := bookCopyParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	bookCopy, err := bc.bookCopyService.DeleteById(bookId, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBookCopy{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "copy.deleted"),
+		Data:       bookCopy,
+	})
+}
+
+func bookCopyParams(c *gin.Context) (int, int, error) {
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, err := strconv.Atoi(c.Param("copyId"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return bookId, id, nil
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type CategoryController interface {
+	CreateCategory(c *gin.Context)
+	GetCategoryTree(c *gin.Context)
+	GetCategoryById(c *gin.Context)
+	GetCategoryBooks(c *gin.Context)
+	UpdateCategoryById(c *gin.Context)
+	DeleteCategoryById(c *gin.Context)
+}
+
+type categoryController struct {
+	categoryService service.CategoryService
+	bookService     service.BookService
+}
+
+func NewCategoryController(categoryService service.CategoryService, bookService service.BookService) CategoryController {
+	return &categoryController{categoryService: categoryService, bookService: bookService}
+}
+
+func (cc *categoryController) CreateCategory(c *gin.Context) {
+
+	var category request.CreateCategory
+
+	err := c.ShouldBind(&category)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	categoryResponse, err := cc.categoryService.Save(category)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseCategory{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "category.created"),
+		Data:       categoryResponse,
+	})
+}
+
+func (cc *categoryController) GetCategoryTree(c *gin.Context) {
+	tree, err := cc.categoryService.FindTree()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseCategory{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "category.fetched"),
+		Data:       tree,
+	})
+}
+
+func (cc *categoryController) GetCategoryById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	category, err := cc.categoryService.FindById(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseCategory{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "category.fetched"),
+		Data:       category,
+	})
+}
+
+// GetCategoryBooks lists the books of a category including the books of all
+// of its sub categories, with the same query parameters as GET /books.
+func (cc *categoryController) GetCategoryBooks(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var query request.BookQuery
+
+	err = c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	_, err = cc.categoryService.FindById(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query.CategoryId = id
+
+	books, pagination, err := cc.bookService.FindAll(query)
+	if err != nil {
+
+		if errors.Is(err, service.ErrBookEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseBook{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "book.empty"),
+				Data:       books,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseBooks{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "book.fetched"),
+		Pagination: *pagination,
+		Data:       books,
+	})
+}
+
+func (cc *categoryController) UpdateCategoryById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var category request.UpdateCategory
+
+	err = c.ShouldBind(&category)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	categoryResponse, err := cc.categoryService.UpdateById(id, category)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseCategory{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "category.updated"),
+		Data:       categoryResponse,
+	})
+}
+
+func (cc *categoryController) DeleteCategoryById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	category, err := cc.categoryService.DeleteById(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseCategory{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "category.deleted"),
+		Data:       category,
+	})
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+// tagHashSize is the number of bytes of the body hash kept in a tag.
+const tagHashSize = 8
+
+// representationTag is the entity tag of a rendered book or author, the one
+// tag every response carries. The body also carries available copies and the
+// names of related rows, which change without bumping the version, so the tag
+// adds a hash of the body to the version. If-Match still only compares the
+// version part.
+func representationTag(version int, data interface{}) string {
+	// the bodies are plain structs, marshalling them does not fail
+	body, _ := json.Marshal(data)
+
+	sum := sha256.Sum256(body)
+
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:tagHashSize]) + `"`
+}
+
+// ifMatch reads the If-Match header of an update or delete as the version
+// the client last read, 0 when the header is missing or "*". Only a tag
+// handed out by representationTag can match, anything else fails the
+// precondition.
+func ifMatch(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	version, ok := parseEtag(header)
+	if !ok {
+		return 0, repository.ErrVersionMismatch
+	}
+
+	return version, nil
+}
+
+// notModified answers a GET with 304 when If-None-Match holds the tag of the
+// body about to be rendered, the client's copy is still fresh.
+func notModified(c *gin.Context, version int, data interface{}) bool {
+	current := representationTag(version, data)
+	c.Header("ETag", current)
+
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+		if tag == "*" || tag == current {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseEtag reads the version of a tag from representationTag. A tag in any
+// other shape, a bare version or a version with something else than the
+// hash, was not handed out by the API and is refused.
+func parseEtag(tag string) (int, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	value, hash, found := strings.Cut(tag[1:len(tag)-1], "-")
+	if !found || len(hash) != hex.EncodedLen(tagHashSize) {
+		return 0, false
+	}
+
+	_, err := hex.DecodeString(hash)
+	if err != nil {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+
+	return version, true
+}
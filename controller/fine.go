@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type FineController interface {
+	GetMyFines(c *gin.Context)
+	RecordPayment(c *gin.Context)
+	Waive(c *gin.Context)
+}
+
+type fineController struct {
+	fineService service.FineService
+}
+
+func NewFineController(fineService service.FineService) FineController {
+	return &fineController{fineService: fineService}
+}
+
+func (fc *fineController) GetMyFines(c *gin.Context) {
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	fines, err := fc.fineService.FindByUsername(claims.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseFine{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "fine.fetched"),
+		Data:       fines,
+	})
+}
+
+func (fc *fineController) RecordPayment(c *gin.Context) {
+
+	var entry request.FineEntry
+
+	err := c.ShouldBind(&entry)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	fine, err := fc.fineService.RecordPayment(claims.Username, entry)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseFine{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "fine.paid"),
+		Data:       fine,
+	})
+}
+
+func (fc *fineController) Waive(c *gin.Context) {
+
+	var entry request.FineEntry
+
+	err := c.ShouldBind(&entry)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	fine, err := fc.fineService.Waive(claims.Username, entry)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseFine{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "fine.waived"),
+		Data:       fine,
+	})
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type HoldController interface {
+	PlaceHold(c *gin.Context)
+	CancelHold(c *gin.Context)
+	GetMyHolds(c *gin.Context)
+}
+
+type holdController struct {
+	holdService service.HoldService
+}
+
+func NewHoldController(holdService service.HoldService) HoldController {
+	return &holdController{holdService: holdService}
+}
+
+func (hc *holdController) PlaceHold(c *gin.Context) {
+	bookId, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	hold, err := hc.holdService.PlaceHold(claims.Username, bookId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseHold{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "hold.created"),
+		Data:       hold,
+	})
+}
+
+func (hc *holdController) CancelHold(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	hold, err := hc.holdService.Cancel(claims.Username, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseHold{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "hold.cancelled"),
+		Data:       hold,
+	})
+}
+
+func (hc *holdController) GetMyHolds(c *gin.Context) {
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	holds, err := hc.holdService.FindByUsername(claims.Username)
+	if err != nil {
+		if errors.Is(err, service.ErrHoldEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseHold{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "hold.empty"),
+				Data:       holds,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseHold{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "hold.fetched"),
+		Data:       holds,
+	})
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/isbn"
+)
+
+type IsbnController interface {
+	Validate(c *gin.Context)
+}
+
+type isbnController struct{}
+
+func NewIsbnController() IsbnController {
+	return &isbnController{}
+}
+
+// Validate reports whether the value is a valid ISBN together with its
+// ISBN-13 and, when it exists, ISBN-10 form. An invalid ISBN is not an error
+// of the request, so it is answered with 200 and valid set to false.
+func (ic *isbnController) Validate(c *gin.Context) {
+	value := c.Param("value")
+
+	validation := response.IsbnValidation{Input: value}
+
+	isbn13, err := isbn.Normalize(value)
+	if err != nil {
+		validation.Error = message(c, isbn.MessageKey(err))
+
+		c.JSON(http.StatusOK, response.WebResponseIsbn{
+			StatusCode: http.StatusOK,
+			Message:    message(c, "isbn.invalid"),
+			Data:       validation,
+		})
+		return
+	}
+
+	validation.Valid, validation.Isbn13 = true, isbn13
+	validation.Isbn10, _ = isbn.To10(isbn13)
+
+	c.JSON(http.StatusOK, response.WebResponseIsbn{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "isbn.valid"),
+		Data:       validation,
+	})
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/jwtkey"
+)
+
+type JwksController interface {
+	GetJwks(ctx *gin.Context)
+}
+
+type jwksController struct {
+	keys *jwtkey.KeySet
+}
+
+func NewJwksController(keys *jwtkey.KeySet) JwksController {
+	return &jwksController{keys: keys}
+}
+
+func (jc *jwksController) GetJwks(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+
+	ctx.JSON(http.StatusOK, jc.keys.JWKS())
+}
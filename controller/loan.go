@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type LoanController interface {
+	Checkout(c *gin.Context)
+	Return(c *gin.Context)
+	Renew(c *gin.Context)
+	GetMyLoans(c *gin.Context)
+}
+
+type loanController struct {
+	loanService service.LoanService
+}
+
+func NewLoanController(loanService service.LoanService) LoanController {
+	return &loanController{loanService: loanService}
+}
+
+func (lc *loanController) Checkout(c *gin.Context) {
+
+	var loan request.CreateLoan
+
+	err := c.ShouldBind(&loan)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	if loan.UserId != 0 && !claims.HasRole(data.StaffRoles...) {
+		c.Error(apperror.Forbidden("loan.member_self_only"))
+		return
+	}
+
+	loanResponse, err := lc.loanService.Checkout(claims.Username, loan)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponseLoan{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "loan.created"),
+		Data:       loanResponse,
+	})
+}
+
+func (lc *loanController) Return(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	loan, err := lc.loanService.Return(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseLoan{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "loan.returned"),
+		Data:       loan,
+	})
+}
+
+func (lc *loanController) Renew(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	loan, err := lc.loanService.Renew(actor(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseLoan{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "loan.renewed"),
+		Data:       loan,
+	})
+}
+
+func (lc *loanController) GetMyLoans(c *gin.Context) {
+
+	claims := c.MustGet("claims").(*data.Claims)
+
+	loans, err := lc.loanService.FindByUsername(claims.Username)
+	if err != nil {
+		if errors.Is(err, service.ErrLoanEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseLoan{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "loan.empty"),
+				Data:       loans,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseLoan{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "loan.fetched"),
+		Data:       loans,
+	})
+}
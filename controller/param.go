@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+// paramId reads a numeric path parameter. A value that is not a number is
+// a malformed request and never reaches the service.
+func paramId(c *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		return 0, apperror.BadRequest("request.param_not_number", apperror.FieldError{Field: name, Code: "numeric", Key: "request.not_number"}).With(i18n.Params{"name": name})
+	}
+
+	return id, nil
+}
+
+// message translates key into the language of the request.
+func message(c *gin.Context, key string, params ...i18n.Params) string {
+	return i18n.T(c.GetString(i18n.ContextKey), key, params...)
+}
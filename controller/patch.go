@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"mime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/mergepatch"
+)
+
+// mergePatch reads the body of a PATCH request. Besides the merge patch
+// media type, plain application/json is accepted for clients that cannot
+// set it.
+func mergePatch(c *gin.Context) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != mergepatch.ContentType && mediaType != "application/json" {
+		return nil, apperror.MediaType("request.patch_media_type")
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		return nil, apperror.Malformed(err)
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type PublisherController interface {
+	CreatePublisher(c *gin.Context)
+	GetAllPublisher(c *gin.Context)
+	GetPublisherById(c *gin.Context)
+	DeletePublisherById(c *gin.Context)
+	UpdatePublisherById(c *gin.Context)
+}
+
+type publisherController struct {
+	publisherService service.PublisherService
+}
+
+func NewPublisherController(publisherService service.PublisherService) PublisherController {
+	return &publisherController{publisherService: publisherService}
+}
+
+func (pc *publisherController) CreatePublisher(c *gin.Context) {
+
+	var publisher request.CreatePublisher
+
+	err := c.ShouldBind(&publisher)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	publisherResponse, err := pc.publisherService.Save(publisher)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.WebResponsePublisher{
+		StatusCode: http.StatusCreated,
+		Message:    message(c, "publisher.created"),
+		Data:       publisherResponse,
+	})
+}
+
+func (pc *publisherController) GetAllPublisher(c *gin.Context) {
+
+	var query request.PublisherQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	publishers, pagination, err := pc.publisherService.FindAll(query)
+	if err != nil {
+		if errors.Is(err, service.ErrPublisherEmpty) {
+			c.JSON(http.StatusOK, response.WebResponsePublisher{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "publisher.empty"),
+				Data:       publishers,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponsePublishers{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "publisher.listed"),
+		Pagination: *pagination,
+		Data:       publishers,
+	})
+}
+
+func (pc *publisherController) GetPublisherById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	publisher, err := pc.publisherService.FindById(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponsePublisher{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "publisher.fetched"),
+		Data:       publisher,
+	})
+}
+
+func (pc *publisherController) DeletePublisherById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	publisher, err := pc.publisherService.DeleteById(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponsePublisher{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "publisher.deleted"),
+		Data:       publisher,
+	})
+}
+
+func (pc *publisherController) UpdatePublisherById(c *gin.Context) {
+	id, err := paramId(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var publisher request.UpdatePublisher
+
+	err = c.ShouldBind(&publisher)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	publisherResponse, err := pc.publisherService.UpdateById(id, publisher)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponsePublisher{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "publisher.updated"),
+		Data:       publisherResponse,
+	})
+}
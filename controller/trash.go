@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/service"
+)
+
+type TrashController interface {
+	GetTrash(c *gin.Context)
+	PurgeTrash(c *gin.Context)
+}
+
+type trashController struct {
+	trashService service.TrashService
+}
+
+func NewTrashController(trashService service.TrashService) TrashController {
+	return &trashController{trashService: trashService}
+}
+
+func (tc *trashController) GetTrash(c *gin.Context) {
+	var query request.TrashQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		c.Error(apperror.Malformed(err))
+		return
+	}
+
+	items, pagination, err := tc.trashService.FindAll(query)
+	if err != nil {
+		if errors.Is(err, service.ErrTrashEmpty) {
+			c.JSON(http.StatusOK, response.WebResponseTrash{
+				StatusCode: http.StatusOK,
+				Message:    message(c, "trash.empty"),
+				Data:       items,
+			})
+			return
+		}
+
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseTrash{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "trash.fetched"),
+		Pagination: pagination,
+		Data:       items,
+	})
+}
+
+func (tc *trashController) PurgeTrash(c *gin.Context) {
+	purged, err := tc.trashService.Purge()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.WebResponseTrash{
+		StatusCode: http.StatusOK,
+		Message:    message(c, "trash.purged"),
+		Data:       purged,
+	})
+}
@@ -1,10 +1,11 @@
 package controller
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
 	"github.com/ilhaamms/library-api/service"
@@ -13,6 +14,10 @@ import (
 type UserController interface {
 	Register(ctx *gin.Context)
 	Login(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+	Logout(ctx *gin.Context)
+	UpdateCategory(ctx *gin.Context)
+	UpdateRole(ctx *gin.Context)
 }
 
 type userController struct {
@@ -29,25 +34,19 @@ func (uc *userController) Register(ctx *gin.Context) {
 
 	err := ctx.ShouldBind(&user)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		ctx.Error(apperror.Malformed(err))
 		return
 	}
 
-	dataUser, err := uc.userService.Save(user)
+	dataUser, err := uc.userService.Save(actor(ctx), user)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, response.WebResponseUser{
 		StatusCode: http.StatusCreated,
-		Message:    "registrasi user berhasil",
+		Message:    message(ctx, "user.registered"),
 		Data:       dataUser,
 	})
 
@@ -59,27 +58,18 @@ func (uc *userController) Login(ctx *gin.Context) {
 
 	err := ctx.ShouldBind(&user)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		ctx.Error(apperror.Malformed(err))
 		return
 	}
 
 	isLogin, dataUser, err := uc.userService.Login(user)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      fmt.Sprintf("error : %v", err.Error()),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	if !isLogin {
-		ctx.JSON(http.StatusBadRequest, response.ErrorResponse{
-			StatusCode: http.StatusBadRequest,
-			Error:      "username atau password salah",
-		})
+		ctx.Error(apperror.Unauthorized("user.login_failed"))
 		return
 	}
 
@@ -92,7 +82,101 @@ func (uc *userController) Login(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response.WebResponseUser{
 		StatusCode: http.StatusOK,
-		Message:    "login berhasil",
+		Message:    message(ctx, "user.logged_in"),
+		Data:       dataUser,
+	})
+}
+
+func (uc *userController) Refresh(ctx *gin.Context) {
+
+	var refresh request.Refresh
+
+	err := ctx.ShouldBind(&refresh)
+	if err != nil {
+		ctx.Error(apperror.Malformed(err))
+		return
+	}
+
+	dataUser, err := uc.userService.Refresh(refresh)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.WebResponseUser{
+		StatusCode: http.StatusOK,
+		Message:    message(ctx, "user.refreshed"),
+		Data:       dataUser,
+	})
+}
+
+func (uc *userController) Logout(ctx *gin.Context) {
+	claims := ctx.MustGet("claims").(*data.Claims)
+
+	err := uc.userService.Logout(claims)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.WebResponseUser{
+		StatusCode: http.StatusOK,
+		Message:    message(ctx, "user.logged_out"),
+	})
+}
+
+func (uc *userController) UpdateCategory(ctx *gin.Context) {
+	id, err := paramId(ctx, "id")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var user request.UpdateUserCategory
+
+	err = ctx.ShouldBind(&user)
+	if err != nil {
+		ctx.Error(apperror.Malformed(err))
+		return
+	}
+
+	dataUser, err := uc.userService.UpdateCategory(actor(ctx), id, user)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.WebResponseUser{
+		StatusCode: http.StatusOK,
+		Message:    message(ctx, "user.category_updated"),
+		Data:       dataUser,
+	})
+}
+
+func (uc *userController) UpdateRole(ctx *gin.Context) {
+	id, err := paramId(ctx, "id")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var user request.UpdateUserRole
+
+	err = ctx.ShouldBind(&user)
+	if err != nil {
+		ctx.Error(apperror.Malformed(err))
+		return
+	}
+
+	dataUser, err := uc.userService.UpdateRole(actor(ctx), id, user)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.WebResponseUser{
+		StatusCode: http.StatusOK,
+		Message:    message(ctx, "user.role_updated"),
 		Data:       dataUser,
 	})
 }
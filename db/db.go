@@ -0,0 +1,88 @@
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/ilhaamms/library-api/config"
+	"gorm.io/gorm"
+)
+
+// Migrations holds the SQL files in db/migrations, one directory per
+// database driver, so the binary can migrate the schema without the migrate
+// CLI or the source tree.
+//
+//go:embed migrations
+var Migrations embed.FS
+
+func MigrationsFor(driver string) (fs.FS, error) {
+	migrations, err := fs.Sub(Migrations, "migrations/"+driver)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fs.ReadDir(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrasi untuk driver %s tidak ditemukan", driver)
+	}
+
+	return migrations, nil
+}
+
+// ErrNoFTS5 is returned for a SQLite driver built without FTS5, the book
+// search migration and /search need it.
+var ErrNoFTS5 = errors.New("driver sqlite tidak mendukung FTS5, build ulang dengan -tags sqlite_fts5")
+
+// FTS5Available reports whether database is SQLite with a driver built with
+// FTS5. The probe table lives in the temp schema of one connection, so it is
+// created and dropped on the same pinned connection.
+func FTS5Available(database *gorm.DB) (bool, error) {
+	if database.Dialector.Name() != config.DriverSQLite {
+		return false, nil
+	}
+
+	available := false
+
+	err := database.Connection(func(conn *gorm.DB) error {
+		err := conn.Exec("CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(x)").Error
+		if err != nil && strings.Contains(err.Error(), "no such module") {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		available = true
+
+		return conn.Exec("DROP TABLE temp.fts5_probe").Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("gagal memeriksa dukungan FTS5 : %w", err)
+	}
+
+	return available, nil
+}
+
+// RequireFTS5 fails with ErrNoFTS5 when database is SQLite without FTS5, so
+// a binary built without the tag stops at start with a clear message
+// instead of at the book search migration.
+func RequireFTS5(database *gorm.DB) error {
+	if database.Dialector.Name() != config.DriverSQLite {
+		return nil
+	}
+
+	available, err := FTS5Available(database)
+	if err != nil {
+		return err
+	}
+
+	if !available {
+		return ErrNoFTS5
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+CREATE TABLE author (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    birth_date DATE NOT NULL
+);
@@ -0,0 +1,7 @@
+CREATE TABLE book (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    isbn VARCHAR(32) NOT NULL UNIQUE,
+    author_id INT NOT NULL,
+    FOREIGN KEY (author_id) REFERENCES author(id)
+);
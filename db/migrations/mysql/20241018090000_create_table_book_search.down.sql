@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS book_search_after_update_author;
+DROP TRIGGER IF EXISTS book_search_after_update;
+DROP TRIGGER IF EXISTS book_search_after_insert;
+DROP TABLE IF EXISTS book_search;
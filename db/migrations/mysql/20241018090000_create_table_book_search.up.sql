@@ -0,0 +1,24 @@
+CREATE TABLE book_search (
+    book_id INT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    isbn VARCHAR(32) NOT NULL,
+    author_name VARCHAR(255) NOT NULL,
+    FULLTEXT INDEX idx_book_search_document (title, isbn, author_name),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+INSERT INTO book_search (book_id, title, isbn, author_name)
+SELECT b.id, b.title, b.isbn, a.name FROM book AS b INNER JOIN author AS a ON b.author_id = a.id;
+
+CREATE TRIGGER book_search_after_insert AFTER INSERT ON book FOR EACH ROW
+    INSERT INTO book_search (book_id, title, isbn, author_name)
+    VALUES (NEW.id, NEW.title, NEW.isbn, (SELECT name FROM author WHERE id = NEW.author_id));
+
+CREATE TRIGGER book_search_after_update AFTER UPDATE ON book FOR EACH ROW
+    UPDATE book_search
+    SET title = NEW.title, isbn = NEW.isbn, author_name = (SELECT name FROM author WHERE id = NEW.author_id)
+    WHERE book_id = NEW.id;
+
+CREATE TRIGGER book_search_after_update_author AFTER UPDATE ON author FOR EACH ROW
+    UPDATE book_search SET author_name = NEW.name
+    WHERE book_id IN (SELECT id FROM book WHERE author_id = NEW.id);
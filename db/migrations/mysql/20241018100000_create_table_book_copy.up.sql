@@ -0,0 +1,13 @@
+CREATE TABLE book_copy (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    book_id INT NOT NULL,
+    barcode VARCHAR(64) NOT NULL UNIQUE,
+    branch VARCHAR(255) NOT NULL,
+    shelf VARCHAR(255) NOT NULL DEFAULT '',
+    `condition` VARCHAR(32) NOT NULL DEFAULT 'good',
+    acquisition_date DATE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'available',
+    CONSTRAINT book_copy_status_check CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair')),
+    INDEX idx_book_copy_book_id (book_id),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
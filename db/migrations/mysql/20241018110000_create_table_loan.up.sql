@@ -0,0 +1,16 @@
+-- MySQL has no partial indexes, the generated active_copy_id is NULL once a
+-- loan is returned so the unique index only covers active loans.
+CREATE TABLE loan (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    copy_id INT NOT NULL,
+    user_id INT NOT NULL,
+    loaned_at DATETIME(6) NOT NULL,
+    due_date DATETIME(6) NOT NULL,
+    returned_at DATETIME(6),
+    renewal_count INT NOT NULL DEFAULT 0,
+    active_copy_id INT AS (CASE WHEN returned_at IS NULL THEN copy_id END) STORED,
+    INDEX idx_loan_user_id (user_id),
+    UNIQUE INDEX idx_loan_active_copy (active_copy_id),
+    FOREIGN KEY (copy_id) REFERENCES book_copy(id),
+    FOREIGN KEY (user_id) REFERENCES `user`(id)
+);
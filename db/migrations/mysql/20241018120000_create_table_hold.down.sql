@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS hold;
+
+UPDATE book_copy SET status = 'available' WHERE status = 'reserved';
+
+ALTER TABLE book_copy DROP CHECK book_copy_status_check;
+
+ALTER TABLE book_copy ADD CONSTRAINT book_copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair'));
@@ -0,0 +1,22 @@
+ALTER TABLE book_copy DROP CHECK book_copy_status_check;
+
+ALTER TABLE book_copy ADD CONSTRAINT book_copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'reserved', 'lost', 'in_repair'));
+
+CREATE TABLE hold (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    book_id INT NOT NULL,
+    user_id INT NOT NULL,
+    copy_id INT,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    created_at DATETIME(6) NOT NULL,
+    ready_at DATETIME(6),
+    expires_at DATETIME(6),
+    active_user_id INT AS (CASE WHEN status IN ('waiting', 'ready') THEN user_id END) STORED,
+    INDEX idx_hold_book_status (book_id, status),
+    INDEX idx_hold_user_id (user_id),
+    UNIQUE INDEX idx_hold_active_user_book (book_id, active_user_id),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES `user`(id),
+    FOREIGN KEY (copy_id) REFERENCES book_copy(id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fine_ledger;
+
+ALTER TABLE `user` DROP CHECK user_category_check, DROP COLUMN category;
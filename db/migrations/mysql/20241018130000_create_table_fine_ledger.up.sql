@@ -0,0 +1,18 @@
+ALTER TABLE `user` ADD COLUMN category VARCHAR(16) NOT NULL DEFAULT 'regular',
+    ADD CONSTRAINT user_category_check CHECK (category IN ('regular', 'student', 'senior'));
+
+CREATE TABLE fine_ledger (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    loan_id INT,
+    entry_type VARCHAR(16) NOT NULL CHECK (entry_type IN ('charge', 'payment', 'waiver')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    recorded_by INT,
+    created_at DATETIME(6) NOT NULL,
+    INDEX idx_fine_ledger_user_id (user_id),
+    INDEX idx_fine_ledger_loan_id (loan_id),
+    FOREIGN KEY (user_id) REFERENCES `user`(id),
+    FOREIGN KEY (loan_id) REFERENCES loan(id),
+    FOREIGN KEY (recorded_by) REFERENCES `user`(id)
+);
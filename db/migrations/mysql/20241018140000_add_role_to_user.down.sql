@@ -0,0 +1 @@
+ALTER TABLE `user` DROP CHECK user_role_check, DROP COLUMN role;
@@ -0,0 +1,6 @@
+ALTER TABLE `user` ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'member',
+    ADD CONSTRAINT user_role_check CHECK (role IN ('admin', 'librarian', 'member'));
+
+UPDATE `user` AS u
+INNER JOIN (SELECT MIN(id) AS id FROM `user`) AS first_user ON first_user.id = u.id
+SET u.role = 'admin';
@@ -0,0 +1,20 @@
+CREATE TABLE refresh_token (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    access_jti VARCHAR(64) NOT NULL,
+    access_expires_at DATETIME(6) NOT NULL,
+    expires_at DATETIME(6) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    revoked_at DATETIME(6),
+    replaced_by INT,
+    INDEX idx_refresh_token_family_id (family_id),
+    FOREIGN KEY (user_id) REFERENCES `user`(id) ON DELETE CASCADE,
+    FOREIGN KEY (replaced_by) REFERENCES refresh_token(id)
+);
+
+CREATE TABLE revoked_token (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at DATETIME(6) NOT NULL
+);
@@ -0,0 +1,14 @@
+CREATE TABLE book_contributor (
+    book_id INT NOT NULL,
+    author_id INT NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'author',
+    position INT NOT NULL,
+    CONSTRAINT book_contributor_role_check CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    PRIMARY KEY (book_id, author_id, role),
+    INDEX idx_book_contributor_author_id (author_id),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES author(id)
+);
+
+INSERT INTO book_contributor (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1 FROM book;
@@ -0,0 +1,5 @@
+CREATE TABLE publisher (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    city VARCHAR(255) NOT NULL DEFAULT ''
+);
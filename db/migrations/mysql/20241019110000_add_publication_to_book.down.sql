@@ -0,0 +1,16 @@
+ALTER TABLE book
+    DROP FOREIGN KEY fk_book_work,
+    DROP FOREIGN KEY fk_book_publisher,
+    DROP CHECK book_format_check;
+
+ALTER TABLE book
+    DROP COLUMN work_id,
+    DROP COLUMN description,
+    DROP COLUMN format,
+    DROP COLUMN language,
+    DROP COLUMN page_count,
+    DROP COLUMN edition,
+    DROP COLUMN publication_year,
+    DROP COLUMN publisher_id;
+
+DROP TABLE IF EXISTS work;
@@ -0,0 +1,17 @@
+CREATE TABLE work (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL
+);
+
+ALTER TABLE book
+    ADD COLUMN publisher_id INT,
+    ADD COLUMN publication_year INT,
+    ADD COLUMN edition VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN page_count INT,
+    ADD COLUMN language VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN format VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN description TEXT NOT NULL,
+    ADD COLUMN work_id INT,
+    ADD CONSTRAINT book_format_check CHECK (format IN ('', 'hardcover', 'paperback', 'ebook', 'audio')),
+    ADD CONSTRAINT fk_book_publisher FOREIGN KEY (publisher_id) REFERENCES publisher(id),
+    ADD CONSTRAINT fk_book_work FOREIGN KEY (work_id) REFERENCES work(id);
@@ -0,0 +1,16 @@
+CREATE TABLE category (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    parent_id INT,
+    UNIQUE INDEX idx_category_parent_name (parent_id, name),
+    FOREIGN KEY (parent_id) REFERENCES category(id)
+);
+
+CREATE TABLE book_category (
+    book_id INT NOT NULL,
+    category_id INT NOT NULL,
+    PRIMARY KEY (book_id, category_id),
+    INDEX idx_book_category_category_id (category_id),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE author ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE book ADD COLUMN version INT NOT NULL DEFAULT 1;
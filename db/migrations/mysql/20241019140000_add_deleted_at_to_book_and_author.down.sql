@@ -0,0 +1,2 @@
+ALTER TABLE book DROP INDEX idx_book_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE author DROP INDEX idx_author_deleted_at, DROP COLUMN deleted_at;
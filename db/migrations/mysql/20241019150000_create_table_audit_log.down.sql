@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS audit_log_append_only;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,17 @@
+CREATE TABLE audit_log (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(6) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    actor_role VARCHAR(16) NOT NULL DEFAULT '',
+    entity VARCHAR(16) NOT NULL,
+    entity_id INT NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    changes TEXT NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_ip VARCHAR(45) NOT NULL DEFAULT '',
+    INDEX idx_audit_log_entity (entity, entity_id),
+    INDEX idx_audit_log_actor (actor)
+);
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE ON audit_log
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append only';
@@ -0,0 +1,2 @@
+-- The original ISBN forms are not kept, the canonical ISBN-13 stays.
+SELECT 1;
@@ -0,0 +1,13 @@
+-- Books are stored with the canonical ISBN-13 without separators. Rows saved
+-- before that are rewritten: separators are removed and a valid ISBN-10 gets
+-- the 978 prefix and a new check digit. Two rows that turn out to hold the
+-- same ISBN fail the unique index and stop the migration, merge them first.
+UPDATE book SET isbn = UPPER(REPLACE(REPLACE(isbn, '-', ''), ' ', ''))
+WHERE isbn <> UPPER(REPLACE(REPLACE(isbn, '-', ''), ' ', ''));
+
+UPDATE book SET isbn = CONCAT('978', SUBSTRING(isbn, 1, 9), MOD(10 - MOD(38
+    + 3 * (CAST(SUBSTRING(isbn, 1, 1) AS UNSIGNED) + CAST(SUBSTRING(isbn, 3, 1) AS UNSIGNED) + CAST(SUBSTRING(isbn, 5, 1) AS UNSIGNED)
+        + CAST(SUBSTRING(isbn, 7, 1) AS UNSIGNED) + CAST(SUBSTRING(isbn, 9, 1) AS UNSIGNED))
+    + CAST(SUBSTRING(isbn, 2, 1) AS UNSIGNED) + CAST(SUBSTRING(isbn, 4, 1) AS UNSIGNED) + CAST(SUBSTRING(isbn, 6, 1) AS UNSIGNED)
+    + CAST(SUBSTRING(isbn, 8, 1) AS UNSIGNED), 10), 10))
+WHERE isbn REGEXP '^[0-9]{9}[0-9X]$';
@@ -0,0 +1,5 @@
+CREATE TABLE author (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL,
+    birth_date DATE NOT NULL
+);
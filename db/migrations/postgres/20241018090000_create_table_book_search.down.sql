@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS book_search_after_update_author ON author;
+DROP TRIGGER IF EXISTS book_search_after_write ON book;
+DROP FUNCTION IF EXISTS book_search_sync_author();
+DROP FUNCTION IF EXISTS book_search_sync_book();
+DROP TABLE IF EXISTS book_search;
@@ -0,0 +1,43 @@
+CREATE TABLE book_search (
+    book_id INTEGER PRIMARY KEY,
+    title TEXT NOT NULL,
+    isbn TEXT NOT NULL,
+    author_name TEXT NOT NULL,
+    document TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', isbn), 'B') ||
+        setweight(to_tsvector('simple', author_name), 'C')
+    ) STORED,
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_search_document ON book_search USING GIN (document);
+
+INSERT INTO book_search (book_id, title, isbn, author_name)
+SELECT b.id, b.title, b.isbn, a.name FROM book AS b INNER JOIN author AS a ON b.author_id = a.id;
+
+CREATE FUNCTION book_search_sync_book() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO book_search (book_id, title, isbn, author_name)
+    VALUES (NEW.id, NEW.title, NEW.isbn, (SELECT name FROM author WHERE id = NEW.author_id))
+    ON CONFLICT (book_id) DO UPDATE
+    SET title = EXCLUDED.title, isbn = EXCLUDED.isbn, author_name = EXCLUDED.author_name;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER book_search_after_write AFTER INSERT OR UPDATE ON book
+FOR EACH ROW EXECUTE FUNCTION book_search_sync_book();
+
+CREATE FUNCTION book_search_sync_author() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE book_search SET author_name = NEW.name
+    WHERE book_id IN (SELECT id FROM book WHERE author_id = NEW.id);
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER book_search_after_update_author AFTER UPDATE OF name ON author
+FOR EACH ROW EXECUTE FUNCTION book_search_sync_author();
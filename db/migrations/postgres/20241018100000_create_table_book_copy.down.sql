@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_book_copy_book_id;
+DROP TABLE IF EXISTS book_copy;
@@ -0,0 +1,14 @@
+CREATE TABLE book_copy (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    book_id INTEGER NOT NULL,
+    barcode TEXT NOT NULL UNIQUE,
+    branch TEXT NOT NULL,
+    shelf TEXT NOT NULL DEFAULT '',
+    condition TEXT NOT NULL DEFAULT 'good',
+    acquisition_date DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'available',
+    CONSTRAINT book_copy_status_check CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair')),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_copy_book_id ON book_copy (book_id);
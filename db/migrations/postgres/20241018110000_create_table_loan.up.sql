@@ -0,0 +1,15 @@
+CREATE TABLE loan (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    copy_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    loaned_at TIMESTAMPTZ NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    returned_at TIMESTAMPTZ,
+    renewal_count INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (copy_id) REFERENCES book_copy(id),
+    FOREIGN KEY (user_id) REFERENCES "user"(id)
+);
+
+CREATE INDEX idx_loan_user_id ON loan (user_id);
+
+CREATE UNIQUE INDEX idx_loan_active_copy ON loan (copy_id) WHERE returned_at IS NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_hold_active_user_book;
+DROP INDEX IF EXISTS idx_hold_user_id;
+DROP INDEX IF EXISTS idx_hold_book_status;
+DROP TABLE IF EXISTS hold;
+
+UPDATE book_copy SET status = 'available' WHERE status = 'reserved';
+
+ALTER TABLE book_copy DROP CONSTRAINT book_copy_status_check;
+
+ALTER TABLE book_copy ADD CONSTRAINT book_copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair'));
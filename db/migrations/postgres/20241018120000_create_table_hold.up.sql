@@ -0,0 +1,24 @@
+ALTER TABLE book_copy DROP CONSTRAINT book_copy_status_check;
+
+ALTER TABLE book_copy ADD CONSTRAINT book_copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'reserved', 'lost', 'in_repair'));
+
+CREATE TABLE hold (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    book_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    copy_id INTEGER,
+    status TEXT NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    created_at TIMESTAMPTZ NOT NULL,
+    ready_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id),
+    FOREIGN KEY (copy_id) REFERENCES book_copy(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_hold_book_status ON hold (book_id, status);
+
+CREATE INDEX idx_hold_user_id ON hold (user_id);
+
+CREATE UNIQUE INDEX idx_hold_active_user_book ON hold (book_id, user_id) WHERE status IN ('waiting', 'ready');
@@ -0,0 +1,3 @@
+ALTER TABLE "user" ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'librarian', 'member'));
+
+UPDATE "user" SET role = 'admin' WHERE id = (SELECT MIN(id) FROM "user");
@@ -0,0 +1,15 @@
+CREATE TABLE book_contributor (
+    book_id INTEGER NOT NULL,
+    author_id INTEGER NOT NULL,
+    role TEXT NOT NULL DEFAULT 'author',
+    position INTEGER NOT NULL,
+    CONSTRAINT book_contributor_role_check CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    PRIMARY KEY (book_id, author_id, role),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES author(id)
+);
+
+CREATE INDEX idx_book_contributor_author_id ON book_contributor (author_id);
+
+INSERT INTO book_contributor (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1 FROM book;
@@ -0,0 +1,5 @@
+CREATE TABLE publisher (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    city TEXT NOT NULL DEFAULT ''
+);
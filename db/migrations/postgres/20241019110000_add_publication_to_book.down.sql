@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_book_work_id;
+DROP INDEX IF EXISTS idx_book_publisher_id;
+
+ALTER TABLE book DROP CONSTRAINT IF EXISTS book_format_check;
+ALTER TABLE book DROP COLUMN IF EXISTS work_id;
+ALTER TABLE book DROP COLUMN IF EXISTS description;
+ALTER TABLE book DROP COLUMN IF EXISTS format;
+ALTER TABLE book DROP COLUMN IF EXISTS language;
+ALTER TABLE book DROP COLUMN IF EXISTS page_count;
+ALTER TABLE book DROP COLUMN IF EXISTS edition;
+ALTER TABLE book DROP COLUMN IF EXISTS publication_year;
+ALTER TABLE book DROP COLUMN IF EXISTS publisher_id;
+
+DROP TABLE IF EXISTS work;
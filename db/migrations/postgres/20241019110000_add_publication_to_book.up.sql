@@ -0,0 +1,18 @@
+CREATE TABLE work (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title TEXT NOT NULL
+);
+
+ALTER TABLE book ADD COLUMN publisher_id INTEGER REFERENCES publisher(id);
+ALTER TABLE book ADD COLUMN publication_year INTEGER;
+ALTER TABLE book ADD COLUMN edition TEXT NOT NULL DEFAULT '';
+ALTER TABLE book ADD COLUMN page_count INTEGER;
+ALTER TABLE book ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE book ADD COLUMN format TEXT NOT NULL DEFAULT '';
+ALTER TABLE book ADD CONSTRAINT book_format_check CHECK (format IN ('', 'hardcover', 'paperback', 'ebook', 'audio'));
+ALTER TABLE book ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE book ADD COLUMN work_id INTEGER REFERENCES work(id);
+
+CREATE INDEX idx_book_publisher_id ON book (publisher_id);
+
+CREATE INDEX idx_book_work_id ON book (work_id);
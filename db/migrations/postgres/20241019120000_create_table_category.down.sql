@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_book_category_category_id;
+DROP TABLE IF EXISTS book_category;
+DROP INDEX IF EXISTS idx_category_parent_name;
+DROP TABLE IF EXISTS category;
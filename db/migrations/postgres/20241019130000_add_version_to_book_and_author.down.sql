@@ -0,0 +1,2 @@
+ALTER TABLE book DROP COLUMN IF EXISTS version;
+ALTER TABLE author DROP COLUMN IF EXISTS version;
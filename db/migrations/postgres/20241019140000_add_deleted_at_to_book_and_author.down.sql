@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_book_deleted_at;
+DROP INDEX IF EXISTS idx_author_deleted_at;
+
+ALTER TABLE book DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE author DROP COLUMN IF EXISTS deleted_at;
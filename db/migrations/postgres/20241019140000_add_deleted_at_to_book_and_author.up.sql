@@ -0,0 +1,5 @@
+ALTER TABLE author ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE book ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_author_deleted_at ON author (deleted_at);
+CREATE INDEX idx_book_deleted_at ON book (deleted_at);
@@ -0,0 +1,13 @@
+-- Books are stored with the canonical ISBN-13 without separators. Rows saved
+-- before that are rewritten: separators are removed and a valid ISBN-10 gets
+-- the 978 prefix and a new check digit. Two rows that turn out to hold the
+-- same ISBN fail the unique index and stop the migration, merge them first.
+UPDATE book SET isbn = UPPER(REPLACE(REPLACE(isbn, '-', ''), ' ', ''))
+WHERE isbn <> UPPER(REPLACE(REPLACE(isbn, '-', ''), ' ', ''));
+
+UPDATE book SET isbn = '978' || substr(isbn, 1, 9) || ((10 - (38
+    + 3 * (CAST(substr(isbn, 1, 1) AS INTEGER) + CAST(substr(isbn, 3, 1) AS INTEGER) + CAST(substr(isbn, 5, 1) AS INTEGER)
+        + CAST(substr(isbn, 7, 1) AS INTEGER) + CAST(substr(isbn, 9, 1) AS INTEGER))
+    + CAST(substr(isbn, 2, 1) AS INTEGER) + CAST(substr(isbn, 4, 1) AS INTEGER) + CAST(substr(isbn, 6, 1) AS INTEGER)
+    + CAST(substr(isbn, 8, 1) AS INTEGER)) % 10) % 10)::text
+WHERE isbn ~ '^[0-9]{9}[0-9X]$';
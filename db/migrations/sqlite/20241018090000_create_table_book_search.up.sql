@@ -0,0 +1,29 @@
+CREATE VIRTUAL TABLE book_search USING fts5(
+    title,
+    isbn,
+    author_name,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO book_search (rowid, title, isbn, author_name)
+SELECT b.id, b.title, b.isbn, a.name FROM book AS b INNER JOIN author AS a ON b.author_id = a.id;
+
+CREATE TRIGGER book_search_after_insert AFTER INSERT ON book BEGIN
+    INSERT INTO book_search (rowid, title, isbn, author_name)
+    VALUES (new.id, new.title, new.isbn, (SELECT name FROM author WHERE id = new.author_id));
+END;
+
+CREATE TRIGGER book_search_after_update AFTER UPDATE ON book BEGIN
+    DELETE FROM book_search WHERE rowid = old.id;
+    INSERT INTO book_search (rowid, title, isbn, author_name)
+    VALUES (new.id, new.title, new.isbn, (SELECT name FROM author WHERE id = new.author_id));
+END;
+
+CREATE TRIGGER book_search_after_delete AFTER DELETE ON book BEGIN
+    DELETE FROM book_search WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER book_search_after_update_author AFTER UPDATE OF name ON author BEGIN
+    UPDATE book_search SET author_name = new.name
+    WHERE rowid IN (SELECT id FROM book WHERE author_id = new.id);
+END;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_loan_active_copy;
+DROP INDEX IF EXISTS idx_loan_user_id;
+DROP TABLE IF EXISTS loan;
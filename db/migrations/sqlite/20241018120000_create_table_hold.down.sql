@@ -0,0 +1,31 @@
+PRAGMA foreign_keys = OFF;
+
+DROP INDEX IF EXISTS idx_hold_active_user_book;
+DROP INDEX IF EXISTS idx_hold_user_id;
+DROP INDEX IF EXISTS idx_hold_book_status;
+DROP TABLE IF EXISTS hold;
+
+CREATE TABLE book_copy_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    book_id INTEGER NOT NULL,
+    barcode TEXT NOT NULL UNIQUE,
+    branch TEXT NOT NULL,
+    shelf TEXT NOT NULL DEFAULT '',
+    condition TEXT NOT NULL DEFAULT 'good',
+    acquisition_date DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair')),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+INSERT INTO book_copy_old
+SELECT id, book_id, barcode, branch, shelf, condition, acquisition_date,
+    CASE WHEN status = 'reserved' THEN 'available' ELSE status END
+FROM book_copy;
+
+DROP TABLE book_copy;
+
+ALTER TABLE book_copy_old RENAME TO book_copy;
+
+CREATE INDEX idx_book_copy_book_id ON book_copy (book_id);
+
+PRAGMA foreign_keys = ON;
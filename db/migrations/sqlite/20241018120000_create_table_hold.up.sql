@@ -0,0 +1,43 @@
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE book_copy_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    book_id INTEGER NOT NULL,
+    barcode TEXT NOT NULL UNIQUE,
+    branch TEXT NOT NULL,
+    shelf TEXT NOT NULL DEFAULT '',
+    condition TEXT NOT NULL DEFAULT 'good',
+    acquisition_date DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'on_loan', 'reserved', 'lost', 'in_repair')),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE
+);
+
+INSERT INTO book_copy_new SELECT id, book_id, barcode, branch, shelf, condition, acquisition_date, status FROM book_copy;
+
+DROP TABLE book_copy;
+
+ALTER TABLE book_copy_new RENAME TO book_copy;
+
+CREATE INDEX idx_book_copy_book_id ON book_copy (book_id);
+
+CREATE TABLE hold (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    book_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    copy_id INTEGER,
+    status TEXT NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    created_at DATETIME NOT NULL,
+    ready_at DATETIME,
+    expires_at DATETIME,
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES user(id),
+    FOREIGN KEY (copy_id) REFERENCES book_copy(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_hold_book_status ON hold (book_id, status);
+
+CREATE INDEX idx_hold_user_id ON hold (user_id);
+
+CREATE UNIQUE INDEX idx_hold_active_user_book ON hold (book_id, user_id) WHERE status IN ('waiting', 'ready');
+
+PRAGMA foreign_keys = ON;
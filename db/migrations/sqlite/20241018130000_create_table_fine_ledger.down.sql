@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_fine_ledger_loan_id;
+DROP INDEX IF EXISTS idx_fine_ledger_user_id;
+DROP TABLE IF EXISTS fine_ledger;
+
+ALTER TABLE user DROP COLUMN category;
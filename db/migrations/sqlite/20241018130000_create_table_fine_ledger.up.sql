@@ -0,0 +1,19 @@
+ALTER TABLE user ADD COLUMN category TEXT NOT NULL DEFAULT 'regular' CHECK (category IN ('regular', 'student', 'senior'));
+
+CREATE TABLE fine_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    loan_id INTEGER,
+    entry_type TEXT NOT NULL CHECK (entry_type IN ('charge', 'payment', 'waiver')),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    recorded_by INTEGER,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES user(id),
+    FOREIGN KEY (loan_id) REFERENCES loan(id),
+    FOREIGN KEY (recorded_by) REFERENCES user(id)
+);
+
+CREATE INDEX idx_fine_ledger_user_id ON fine_ledger (user_id);
+
+CREATE INDEX idx_fine_ledger_loan_id ON fine_ledger (loan_id);
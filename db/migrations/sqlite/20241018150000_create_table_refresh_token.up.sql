@@ -0,0 +1,21 @@
+CREATE TABLE refresh_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    family_id TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    access_jti TEXT NOT NULL,
+    access_expires_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    replaced_by INTEGER,
+    FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+    FOREIGN KEY (replaced_by) REFERENCES refresh_token(id)
+);
+
+CREATE INDEX idx_refresh_token_family_id ON refresh_token (family_id);
+
+CREATE TABLE revoked_token (
+    jti TEXT PRIMARY KEY,
+    expires_at DATETIME NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_book_contributor_author_id;
+DROP TABLE IF EXISTS book_contributor;
@@ -0,0 +1,5 @@
+CREATE TABLE publisher (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    city TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_book_work_id;
+DROP INDEX IF EXISTS idx_book_publisher_id;
+
+ALTER TABLE book DROP COLUMN work_id;
+ALTER TABLE book DROP COLUMN description;
+ALTER TABLE book DROP COLUMN format;
+ALTER TABLE book DROP COLUMN language;
+ALTER TABLE book DROP COLUMN page_count;
+ALTER TABLE book DROP COLUMN edition;
+ALTER TABLE book DROP COLUMN publication_year;
+ALTER TABLE book DROP COLUMN publisher_id;
+
+DROP TABLE IF EXISTS work;
@@ -0,0 +1,18 @@
+CREATE TABLE category (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    parent_id INTEGER,
+    FOREIGN KEY (parent_id) REFERENCES category(id)
+);
+
+CREATE UNIQUE INDEX idx_category_parent_name ON category (parent_id, name);
+
+CREATE TABLE book_category (
+    book_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    PRIMARY KEY (book_id, category_id),
+    FOREIGN KEY (book_id) REFERENCES book(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_category_category_id ON book_category (category_id);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS audit_log_append_only;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_entity;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,38 @@
+package data
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+)
+
+const (
+	AuditAuthor = "author"
+	AuditBook   = "book"
+	AuditUser   = "user"
+)
+
+// RequestIdKey is the context key of the id middleware.RequestId gives every
+// request.
+const RequestIdKey = "request_id"
+
+// Actor is who made a change and the request it came with, recorded on
+// every audit entry. Username and Role are empty for a request without a
+// token, such as a registration.
+type Actor struct {
+	Username  string
+	Role      string
+	RequestId string
+	ClientIp  string
+}
+
+func (a Actor) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if a.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,10 @@
+package data
+
+// What happens to the books of an author that is deleted: restrict refuses
+// the delete while the author has books, cascade deletes the books with the
+// author and reassign moves them to another author.
+const (
+	AuthorDeleteRestrict = "restrict"
+	AuthorDeleteCascade  = "cascade"
+	AuthorDeleteReassign = "reassign"
+)
@@ -0,0 +1,23 @@
+package data
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusReserved  = "reserved"
+	CopyStatusLost      = "lost"
+	CopyStatusInRepair  = "in_repair"
+)
+
+// CopyStatuses are the statuses staff may give a copy by hand. on_loan and
+// reserved follow the loan and hold rows, only loans and holds set them.
+var CopyStatuses = []string{CopyStatusAvailable, CopyStatusLost, CopyStatusInRepair}
+
+const (
+	CopyConditionNew     = "new"
+	CopyConditionGood    = "good"
+	CopyConditionFair    = "fair"
+	CopyConditionPoor    = "poor"
+	CopyConditionDamaged = "damaged"
+)
+
+var CopyConditions = []string{CopyConditionNew, CopyConditionGood, CopyConditionFair, CopyConditionPoor, CopyConditionDamaged}
@@ -2,9 +2,19 @@ package data
 
 import "github.com/golang-jwt/jwt"
 
-var JwtKey = []byte("library-ap!-ilham")
-
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role"`
+	Session  string `json:"sid"`
 	jwt.StandardClaims
 }
+
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if c.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,7 @@
+package data
+
+const (
+	FineEntryCharge  = "charge"
+	FineEntryPayment = "payment"
+	FineEntryWaiver  = "waiver"
+)
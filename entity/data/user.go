@@ -0,0 +1,19 @@
+package data
+
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleMember    = "member"
+)
+
+var Roles = []string{RoleAdmin, RoleLibrarian, RoleMember}
+
+var StaffRoles = []string{RoleAdmin, RoleLibrarian}
+
+const (
+	UserCategoryRegular = "regular"
+	UserCategoryStudent = "student"
+	UserCategorySenior  = "senior"
+)
+
+var UserCategories = []string{UserCategoryRegular, UserCategoryStudent, UserCategorySenior}
@@ -0,0 +1,9 @@
+package request
+
+type AuditQuery struct {
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+	Entity string `form:"entity" json:"entity" validate:"omitempty,oneof=author book user"`
+	Id     int    `form:"id" json:"id" validate:"omitempty,gt=0"`
+	Actor  string `form:"actor" json:"actor"`
+}
@@ -9,11 +9,26 @@ type Author struct {
 }
 
 type CreateAuthor struct {
-	Name      string `json:"name" form:"name"`
-	Birthdate string `json:"birth_date" form:"birth_date" gorm:"column:birth_date"`
+	Name      string `json:"name" form:"name" validate:"required,min=3"`
+	Birthdate string `json:"birth_date" form:"birth_date" gorm:"column:birth_date" validate:"required,pastdate"`
 }
 
 type UpdateAuthor struct {
-	Name      string `json:"name" form:"name"`
-	Birthdate string `json:"birth_date" form:"birth_date" gorm:"column:birth_date"`
+	Name      string `json:"name" form:"name" validate:"omitempty,min=3"`
+	Birthdate string `json:"birth_date" form:"birth_date" gorm:"column:birth_date" validate:"omitempty,pastdate"`
+}
+
+type AuthorQuery struct {
+	Page          int    `form:"page"`
+	Limit         int    `form:"limit"`
+	Sort          string `form:"sort"`
+	Order         string `form:"order"`
+	Name          string `form:"name"`
+	BirthDateFrom string `form:"birth_date_from"`
+	BirthDateTo   string `form:"birth_date_to"`
+}
+
+type DeleteAuthor struct {
+	Strategy string `form:"strategy" json:"strategy" validate:"omitempty,oneof=restrict cascade reassign"`
+	To       int    `form:"to" json:"to" validate:"omitempty,gt=0"`
 }
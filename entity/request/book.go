@@ -7,14 +7,62 @@ type Book struct {
 	AuthorId int    `json:"author_id"`
 }
 
+type BookContributor struct {
+	AuthorId int    `json:"author_id" validate:"gt=0"`
+	Role     string `json:"role" validate:"omitempty,contributor_role"`
+}
+
+// BookPublication is the publication data shared by the create and update
+// requests. EditionOf groups the book with another book as editions of the
+// same work.
+type BookPublication struct {
+	PublisherId     int    `json:"publisher_id,omitempty" form:"publisher_id" validate:"gte=0"`
+	PublicationYear int    `json:"publication_year,omitempty" form:"publication_year" validate:"gte=0,lte=9999"`
+	Edition         string `json:"edition,omitempty" form:"edition"`
+	PageCount       int    `json:"page_count,omitempty" form:"page_count" validate:"gte=0"`
+	Language        string `json:"language,omitempty" form:"language" validate:"omitempty,language"`
+	Format          string `json:"format,omitempty" form:"format" validate:"omitempty,book_format"`
+	Description     string `json:"description,omitempty" form:"description"`
+	EditionOf       int    `json:"edition_of,omitempty" form:"edition_of" gorm:"-" validate:"gte=0"`
+}
+
 type CreateBook struct {
-	Title    string `json:"title" form:"title"`
-	Isbn     string `json:"isbn" form:"isbn"`
-	AuthorId int    `json:"author_id" form:"author_id"`
+	Title        string            `json:"title" form:"title" validate:"required,min=3"`
+	Isbn         string            `json:"isbn" form:"isbn" validate:"required,isbn"`
+	AuthorId     int               `json:"author_id" form:"author_id" validate:"required_without=Contributors,gte=0"`
+	Contributors []BookContributor `json:"contributors,omitempty" form:"-" gorm:"-" validate:"dive"`
+	CategoryIds  []int             `json:"category_ids,omitempty" form:"-" gorm:"-" validate:"unique,dive,gt=0"`
+	BookPublication
 }
 
 type UpdateBook struct {
-	Title    string `json:"title" form:"title"`
-	Isbn     string `json:"isbn" form:"isbn"`
-	AuthorId int    `json:"author_id" form:"author_id"`
+	Title        string            `json:"title" form:"title" validate:"required,min=3"`
+	Isbn         string            `json:"isbn" form:"isbn" validate:"required,isbn"`
+	AuthorId     int               `json:"author_id" form:"author_id" validate:"required_without=Contributors,gte=0"`
+	Contributors []BookContributor `json:"contributors,omitempty" form:"-" gorm:"-" validate:"dive"`
+	CategoryIds  []int             `json:"category_ids,omitempty" form:"-" gorm:"-" validate:"unique,dive,gt=0"`
+	BookPublication
+}
+
+type BookQuery struct {
+	Page        int    `form:"page"`
+	Limit       int    `form:"limit"`
+	Sort        string `form:"sort"`
+	Order       string `form:"order"`
+	Title       string `form:"title"`
+	AuthorId    int    `form:"author_id"`
+	Isbn        string `form:"isbn"`
+	PublisherId int    `form:"publisher_id"`
+	WorkId      int    `form:"work_id"`
+	Language    string `form:"language"`
+	Format      string `form:"format"`
+	YearFrom    int    `form:"year_from"`
+	YearTo      int    `form:"year_to"`
+	CategoryId  int    `form:"category"`
+}
+
+type SearchBook struct {
+	Q     string `form:"q"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
 }
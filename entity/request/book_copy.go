@@ -0,0 +1,20 @@
+package request
+
+type CreateBookCopy struct {
+	BookId          int    `json:"-" form:"-"`
+	Barcode         string `json:"barcode" form:"barcode"`
+	Branch          string `json:"branch" form:"branch"`
+	Shelf           string `json:"shelf" form:"shelf"`
+	Condition       string `json:"condition" form:"condition"`
+	AcquisitionDate string `json:"acquisition_date" form:"acquisition_date" gorm:"column:acquisition_date"`
+	Status          string `json:"status" form:"status"`
+}
+
+type UpdateBookCopy struct {
+	Barcode         string `json:"barcode" form:"barcode"`
+	Branch          string `json:"branch" form:"branch"`
+	Shelf           string `json:"shelf" form:"shelf"`
+	Condition       string `json:"condition" form:"condition"`
+	AcquisitionDate string `json:"acquisition_date" form:"acquisition_date" gorm:"column:acquisition_date"`
+	Status          string `json:"status" form:"status"`
+}
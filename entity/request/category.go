@@ -0,0 +1,13 @@
+package request
+
+type CreateCategory struct {
+	Name     string `json:"name" form:"name"`
+	ParentId int    `json:"parent_id" form:"parent_id"`
+}
+
+// UpdateCategory leaves the parent untouched when ParentId is nil, 0 moves
+// the category to the top of the tree.
+type UpdateCategory struct {
+	Name     string `json:"name" form:"name"`
+	ParentId *int   `json:"parent_id" form:"parent_id"`
+}
@@ -0,0 +1,13 @@
+package request
+
+import "time"
+
+type FineEntry struct {
+	UserId     int       `json:"user_id" form:"user_id"`
+	LoanId     int       `json:"loan_id" form:"loan_id"`
+	Amount     int64     `json:"amount" form:"amount"`
+	Note       string    `json:"note" form:"note"`
+	EntryType  string    `json:"-" form:"-"`
+	RecordedBy int       `json:"-" form:"-"`
+	CreatedAt  time.Time `json:"-" form:"-"`
+}
@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+type CreateHold struct {
+	BookId    int
+	UserId    int
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package request
+
+import "time"
+
+type CreateLoan struct {
+	CopyId   int       `json:"copy_id" form:"copy_id"`
+	UserId   int       `json:"user_id" form:"user_id"`
+	LoanedAt time.Time `json:"-" form:"-"`
+	DueDate  time.Time `json:"-" form:"-"`
+}
+
+type ReturnLoan struct {
+	ReturnedAt    time.Time
+	HoldExpiresAt time.Time
+	Fine          int64
+}
@@ -0,0 +1,20 @@
+package request
+
+type CreatePublisher struct {
+	Name string `json:"name" form:"name"`
+	City string `json:"city" form:"city"`
+}
+
+type UpdatePublisher struct {
+	Name string `json:"name" form:"name"`
+	City string `json:"city" form:"city"`
+}
+
+type PublisherQuery struct {
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+	Sort  string `form:"sort"`
+	Order string `form:"order"`
+	Name  string `form:"name"`
+	City  string `form:"city"`
+}
@@ -0,0 +1,17 @@
+package request
+
+import "time"
+
+type RefreshToken struct {
+	UserId          int
+	FamilyId        string
+	TokenHash       string
+	AccessJti       string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+}
+
+type Refresh struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type TrashQuery struct {
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+	Entity string `form:"entity" json:"entity" validate:"omitempty,oneof=book author"`
+}
@@ -1,6 +1,16 @@
 package request
 
 type User struct {
-	Username string `json:"username" form:"username"`
-	Password string `json:"password" form:"password"`
+	ID       int    `json:"-" form:"-"`
+	Username string `json:"username" form:"username" validate:"required,min=5,max=20,username"`
+	Password string `json:"password" form:"password" validate:"required,min=8"`
+	Role     string `json:"-" form:"-"`
+}
+
+type UpdateUserCategory struct {
+	Category string `json:"category" form:"category"`
+}
+
+type UpdateUserRole struct {
+	Role string `json:"role" form:"role"`
 }
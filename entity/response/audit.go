@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+// AuditChange is a field before and after a change, null on the side where
+// the entity did not exist.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditEntry struct {
+	Id        int                    `json:"id"`
+	CreatedAt time.Time              `json:"created_at"`
+	Actor     string                 `json:"actor"`
+	ActorRole string                 `json:"actor_role"`
+	Entity    string                 `json:"entity"`
+	EntityId  int                    `json:"entity_id"`
+	Action    string                 `json:"action"`
+	Changes   map[string]AuditChange `json:"changes" gorm:"serializer:json"`
+	RequestId string                 `json:"request_id"`
+	ClientIp  string                 `json:"client_ip"`
+}
+
+type WebResponseAudit struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Data       interface{} `json:"data"`
+}
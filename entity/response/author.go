@@ -11,6 +11,7 @@ type Author struct {
 	ID        int       `json:"id"`
 	Name      string    `json:"name"`
 	BirthDate time.Time `json:"birth_date" gorm:"column:birth_date"`
+	Version   int       `json:"version"`
 }
 
 type AuthorBook struct {
@@ -22,6 +23,25 @@ type AuthorBook struct {
 type UpdateAuthor struct {
 	Name      string    `json:"name"`
 	BirthDate time.Time `json:"birth_date" gorm:"column:birth_date"`
+	Version   int       `json:"version"`
+}
+
+// AuthorBookRef is a book touched by the delete of one of its authors.
+type AuthorBookRef struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+	Isbn  string `json:"isbn"`
+}
+
+// AuthorDeletion is everything deleting an author changed: the author moved
+// to the trash and, depending on the strategy, the books deleted with them
+// or moved to another author.
+type AuthorDeletion struct {
+	Strategy        string          `json:"strategy"`
+	Author          Author          `json:"author"`
+	DeletedBooks    []AuthorBookRef `json:"deleted_books"`
+	ReassignedBooks []AuthorBookRef `json:"reassigned_books"`
+	ReassignedTo    *Author         `json:"reassigned_to"`
 }
 
 type WebResponseAuthor struct {
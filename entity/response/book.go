@@ -1,25 +1,94 @@
 package response
 
+import "time"
+
 type CreateBook struct {
 	Title    string `json:"title"`
 	Isbn     string `json:"isbn"`
 	AuthorId int    `json:"author_id"`
 }
 
+type BookContributor struct {
+	AuthorId int    `json:"author_id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Position int    `json:"position"`
+}
+
 type Book struct {
-	Id         int    `json:"id"`
-	Title      string `json:"title"`
-	Isbn       string `json:"isbn"`
-	AuthorId   int    `json:"author_id"`
-	AuthorName string `json:"author_name"`
-	BirthDate  string `json:"birth_date"`
+	Id              int               `json:"id"`
+	Title           string            `json:"title"`
+	Isbn            string            `json:"isbn"`
+	AuthorId        int               `json:"author_id"`
+	AuthorName      string            `json:"author_name"`
+	BirthDate       string            `json:"birth_date"`
+	TotalCopies     int               `json:"total_copies"`
+	AvailableCopies int               `json:"available_copies"`
+	PublisherId     *int              `json:"publisher_id"`
+	PublisherName   *string           `json:"publisher_name"`
+	PublicationYear *int              `json:"publication_year"`
+	Edition         string            `json:"edition"`
+	PageCount       *int              `json:"page_count"`
+	Language        string            `json:"language"`
+	Format          string            `json:"format"`
+	Description     string            `json:"description"`
+	WorkId          *int              `json:"work_id"`
+	Version         int               `json:"version"`
+	DeletedAt       *time.Time        `json:"-"`
+	Contributors    []BookContributor `json:"contributors" gorm:"-"`
+	Categories      []BookCategory    `json:"categories" gorm:"-"`
 }
 
 type ResultBook struct {
-	Id         int    `json:"id"`
-	Title      string `json:"title"`
-	Isbn       string `json:"isbn"`
-	AuthorBook `json:"author"`
+	Id              int    `json:"id"`
+	Title           string `json:"title"`
+	Isbn            string `json:"isbn"`
+	TotalCopies     int    `json:"total_copies"`
+	AvailableCopies int    `json:"available_copies"`
+	AuthorBook      `json:"author"`
+	Contributors    []BookContributor `json:"contributors"`
+	Categories      []BookCategory    `json:"categories"`
+	Publisher       *PublisherBook    `json:"publisher"`
+	PublicationYear *int              `json:"publication_year"`
+	Edition         string            `json:"edition"`
+	PageCount       *int              `json:"page_count"`
+	Language        string            `json:"language"`
+	Format          string            `json:"format"`
+	Description     string            `json:"description"`
+	WorkId          *int              `json:"work_id"`
+	Version         int               `json:"version"`
+}
+
+// Result turns a book row into the shape returned by the API.
+func (b Book) Result() ResultBook {
+	result := ResultBook{
+		Id:              b.Id,
+		Title:           b.Title,
+		Isbn:            b.Isbn,
+		TotalCopies:     b.TotalCopies,
+		AvailableCopies: b.AvailableCopies,
+		AuthorBook: AuthorBook{
+			ID:        b.AuthorId,
+			Name:      b.AuthorName,
+			BirthDate: b.BirthDate,
+		},
+		Contributors:    b.Contributors,
+		Categories:      b.Categories,
+		PublicationYear: b.PublicationYear,
+		Edition:         b.Edition,
+		PageCount:       b.PageCount,
+		Language:        b.Language,
+		Format:          b.Format,
+		Description:     b.Description,
+		WorkId:          b.WorkId,
+		Version:         b.Version,
+	}
+
+	if b.PublisherId != nil && b.PublisherName != nil {
+		result.Publisher = &PublisherBook{ID: *b.PublisherId, Name: *b.PublisherName}
+	}
+
+	return result
 }
 
 type WebResponseBook struct {
@@ -34,3 +103,35 @@ type WebResponseBooks struct {
 	Pagination Pagination  `json:"pagination"`
 	Data       interface{} `json:"data"`
 }
+
+type SearchBook struct {
+	Id                  int     `json:"id"`
+	Title               string  `json:"title"`
+	Isbn                string  `json:"isbn"`
+	AuthorId            int     `json:"author_id"`
+	AuthorName          string  `json:"author_name"`
+	BirthDate           string  `json:"birth_date"`
+	TotalCopies         int     `json:"total_copies"`
+	AvailableCopies     int     `json:"available_copies"`
+	TitleHighlight      string  `json:"title_highlight"`
+	IsbnHighlight       string  `json:"isbn_highlight"`
+	AuthorNameHighlight string  `json:"author_name_highlight"`
+	Rank                float64 `json:"rank"`
+}
+
+type BookHighlight struct {
+	Title      string `json:"title"`
+	Isbn       string `json:"isbn"`
+	AuthorName string `json:"author_name"`
+}
+
+type ResultSearchBook struct {
+	Id              int    `json:"id"`
+	Title           string `json:"title"`
+	Isbn            string `json:"isbn"`
+	TotalCopies     int    `json:"total_copies"`
+	AvailableCopies int    `json:"available_copies"`
+	AuthorBook      `json:"author"`
+	Highlight       BookHighlight `json:"highlight"`
+	Score           float64       `json:"score"`
+}
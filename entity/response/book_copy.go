@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+type BookCopy struct {
+	Id              int       `json:"id"`
+	BookId          int       `json:"book_id"`
+	Barcode         string    `json:"barcode"`
+	Branch          string    `json:"branch"`
+	Shelf           string    `json:"shelf"`
+	Condition       string    `json:"condition"`
+	AcquisitionDate time.Time `json:"acquisition_date" gorm:"column:acquisition_date"`
+	Status          string    `json:"status"`
+}
+
+type WebResponseBookCopy struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
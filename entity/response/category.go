@@ -0,0 +1,24 @@
+package response
+
+type Category struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentId *int   `json:"parent_id"`
+}
+
+type CategoryTree struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"`
+	Children []CategoryTree `json:"children"`
+}
+
+type BookCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebResponseCategory struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
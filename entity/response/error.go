@@ -1,6 +1,11 @@
 package response
 
+import "github.com/ilhaamms/library-api/apperror"
+
 type ErrorResponse struct {
-	StatusCode int    `json:"status_code"`
-	Error      string `json:"error"`
+	StatusCode int                   `json:"status_code"`
+	Code       string                `json:"code"`
+	Error      string                `json:"error"`
+	Details    []apperror.FieldError `json:"details,omitempty"`
+	Data       interface{}           `json:"data,omitempty"`
 }
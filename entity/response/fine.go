@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+type FineEntry struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id"`
+	LoanId     *int      `json:"loan_id"`
+	EntryType  string    `json:"entry_type"`
+	Amount     int64     `json:"amount"`
+	Note       string    `json:"note"`
+	RecordedBy *int      `json:"recorded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AccruingFine struct {
+	LoanId      int       `json:"loan_id"`
+	Title       string    `json:"title"`
+	DueDate     time.Time `json:"due_date"`
+	DaysOverdue int       `json:"days_overdue"`
+	Amount      int64     `json:"amount"`
+}
+
+type FineSummary struct {
+	Category      string         `json:"category"`
+	Balance       int64          `json:"balance"`
+	Accruing      int64          `json:"accruing"`
+	AccruingLoans []AccruingFine `json:"accruing_loans"`
+	Ledger        []FineEntry    `json:"ledger"`
+}
+
+type WebResponseFine struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
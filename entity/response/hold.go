@@ -0,0 +1,24 @@
+package response
+
+import "time"
+
+type Hold struct {
+	Id            int        `json:"id"`
+	BookId        int        `json:"book_id"`
+	Title         string     `json:"title"`
+	UserId        int        `json:"user_id"`
+	Username      string     `json:"username"`
+	CopyId        *int       `json:"copy_id"`
+	Barcode       *string    `json:"barcode"`
+	Status        string     `json:"status"`
+	QueuePosition int        `json:"queue_position"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ReadyAt       *time.Time `json:"ready_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+type WebResponseHold struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
@@ -0,0 +1,15 @@
+package response
+
+type IsbnValidation struct {
+	Input  string `json:"input"`
+	Valid  bool   `json:"valid"`
+	Isbn13 string `json:"isbn13,omitempty"`
+	Isbn10 string `json:"isbn10,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type WebResponseIsbn struct {
+	StatusCode int            `json:"status_code"`
+	Message    string         `json:"message"`
+	Data       IsbnValidation `json:"data"`
+}
@@ -0,0 +1,24 @@
+package response
+
+import "time"
+
+type Loan struct {
+	Id           int        `json:"id"`
+	CopyId       int        `json:"copy_id"`
+	Barcode      string     `json:"barcode"`
+	BookId       int        `json:"book_id"`
+	Title        string     `json:"title"`
+	UserId       int        `json:"user_id"`
+	Username     string     `json:"username"`
+	LoanedAt     time.Time  `json:"loaned_at"`
+	DueDate      time.Time  `json:"due_date"`
+	ReturnedAt   *time.Time `json:"returned_at"`
+	RenewalCount int        `json:"renewal_count"`
+	Overdue      bool       `json:"overdue" gorm:"-"`
+}
+
+type WebResponseLoan struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
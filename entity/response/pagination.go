@@ -1,7 +1,8 @@
 package response
 
 type Pagination struct {
-	CurrentPage int `json:"current_page"`
-	TotalPage   int `json:"total_page"`
-	Limit       int `json:"limit"`
+	CurrentPage int   `json:"current_page"`
+	TotalPage   int   `json:"total_page"`
+	Limit       int   `json:"limit"`
+	TotalItems  int64 `json:"total_items"`
 }
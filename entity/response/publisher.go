@@ -0,0 +1,25 @@
+package response
+
+type Publisher struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+type PublisherBook struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebResponsePublisher struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
+
+type WebResponsePublishers struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Pagination Pagination  `json:"pagination"`
+	Data       interface{} `json:"data"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type RefreshToken struct {
+	Id         int
+	UserId     int
+	FamilyId   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *int
+}
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+// TrashItem is a deleted book or author, Name holds the title of a book.
+type TrashItem struct {
+	Entity    string    `json:"entity"`
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at" gorm:"-"`
+}
+
+type PurgedTrash struct {
+	Before  time.Time `json:"before"`
+	Books   int64     `json:"books"`
+	Authors int64     `json:"authors"`
+}
+
+type WebResponseTrash struct {
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Data       interface{} `json:"data"`
+}
@@ -10,12 +10,24 @@ type User struct {
 	ID       int    `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Role     string `json:"role"`
+	Category string `json:"category"`
 }
 
-type ResponseUserLogin struct {
+type UserProfile struct {
+	ID       int    `json:"id"`
 	Username string `json:"username"`
-	Password string `json:"password"`
-	Token    string `json:"token"`
+	Role     string `json:"role"`
+	Category string `json:"category"`
+}
+
+type ResponseUserLogin struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	Role         string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
 }
 
 type CreateUser struct {
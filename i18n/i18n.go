@@ -0,0 +1,144 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Indonesian = "id"
+	English    = "en"
+
+	// Default is used when the client asks for no language or for one
+	// without a catalogue.
+	Default = Indonesian
+
+	// ContextKey is the gin context key holding the language of a request.
+	ContextKey = "lang"
+)
+
+// Params fill the {name} placeholders of a message.
+type Params map[string]interface{}
+
+// Catalogues are flat JSON objects mapping a message key to its text, one
+// file per language in i18n/locales.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogues = load()
+
+func load() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogues := make(map[string]map[string]string, len(files))
+
+	for _, file := range files {
+		content, err := locales.ReadFile("locales/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		var messages map[string]string
+		err = json.Unmarshal(content, &messages)
+		if err != nil {
+			panic(fmt.Sprintf("katalog pesan %s tidak valid : %v", file.Name(), err))
+		}
+
+		catalogues[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+
+	return catalogues
+}
+
+// Languages returns the languages that have a catalogue.
+func Languages() []string {
+	languages := make([]string, 0, len(catalogues))
+	for lang := range catalogues {
+		languages = append(languages, lang)
+	}
+
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Keys returns the message keys of a language, used to check that every
+// catalogue translates the same messages.
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(catalogues[lang]))
+	for key := range catalogues[lang] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// T translates key into lang. A key missing from lang is looked up in the
+// default language and, failing that, returned as is.
+func T(lang, key string, params ...Params) string {
+	message, ok := catalogues[lang][key]
+	if !ok {
+		message, ok = catalogues[Default][key]
+	}
+
+	if !ok {
+		message = key
+	}
+
+	for _, p := range params {
+		for name, value := range p {
+			message = strings.ReplaceAll(message, "{"+name+"}", fmt.Sprint(value))
+		}
+	}
+
+	return message
+}
+
+// Match picks the best supported language from a lang parameter or an
+// Accept-Language header such as "en-US,en;q=0.9,id;q=0.8". Region
+// subtags are ignored. It returns an empty string when nothing matches.
+func Match(header string) string {
+	best, bestQuality := "", 0.0
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+
+		lang := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexAny(lang, "-_"); i >= 0 {
+			lang = lang[:i]
+		}
+
+		if _, ok := catalogues[lang]; !ok {
+			continue
+		}
+
+		quality := 1.0
+		for _, field := range fields[1:] {
+			value, found := strings.CutPrefix(strings.TrimSpace(field), "q=")
+			if !found {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				quality = q
+			}
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+
+	return best
+}
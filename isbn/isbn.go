@@ -0,0 +1,167 @@
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrLength         = errors.New("isbn harus terdiri dari 10 atau 13 digit")
+	ErrCharacter      = errors.New("isbn hanya boleh berisi angka, dengan X sebagai check digit ISBN-10")
+	ErrPrefix         = errors.New("ISBN-13 harus diawali 978 atau 979")
+	ErrChecksum       = errors.New("check digit isbn tidak valid")
+	ErrNotConvertible = errors.New("hanya ISBN-13 berawalan 978 yang memiliki bentuk ISBN-10")
+)
+
+var messageKeys = map[error]string{
+	ErrLength:         "isbn.length",
+	ErrCharacter:      "isbn.character",
+	ErrPrefix:         "isbn.prefix",
+	ErrChecksum:       "isbn.checksum",
+	ErrNotConvertible: "isbn.not_convertible",
+}
+
+// MessageKey returns the i18n message key of an error returned by this
+// package, or the error text for any other error.
+func MessageKey(err error) string {
+	if key, ok := messageKeys[err]; ok {
+		return key
+	}
+
+	return err.Error()
+}
+
+// Clean removes the hyphens and spaces used to group an ISBN and upper cases
+// the ISBN-10 check digit X.
+func Clean(value string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+}
+
+// Validate checks the length, characters and check digit of an ISBN-10 or
+// ISBN-13. Hyphens and spaces are ignored.
+func Validate(value string) error {
+	value = Clean(value)
+
+	switch len(value) {
+	case 10:
+		for i, c := range value {
+			if !isDigit(c) && !(c == 'X' && i == 9) {
+				return ErrCharacter
+			}
+		}
+
+		if checkDigit10(value[:9]) != value[9] {
+			return ErrChecksum
+		}
+	case 13:
+		for _, c := range value {
+			if !isDigit(c) {
+				return ErrCharacter
+			}
+		}
+
+		if !strings.HasPrefix(value, "978") && !strings.HasPrefix(value, "979") {
+			return ErrPrefix
+		}
+
+		if checkDigit13(value[:12]) != value[12] {
+			return ErrChecksum
+		}
+	default:
+		return ErrLength
+	}
+
+	return nil
+}
+
+// Normalize validates an ISBN and returns its canonical ISBN-13 form without
+// separators.
+func Normalize(value string) (string, error) {
+	err := Validate(value)
+	if err != nil {
+		return "", err
+	}
+
+	value = Clean(value)
+	if len(value) == 10 {
+		return To13(value)
+	}
+
+	return value, nil
+}
+
+// To13 converts a valid ISBN-10 to ISBN-13 by prefixing 978 and computing a
+// new check digit. An ISBN-13 is returned unchanged.
+func To13(value string) (string, error) {
+	err := Validate(value)
+	if err != nil {
+		return "", err
+	}
+
+	value = Clean(value)
+	if len(value) == 13 {
+		return value, nil
+	}
+
+	body := "978" + value[:9]
+
+	return body + string(checkDigit13(body)), nil
+}
+
+// To10 converts a valid ISBN-13 with the 978 prefix to ISBN-10. ISBN-13
+// numbers starting with 979 have no ISBN-10 form.
+func To10(value string) (string, error) {
+	err := Validate(value)
+	if err != nil {
+		return "", err
+	}
+
+	value = Clean(value)
+	if len(value) == 10 {
+		return value, nil
+	}
+
+	if !strings.HasPrefix(value, "978") {
+		return "", ErrNotConvertible
+	}
+
+	body := value[3:12]
+
+	return body + string(checkDigit10(body)), nil
+}
+
+// checkDigit10 computes the mod 11 check digit of the first nine digits of an
+// ISBN-10.
+func checkDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// checkDigit13 computes the mod 10 check digit of the first twelve digits of
+// an ISBN-13, weighting the digits alternately by 1 and 3.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += int(body[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
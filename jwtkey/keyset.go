@@ -0,0 +1,233 @@
+package jwtkey
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/ilhaamms/library-api/config"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var Algorithms = []string{AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA}
+
+var (
+	ErrUnknownKey        = errors.New("kid tidak dikenal")
+	ErrAlgorithmMismatch = errors.New("algoritma token tidak sesuai dengan key")
+)
+
+type Key struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// KeySet holds every key that may verify a token and the single active key
+// used to sign new ones. Keys that are being rotated out stay in the set
+// with only their public half until the tokens they signed have expired.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// NewHMAC builds a key set that signs and verifies with a shared HS256
+// secret. Tokens signed this way carry no kid and are not published in the
+// JWKS.
+func NewHMAC(secret []byte) *KeySet {
+	key := &Key{Method: jwt.SigningMethodHS256, private: secret, public: secret}
+
+	return &KeySet{active: key, keys: map[string]*Key{"": key}}
+}
+
+// FromConfig loads the PEM keys listed in jwt_keys. Without any configured
+// key it falls back to HS256 with jwt_secret.
+func FromConfig(cfg *config.Config) (*KeySet, error) {
+	if len(cfg.JwtKeys) == 0 {
+		return NewHMAC([]byte(cfg.JwtSecret)), nil
+	}
+
+	keySet := &KeySet{keys: map[string]*Key{}}
+
+	for _, keyConfig := range cfg.JwtKeys {
+		key, err := loadKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("jwt_keys.%s : %w", keyConfig.Kid, err)
+		}
+
+		keySet.keys[key.Kid] = key
+	}
+
+	active, ok := keySet.keys[cfg.JwtActiveKey]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("jwt_active_key %s harus memiliki private_key", cfg.JwtActiveKey)
+	}
+
+	keySet.active = active
+
+	return keySet, nil
+}
+
+func loadKey(keyConfig config.JwtKey) (*Key, error) {
+	key := &Key{Kid: keyConfig.Kid}
+
+	var privatePEM, publicPEM []byte
+	var err error
+
+	if keyConfig.PrivateKey != "" {
+		privatePEM, err = os.ReadFile(keyConfig.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keyConfig.PublicKey != "" {
+		publicPEM, err = os.ReadFile(keyConfig.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch keyConfig.Algorithm {
+	case AlgorithmRS256:
+		key.Method = jwt.SigningMethodRS256
+
+		if privatePEM != nil {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+
+			key.private, key.public = private, &private.PublicKey
+		}
+
+		if publicPEM != nil {
+			public, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, err
+			}
+
+			err = setPublic(key, public)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case AlgorithmES256:
+		key.Method = jwt.SigningMethodES256
+
+		if privatePEM != nil {
+			private, err := jwt.ParseECPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+
+			key.private, key.public = private, &private.PublicKey
+		}
+
+		if publicPEM != nil {
+			public, err := jwt.ParseECPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, err
+			}
+
+			err = setPublic(key, public)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if public, ok := key.public.(*ecdsa.PublicKey); ok && public.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 membutuhkan kurva P-256")
+		}
+	case AlgorithmEdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+
+		if privatePEM != nil {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+
+			key.private, key.public = private, private.(ed25519.PrivateKey).Public()
+		}
+
+		if publicPEM != nil {
+			public, err := jwt.ParseEdPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, err
+			}
+
+			err = setPublic(key, public)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("algorithm %s tidak didukung", keyConfig.Algorithm)
+	}
+
+	if key.public == nil {
+		return nil, errors.New("private_key atau public_key wajib diisi")
+	}
+
+	return key, nil
+}
+
+// setPublic sets the configured public key, which must be the public half of
+// the private key when both are configured.
+func setPublic(key *Key, public crypto.PublicKey) error {
+	if key.private != nil {
+		derived, ok := key.public.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !derived.Equal(public) {
+			return errors.New("public_key tidak cocok dengan private_key")
+		}
+	}
+
+	key.public = public
+
+	return nil
+}
+
+func (ks *KeySet) Active() *Key {
+	return ks.active
+}
+
+// Sign signs the claims with the active key and puts its kid in the token
+// header so verifiers know which public key to use.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+
+	if ks.active.Kid != "" {
+		token.Header["kid"] = ks.active.Kid
+	}
+
+	return token.SignedString(ks.active.private)
+}
+
+// Keyfunc resolves the verification key from the kid header. The algorithm
+// of the token must match the key, otherwise a public key could be abused as
+// an HMAC secret.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrAlgorithmMismatch
+	}
+
+	return key.public, nil
+}
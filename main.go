@@ -2,32 +2,167 @@ package main
 
 import (
 	"log"
+	"os"
+	"time"
 
 	"github.com/ilhaamms/library-api/api"
 	"github.com/ilhaamms/library-api/config"
 	"github.com/ilhaamms/library-api/controller"
+	database "github.com/ilhaamms/library-api/db"
+	"github.com/ilhaamms/library-api/jwtkey"
 	"github.com/ilhaamms/library-api/repository"
 	"github.com/ilhaamms/library-api/service"
 )
 
 func main() {
-	db, err := config.InitDbSQLite()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cfg, args, err := config.Parse(os.Args[2:])
+		if err != nil {
+			log.Fatal("Error loading config : ", err)
+		}
+
+		db, err := config.InitDB(cfg)
+		if err != nil {
+			log.Fatal("Error connecting to database : ", err)
+		}
+
+		err = runMigrate(db, args)
+		if err != nil {
+			log.Fatal("Error running migrations : ", err)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		cfg, args, err := config.Parse(os.Args[2:])
+		if err != nil {
+			log.Fatal("Error loading config : ", err)
+		}
+
+		db, err := config.InitDB(cfg)
+		if err != nil {
+			log.Fatal("Error connecting to database : ", err)
+		}
+
+		err = runCreateAdmin(db, args)
+		if err != nil {
+			log.Fatal("Error creating admin : ", err)
+		}
+
+		return
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal("Error loading config : ", err)
+	}
+
+	keys, err := jwtkey.FromConfig(cfg)
+	if err != nil {
+		log.Fatal("Error loading jwt keys : ", err)
+	}
+
+	db, err := config.InitDB(cfg)
 	if err != nil {
 		log.Fatal("Error connecting to database : ", err)
 	}
 
+	err = database.RequireFTS5(db)
+	if err != nil {
+		log.Fatal("Error checking database : ", err)
+	}
+
+	if cfg.MigrateOnStart {
+		err = runMigrate(db, []string{"up"})
+		if err != nil {
+			log.Fatal("Error running migrations : ", err)
+		}
+	}
+
 	authorRepo := repository.NewAuthorRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
 	bookRepo := repository.NewBookRepository(db)
+	bookCopyRepo := repository.NewBookCopyRepository(db)
+	loanRepo := repository.NewLoanRepository(db)
+	holdRepo := repository.NewHoldRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	transactor := repository.NewTransactor(db)
 
-	authorService := service.NewAuthorService(authorRepo)
-	userService := service.NewUserService(userRepo)
-	bookService := service.NewBookService(bookRepo)
+	finePolicy := service.FinePolicy{
+		GraceDays:  cfg.FineGraceDays,
+		MaxPerItem: cfg.FineMaxPerItem,
+		Rates:      cfg.FineRates,
+	}
+
+	authorService := service.NewAuthorService(authorRepo, auditRepo, transactor)
+	userService := service.NewUserService(userRepo, tokenRepo, auditRepo, transactor, keys)
+	bookService := service.NewBookService(bookRepo, auditRepo, transactor)
+	bookCopyService := service.NewBookCopyService(bookCopyRepo, bookRepo, cfg.HoldPickupDays)
+	loanService := service.NewLoanService(loanRepo, userRepo, cfg.LoanDays, cfg.MaxRenewals, cfg.HoldPickupDays, finePolicy)
+	holdService := service.NewHoldService(holdRepo, bookRepo, userRepo, cfg.HoldPickupDays)
+	fineService := service.NewFineService(fineRepo, loanRepo, userRepo, finePolicy)
+	publisherService := service.NewPublisherService(publisherRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	trashService := service.NewTrashService(trashRepo, cfg.TrashRetentionDays)
+	auditService := service.NewAuditService(auditRepo)
 
 	authorController := controller.NewAuthorController(authorService)
 	userController := controller.NewUserController(userService)
 	bookController := controller.NewBookController(bookService)
+	bookCopyController := controller.NewBookCopyController(bookCopyService)
+	loanController := controller.NewLoanController(loanService)
+	holdController := controller.NewHoldController(holdService)
+	fineController := controller.NewFineController(fineService)
+	publisherController := controller.NewPublisherController(publisherService)
+	categoryController := controller.NewCategoryController(categoryService, bookService)
+	trashController := controller.NewTrashController(trashService)
+	auditController := controller.NewAuditController(auditService)
+	isbnController := controller.NewIsbnController()
+	jwksController := controller.NewJwksController(keys)
+
+	go expireHolds(holdService, time.Minute)
+	go purgeTrash(trashService, time.Hour)
 
-	api := api.NewAPI(authorController, userController, bookController)
+	api := api.NewAPI(cfg, keys, authorController, userController, bookController, bookCopyController, loanController, holdController, fineController, publisherController, categoryController, trashController, auditController, isbnController, jwksController, userService)
 	api.Run()
 }
+
+func expireHolds(holdService service.HoldService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := holdService.ExpireHolds()
+		if err != nil {
+			log.Println("Error expiring holds : ", err)
+			continue
+		}
+
+		if expired > 0 {
+			log.Printf("Expired %d holds", expired)
+		}
+	}
+}
+
+func purgeTrash(trashService service.TrashService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := trashService.Purge()
+		if err != nil {
+			log.Println("Error purging trash : ", err)
+			continue
+		}
+
+		if purged.Books > 0 || purged.Authors > 0 {
+			log.Printf("Purged %d books and %d authors from the trash", purged.Books, purged.Authors)
+		}
+	}
+}
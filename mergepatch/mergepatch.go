@@ -0,0 +1,74 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ContentType is the media type of a JSON merge patch (RFC 7396).
+const ContentType = "application/merge-patch+json"
+
+var ErrInvalid = errors.New("merge patch harus berupa JSON yang valid")
+
+// Apply applies a JSON merge patch to a JSON document. Members of the patch
+// replace the members of the document with the same name, null removes a
+// member and nested objects are merged recursively. A patch that is not an
+// object replaces the whole document.
+func Apply(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+
+	err := json.Unmarshal(patch, &changes)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	if len(document) > 0 {
+		err = json.Unmarshal(document, &target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+// Keys returns the top level members of a patch, used to tell a member that
+// was removed from one that was never sent.
+func Keys(patch []byte) (map[string]bool, error) {
+	var changes map[string]json.RawMessage
+
+	err := json.Unmarshal(patch, &changes)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	keys := make(map[string]bool, len(changes))
+	for key := range changes {
+		keys[key] = true
+	}
+
+	return keys, nil
+}
+
+func merge(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	document, ok := target.(map[string]interface{})
+	if !ok {
+		document = map[string]interface{}{}
+	}
+
+	for key, value := range changes {
+		if value == nil {
+			delete(document, key)
+			continue
+		}
+
+		document[key] = merge(document[key], value)
+	}
+
+	return document
+}
@@ -1,27 +1,26 @@
 package middleware
 
 import (
-	"bytes"
-	"crypto/sha256"
-	"encoding/hex"
-	"io"
-	"log"
-
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"github.com/ilhaamms/library-api/apperror"
 	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/jwtkey"
 )
 
-func Auth() gin.HandlerFunc {
+// Denylist reports whether an access token was revoked before it expired,
+// for example by logging out.
+type Denylist interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+func Auth(keys *jwtkey.KeySet, denylist Denylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		tokenString := c.GetHeader("Authorization")
 
 		if tokenString == "" || len(tokenString) < 7 {
-			c.JSON(401, gin.H{
-				"message": "authorization required",
-			})
-			c.Abort()
+			abort(c, apperror.Unauthorized("auth.required"))
 			return
 		}
 
@@ -29,51 +28,23 @@ func Auth() gin.HandlerFunc {
 
 		claims := &data.Claims{}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return data.JwtKey, nil
-		})
+		token, err := jwt.ParseWithClaims(tokenString, claims, keys.Keyfunc)
 
 		if err != nil || !token.Valid {
-			c.JSON(401, gin.H{
-				"message": "invalid token",
-			})
-			c.Abort()
+			abort(c, apperror.Unauthorized("auth.invalid_token"))
 			return
 		}
 
-		var computedHash string
-		var bodyBytes []byte
-
-		if c.Request.Body != nil {
-			bodyBytes, _ = io.ReadAll(c.Request.Body)
-			computedHash = computeHash(bodyBytes)
-		} else {
-			computedHash = computeHash([]byte(tokenString))
-		}
-
-		c.Request.Header.Set("X-Request-Hash", computedHash)
-
-		hashHeader := c.GetHeader("X-Request-Hash")
-
-		if hashHeader != computedHash {
-			log.Println("hashHeader", hashHeader)
-			c.JSON(400, gin.H{
-				"message": "data integrity validation failed",
-			})
-			c.Abort()
-			return
+		if denylist != nil {
+			revoked, err := denylist.IsRevoked(claims.Id)
+			if err != nil || revoked {
+				abort(c, apperror.Unauthorized("auth.token_revoked"))
+				return
+			}
 		}
 
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
 		c.Set("claims", claims)
 
 		c.Next()
 	}
 }
-
-func computeHash(data []byte) string {
-	hash := sha256.New()
-	hash.Write(data)
-	return hex.EncodeToString(hash.Sum(nil))
-}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+// ErrorHandler renders the last error a handler attached with c.Error as an
+// ErrorResponse in the language chosen by Locale. Typed errors pick the
+// status and code, anything else is reported as an internal error.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := apperror.From(c.Errors.Last().Err)
+		lang := c.GetString(i18n.ContextKey)
+
+		c.JSON(err.Status, response.ErrorResponse{
+			StatusCode: err.Status,
+			Code:       err.Code,
+			Error:      fmt.Sprintf("error : %v", err.Message(lang)),
+			Details:    err.Details(lang),
+			Data:       err.Data,
+		})
+	}
+}
+
+// abort stops the chain with err, ErrorHandler writes the response.
+func abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+// Locale picks the language of the response from the lang query parameter
+// or, without one, the Accept-Language header and stores it in the context
+// under i18n.ContextKey. Unsupported languages fall back to i18n.Default.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		lang := i18n.Match(c.Query("lang"))
+		if lang == "" {
+			lang = i18n.Match(c.GetHeader("Accept-Language"))
+		}
+
+		if lang == "" {
+			lang = i18n.Default
+		}
+
+		c.Set(i18n.ContextKey, lang)
+		c.Header("Content-Language", lang)
+
+		c.Next()
+	}
+}
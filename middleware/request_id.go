@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/entity/data"
+)
+
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestId keeps the X-Request-Id the client sent, or makes a new one when
+// it is missing or malformed, stores it in the context under
+// data.RequestIdKey and sends it back, so an entry of the audit trail can be
+// traced to the request that made it.
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id := c.GetHeader("X-Request-Id")
+		if !requestIdPattern.MatchString(id) {
+			id = newRequestId()
+		}
+
+		c.Set(data.RequestIdKey, id)
+		c.Header("X-Request-Id", id)
+
+		c.Next()
+	}
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+)
+
+// RequireRole must run after Auth. It only lets the request through when the
+// role carried in the token is one of the given roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		claims, ok := c.MustGet("claims").(*data.Claims)
+		if !ok || !claims.HasRole(roles...) {
+			abort(c, apperror.Forbidden("auth.insufficient_role"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+const (
+	HeaderKeyId     = "X-Key-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// MaxSignedBody is the largest body read to check a signature, bigger bodies
+// are refused before they are hashed.
+const MaxSignedBody = 1 << 20
+
+// SigningString is the canonical form a client signs: method, request URI
+// (path and query), unix timestamp, nonce and the hex SHA-256 of the body,
+// each on its own line.
+func SigningString(method, uri, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		uri,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+func Sign(secret []byte, method, uri, timestamp, nonce string, body []byte) string {
+	return hex.EncodeToString(computeSignature(secret, method, uri, timestamp, nonce, body))
+}
+
+func computeSignature(secret []byte, method, uri, timestamp, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(SigningString(method, uri, timestamp, nonce, body)))
+
+	return mac.Sum(nil)
+}
+
+// Signature verifies HMAC signed requests. Requests that carry X-Key-Id are
+// always verified, unsigned requests are only rejected when required is set.
+// Timestamps outside maxSkew are refused and every nonce is remembered for
+// twice that window so a captured request cannot be replayed.
+func Signature(secrets map[string]string, maxSkew time.Duration, required bool) gin.HandlerFunc {
+	nonces := newNonceCache(2 * maxSkew)
+
+	return func(c *gin.Context) {
+
+		keyId := c.GetHeader(HeaderKeyId)
+
+		if keyId == "" {
+			if required {
+				abort(c, apperror.Unauthorized("signature.required"))
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		secret, ok := secrets[keyId]
+		if !ok {
+			abort(c, apperror.Unauthorized("signature.unknown_key"))
+			return
+		}
+
+		timestamp := c.GetHeader(HeaderTimestamp)
+		nonce := c.GetHeader(HeaderNonce)
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || nonce == "" {
+			abort(c, apperror.Unauthorized("signature.invalid"))
+			return
+		}
+
+		now := time.Now()
+		signedAt := time.Unix(unix, 0)
+
+		if signedAt.Before(now.Add(-maxSkew)) || signedAt.After(now.Add(maxSkew)) {
+			abort(c, apperror.Unauthorized("signature.stale"))
+			return
+		}
+
+		var body []byte
+
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxSignedBody))
+
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abort(c, apperror.TooLarge("signature.body_too_large").With(i18n.Params{"limit": MaxSignedBody}))
+				return
+			}
+
+			if err != nil {
+				abort(c, apperror.BadRequest("signature.read_body_failed"))
+				return
+			}
+
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+
+		expected := computeSignature([]byte(secret), c.Request.Method, c.Request.URL.RequestURI(), timestamp, nonce, body)
+
+		signature, err := hex.DecodeString(c.GetHeader(HeaderSignature))
+		if err != nil || !hmac.Equal(signature, expected) {
+			abort(c, apperror.Unauthorized("signature.invalid"))
+			return
+		}
+
+		if !nonces.add(keyId+":"+nonce, now) {
+			abort(c, apperror.Unauthorized("signature.nonce_used"))
+			return
+		}
+
+		c.Set("signing_key_id", keyId)
+
+		c.Next()
+	}
+}
+
+type nonceCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	seenAt map[string]time.Time
+	queue  []seenNonce
+}
+
+// seenNonce is a queue entry, nonces are queued in the order they are seen
+// so the expired ones are always at the front.
+type seenNonce struct {
+	nonce string
+	at    time.Time
+}
+
+func newNonceCache(ttl time.Duration) *nonceCache {
+	return &nonceCache{ttl: ttl, seenAt: map[string]time.Time{}}
+}
+
+// add records the nonce and reports false if it was already seen inside the
+// cache window. Expired entries are dropped from the front of the queue, so
+// a request only pays for the nonces that expired since the last one.
+func (n *nonceCache) add(nonce string, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	expired := 0
+	for expired < len(n.queue) && now.Sub(n.queue[expired].at) > n.ttl {
+		delete(n.seenAt, n.queue[expired].nonce)
+		expired++
+	}
+
+	n.queue = n.queue[expired:]
+
+	if _, ok := n.seenAt[nonce]; ok {
+		return false
+	}
+
+	n.seenAt[nonce] = now
+	n.queue = append(n.queue, seenNonce{nonce: nonce, at: now})
+
+	return true
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ilhaamms/library-api/db"
+	"github.com/ilhaamms/library-api/migration"
+	"gorm.io/gorm"
+)
+
+func newMigrator(database *gorm.DB) (*migration.Migrator, error) {
+	err := db.RequireFTS5(database)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := db.MigrationsFor(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return migration.New(database, migrations)
+}
+
+// runMigrate runs the migrate subcommand against the embedded migrations of
+// the configured database.
+func runMigrate(database *gorm.DB, args []string) error {
+	migrator, err := newMigrator(database)
+	if err != nil {
+		return err
+	}
+
+	return migration.Run(migrator, args, os.Stdout)
+}
@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+const Usage = "usage : library-api migrate [flags] up | down [N] | status | goto VERSION"
+
+// Run handles the migrate subcommand and writes what it did to out. down
+// rolls back one migration unless a count is given, goto 0 rolls back
+// everything.
+func Run(migrator *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	var ran []Migration
+	var err error
+
+	switch args[0] {
+	case "up":
+		ran, err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errors.New("jumlah langkah down harus berupa angka lebih dari 0")
+			}
+		}
+
+		ran, err = migrator.Down(steps)
+	case "goto":
+		if len(args) < 2 {
+			return errors.New(Usage)
+		}
+
+		var version int64
+
+		version, err = strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("versi migrasi harus berupa angka")
+		}
+
+		ran, err = migrator.Goto(version)
+	case "status":
+		return printStatus(migrator, out)
+	default:
+		return errors.New(Usage)
+	}
+
+	for _, m := range ran {
+		fmt.Fprintf(out, "%d_%s\n", m.Version, m.Name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	version, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "versi schema sekarang : %d\n", version)
+
+	return nil
+}
+
+func printStatus(migrator *Migrator, out io.Writer) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+
+	for _, s := range status {
+		fmt.Fprintf(w, "%d\t%s\t%t\n", s.Version, s.Name, s.Applied)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,326 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+var ErrDirty = errors.New("database dalam keadaan dirty, perbaiki schema secara manual lalu perbarui tabel schema_migrations")
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// Migrator applies the SQL files of fsys and records the current version in
+// schema_migrations, the same table the golang-migrate CLI uses, so databases
+// migrated by the CLI keep working.
+type Migrator struct {
+	db         *gorm.DB
+	dialect    string
+	migrations []Migration
+}
+
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	m := &Migrator{db: db, dialect: db.Dialector.Name()}
+
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("migrasi %d_%s tidak memiliki file up", migration.Version, migration.Name)
+		}
+
+		m.migrations = append(m.migrations, *migration)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	err = m.createVersionTable()
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *Migrator) createVersionTable() error {
+	if m.dialect != "sqlite" {
+		return m.db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)").Error
+	}
+
+	err := m.db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version uint64, dirty bool)").Error
+	if err != nil {
+		return err
+	}
+
+	return m.db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON schema_migrations (version)").Error
+}
+
+// Version returns the version of the last applied migration, 0 when the
+// schema is empty.
+func (m *Migrator) Version() (int64, error) {
+	var rows []struct {
+		Version int64
+		Dirty   bool
+	}
+
+	err := m.db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	if rows[0].Dirty {
+		return rows[0].Version, ErrDirty
+	}
+
+	return rows[0].Version, nil
+}
+
+func (m *Migrator) Status() ([]Status, error) {
+	current, err := m.Version()
+	if err != nil && !errors.Is(err, ErrDirty) {
+		return nil, err
+	}
+
+	status := []Status{}
+
+	for _, migration := range m.migrations {
+		status = append(status, Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= current,
+		})
+	}
+
+	return status, nil
+}
+
+// Up applies every pending migration and returns the ones it ran.
+func (m *Migrator) Up() ([]Migration, error) {
+	if len(m.migrations) == 0 {
+		return nil, nil
+	}
+
+	return m.Goto(m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down rolls back the given number of applied migrations.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	current, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	index := m.indexOf(current)
+	if current != 0 && index < 0 {
+		return nil, fmt.Errorf("versi migrasi %d tidak ditemukan", current)
+	}
+
+	target := int64(0)
+	if index-steps >= 0 {
+		target = m.migrations[index-steps].Version
+	}
+
+	return m.Goto(target)
+}
+
+// Goto migrates up or down until version is the last applied migration.
+// Version 0 rolls back everything.
+func (m *Migrator) Goto(version int64) ([]Migration, error) {
+	if version != 0 && m.indexOf(version) < 0 {
+		return nil, fmt.Errorf("versi migrasi %d tidak ditemukan", version)
+	}
+
+	current, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	ran := []Migration{}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= current || migration.Version > version {
+			continue
+		}
+
+		err = m.run(migration, migration.up, migration.Version)
+		if err != nil {
+			return ran, err
+		}
+
+		ran = append(ran, migration)
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current || migration.Version <= version {
+			continue
+		}
+
+		if migration.down == "" {
+			return ran, fmt.Errorf("migrasi %d_%s tidak memiliki file down", migration.Version, migration.Name)
+		}
+
+		previous := int64(0)
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+
+		err = m.run(migration, migration.down, previous)
+		if err != nil {
+			return ran, err
+		}
+
+		ran = append(ran, migration)
+	}
+
+	return ran, nil
+}
+
+func (m *Migrator) indexOf(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Migrator) run(migration Migration, script string, version int64) error {
+	switch m.dialect {
+	case "sqlite":
+		return m.runSQLite(migration, script, version)
+	case "mysql":
+		return m.runDirty(migration, script, version)
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(script).Error
+		if err != nil {
+			return fmt.Errorf("migrasi %d_%s gagal : %w", migration.Version, migration.Name, err)
+		}
+
+		return setVersion(tx, version, false)
+	})
+}
+
+// runSQLite executes the script and records the new version in the same
+// transaction. Foreign keys are switched off on the pinned connection so
+// scripts can rebuild referenced tables, then checked before commit.
+func (m *Migrator) runSQLite(migration Migration, script string, version int64) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		err := conn.Exec("PRAGMA foreign_keys = OFF").Error
+		if err != nil {
+			return err
+		}
+
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			err := tx.Exec(script).Error
+			if err != nil {
+				return fmt.Errorf("migrasi %d_%s gagal : %w", migration.Version, migration.Name, err)
+			}
+
+			var violations []map[string]interface{}
+			err = tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error
+			if err != nil {
+				return err
+			}
+
+			if len(violations) > 0 {
+				return fmt.Errorf("migrasi %d_%s melanggar foreign key", migration.Version, migration.Name)
+			}
+
+			return setVersion(tx, version, false)
+		})
+	})
+}
+
+// runDirty is used where DDL commits implicitly (MySQL). The version is
+// marked dirty before the script runs, so a migration that fails halfway is
+// reported instead of being silently skipped on the next run.
+func (m *Migrator) runDirty(migration Migration, script string, version int64) error {
+	err := setVersion(m.db, migration.Version, true)
+	if err != nil {
+		return err
+	}
+
+	err = m.db.Exec(script).Error
+	if err != nil {
+		return fmt.Errorf("migrasi %d_%s gagal : %w", migration.Version, migration.Name, err)
+	}
+
+	return setVersion(m.db, version, false)
+}
+
+func setVersion(db *gorm.DB, version int64, dirty bool) error {
+	err := db.Exec("DELETE FROM schema_migrations").Error
+	if err != nil {
+		return err
+	}
+
+	if version == 0 && !dirty {
+		return nil
+	}
+
+	return db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+// AuditRepository is append only: entries are written once and never
+// changed, the audit_log table refuses updates and deletes as well.
+type AuditRepository interface {
+	Save(entry response.AuditEntry) error
+	FindAll(query request.AuditQuery) ([]response.AuditEntry, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Save(entry response.AuditEntry) error {
+	return r.db.Table("audit_log").Create(&entry).Error
+}
+
+// FindAll lists the entries matching the query, the most recent first.
+func (r *auditRepository) FindAll(query request.AuditQuery) ([]response.AuditEntry, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		if query.Entity != "" {
+			db = db.Where("entity = ?", query.Entity)
+		}
+
+		if query.Id != 0 {
+			db = db.Where("entity_id = ?", query.Id)
+		}
+
+		if query.Actor != "" {
+			db = db.Where("actor = ?", query.Actor)
+		}
+
+		return db
+	}
+
+	var totalItems int64
+	err := r.db.Table("audit_log").Scopes(filter).Count(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []response.AuditEntry
+	err = r.db.Table("audit_log").
+		Scopes(filter).
+		Order("created_at DESC, id DESC").
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, totalItems, nil
+}
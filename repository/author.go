@@ -1,17 +1,49 @@
 package repository
 
 import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
 	"gorm.io/gorm"
 )
 
 type AuthorRepository interface {
-	Save(author request.CreateAuthor) error
-	FindAll() ([]response.Author, error)
+	Save(author request.CreateAuthor) (response.Author, error)
+	FindAll(query request.AuthorQuery) ([]response.Author, int64, error)
 	FindById(id int) (response.Author, error)
-	DeleteById(id int) (*response.Author, error)
-	UpdateById(id int, author request.UpdateAuthor) (*response.Author, error)
+	DeleteById(id, version int, options request.DeleteAuthor) (*response.AuthorDeletion, error)
+	UpdateById(id, version int, author request.UpdateAuthor) (*response.Author, error)
+	Restore(id int) (*response.Author, error)
+}
+
+var (
+	ErrAuthorHasBooks   = apperror.Conflict("author.has_books")
+	ErrReassignNotFound = apperror.Invalid("to", "author.reassign_not_found")
+)
+
+// liveBooks are the books an author wrote or contributed to that are not in
+// the trash.
+const liveBooks = `deleted_at IS NULL AND (author_id = ? OR id IN (SELECT book_id FROM book_contributor WHERE author_id = ?))`
+
+var AuthorSortFields = []string{"id", "name", "birth_date"}
+
+var authorSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"birth_date": "birth_date",
+}
+
+// authorRow is a new author, the birth date is kept as the YYYY-MM-DD the
+// client sent.
+type authorRow struct {
+	ID        int
+	Name      string
+	BirthDate string `gorm:"column:birth_date"`
 }
 
 type authorRepository struct {
@@ -22,28 +54,59 @@ func NewAuthorRepository(db *gorm.DB) AuthorRepository {
 	return &authorRepository{db}
 }
 
-func (r *authorRepository) Save(author request.CreateAuthor) error {
-	err := r.db.Table("author").Create(&author).Error
+func (r *authorRepository) Save(author request.CreateAuthor) (response.Author, error) {
+	row := authorRow{Name: author.Name, BirthDate: author.Birthdate}
+
+	err := r.db.Table("author").Create(&row).Error
 	if err != nil {
-		return err
+		return response.Author{}, err
 	}
 
-	return nil
+	return r.FindById(row.ID)
 }
 
-func (r *authorRepository) FindAll() ([]response.Author, error) {
+func (r *authorRepository) FindAll(query request.AuthorQuery) ([]response.Author, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("deleted_at IS NULL")
+
+		if query.Name != "" {
+			db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(query.Name)+"%")
+		}
+
+		if query.BirthDateFrom != "" {
+			db = db.Where("birth_date >= ?", query.BirthDateFrom)
+		}
+
+		if query.BirthDateTo != "" {
+			db = db.Where("birth_date <= ?", query.BirthDateTo)
+		}
+
+		return db
+	}
+
+	var totalItems int64
+	err := r.db.Table("author").Scopes(filter).Count(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var authors []response.Author
-	err := r.db.Table("author").Find(&authors).Error
+	err = r.db.Table("author").
+		Scopes(filter).
+		Order(authorSortColumns[query.Sort] + " " + query.Order).
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&authors).Error
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return authors, nil
+	return authors, totalItems, nil
 }
 
 func (r *authorRepository) FindById(id int) (response.Author, error) {
 	var author response.Author
-	err := r.db.Table("author").Where("id = ?", id).First(&author).Error
+	err := r.db.Table("author").Where("id = ? AND deleted_at IS NULL", id).First(&author).Error
 	if err != nil {
 		return response.Author{}, err
 	}
@@ -51,27 +114,155 @@ func (r *authorRepository) FindById(id int) (response.Author, error) {
 	return author, nil
 }
 
-func (r *authorRepository) DeleteById(id int) (*response.Author, error) {
+// DeleteById moves the author to the trash, in one transaction with what
+// the strategy does to the books they wrote or contributed to: restrict
+// fails with ErrAuthorHasBooks carrying the books, cascade moves the books
+// to the trash as well and reassign hands them over to the author to.
+func (r *authorRepository) DeleteById(id, version int, options request.DeleteAuthor) (*response.AuthorDeletion, error) {
+	deletion := response.AuthorDeletion{
+		Strategy:        options.Strategy,
+		DeletedBooks:    []response.AuthorBookRef{},
+		ReassignedBooks: []response.AuthorBookRef{},
+	}
 
-	var author response.Author
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		txRepo := &authorRepository{tx}
+
+		author, err := txRepo.FindById(id)
+		if err != nil {
+			return err
+		}
+
+		if version != 0 && version != author.Version {
+			return ErrVersionMismatch
+		}
+
+		books := []response.AuthorBookRef{}
+		err = tx.Table("book").Select("id, title, isbn").Where(liveBooks, id, id).Order("id").Find(&books).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+
+		switch options.Strategy {
+		case data.AuthorDeleteCascade:
+			err = checkCirculation(tx, bookRefIds(books))
+			if err == nil {
+				err = trashBooks(tx, books, now)
+			}
+
+			deletion.DeletedBooks = books
+		case data.AuthorDeleteReassign:
+			var target response.Author
+			target, err = txRepo.FindById(options.To)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReassignNotFound
+			}
+
+			if err == nil {
+				err = reassignBooks(tx, id, options.To, books)
+			}
+
+			deletion.ReassignedBooks = books
+			deletion.ReassignedTo = &target
+		default:
+			if len(books) > 0 {
+				return ErrAuthorHasBooks.WithData(books)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result := whereVersion(tx.Table("author"), id, author.Version).Updates(map[string]interface{}{
+			"deleted_at": now,
+			"version":    nextVersion,
+		})
+		err = swapped(tx, "author", id, result)
+		if err != nil {
+			return err
+		}
+
+		deletion.Author = author
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &deletion, nil
+}
+
+// trashBooks moves the books to the trash along with their author.
+func trashBooks(tx *gorm.DB, books []response.AuthorBookRef, now time.Time) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	return tx.Table("book").Where("id IN ?", bookRefIds(books)).Updates(map[string]interface{}{
+		"deleted_at": now,
+		"version":    nextVersion,
+	}).Error
+}
+
+// reassignBooks makes to the author of the books instead of from, both as
+// their main author and as contributor. A role from already shares with to
+// on a book is dropped rather than listed twice.
+func reassignBooks(tx *gorm.DB, from, to int, books []response.AuthorBookRef) error {
+	if len(books) == 0 {
+		return nil
+	}
 
-	err := r.db.Table("author").Where("id = ?", id).First(&author).Error
+	ids := bookRefIds(books)
+
+	err := tx.Table("book").Where("id IN ? AND author_id = ?", ids, from).Update("author_id", to).Error
 	if err != nil {
-		return &author, err
+		return err
 	}
 
-	err = r.db.Table("author").Where("id = ?", id).Delete(&response.Author{}).Error
+	// the derived table lets MySQL read book_contributor while deleting from it
+	err = tx.Exec(`DELETE FROM book_contributor WHERE author_id = ? AND book_id IN ? AND (book_id, role) IN
+		(SELECT book_id, role FROM (SELECT book_id, role FROM book_contributor WHERE author_id = ?) AS kept)`, from, ids, to).Error
 	if err != nil {
-		return &author, err
+		return err
 	}
 
-	return &author, nil
+	err = tx.Table("book_contributor").Where("author_id = ? AND book_id IN ?", from, ids).Update("author_id", to).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Table("book").Where("id IN ?", ids).Update("version", nextVersion).Error
 }
 
-func (r *authorRepository) UpdateById(id int, author request.UpdateAuthor) (*response.Author, error) {
+func bookRefIds(books []response.AuthorBookRef) []int {
+	ids := make([]int, len(books))
+	for i, book := range books {
+		ids[i] = book.Id
+	}
+
+	return ids
+}
+
+// UpdateById changes the fields of the author that are not empty. Unless
+// version is 0 the author is only updated while it is at that version.
+func (r *authorRepository) UpdateById(id, version int, author request.UpdateAuthor) (*response.Author, error) {
 	var authorResponse response.Author
 
-	err := r.db.Table("author").Where("id = ?", id).Updates(&author).Error
+	columns := map[string]interface{}{"version": nextVersion}
+	if author.Name != "" {
+		columns["name"] = author.Name
+	}
+
+	if author.Birthdate != "" {
+		columns["birth_date"] = author.Birthdate
+	}
+
+	result := whereVersion(r.db.Table("author"), id, version).Updates(columns)
+	err := swapped(r.db, "author", id, result)
 	if err != nil {
 		return &authorResponse, err
 	}
@@ -83,3 +274,25 @@ func (r *authorRepository) UpdateById(id int, author request.UpdateAuthor) (*res
 
 	return &authorResponse, nil
 }
+
+// Restore takes the author out of the trash.
+func (r *authorRepository) Restore(id int) (*response.Author, error) {
+	result := r.db.Table("author").Where("id = ? AND deleted_at IS NOT NULL", id).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    nextVersion,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	author, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &author, nil
+}
@@ -1,18 +1,83 @@
 package repository
 
 import (
+	"errors"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/isbn"
 	"gorm.io/gorm"
 )
 
 type BookRepository interface {
-	Save(book request.CreateBook) error
+	Save(book request.CreateBook) (response.Book, error)
 	FindBookByIsbn(isbn string) (response.Book, error)
-	FindAll() ([]response.Book, error)
+	FindAll(query request.BookQuery) ([]response.Book, int64, error)
 	FindById(id int) (response.Book, error)
-	Delete(id int) (*response.ResultBook, error)
-	Update(id int, book request.UpdateBook) (*response.ResultBook, error)
+	Delete(id, version int) (*response.ResultBook, error)
+	Update(id, version int, book request.UpdateBook) (*response.ResultBook, error)
+	Search(query request.SearchBook) ([]response.SearchBook, int64, error)
+	Restore(id int) (*response.ResultBook, error)
+}
+
+var (
+	ErrEditionNotFound = apperror.NotFound("book.edition_not_found")
+	ErrAuthorNotFound  = apperror.Invalid("author_id", "book.author_not_found")
+	ErrAuthorInTrash   = apperror.Conflict("book.author_in_trash")
+	ErrBookCirculating = apperror.Conflict("book.circulating")
+)
+
+const bookColumns = `b.id, b.title, b.isbn, a.id AS author_id, a.name AS author_name, a.birth_date,
+	(SELECT COUNT(*) FROM book_copy AS c WHERE c.book_id = b.id) AS total_copies,
+	(SELECT COUNT(*) FROM book_copy AS c WHERE c.book_id = b.id AND c.status = 'available') AS available_copies,
+	b.publisher_id, p.name AS publisher_name, b.publication_year, b.edition, b.page_count,
+	b.language, b.format, b.description, b.work_id, b.version`
+
+const bookJoins = `INNER JOIN author AS a ON b.author_id = a.id
+	LEFT JOIN publisher AS p ON b.publisher_id = p.id`
+
+var BookSortFields = []string{"id", "title", "isbn", "author_id", "publication_year"}
+
+var bookSortColumns = map[string]string{
+	"id":               "b.id",
+	"title":            "b.title",
+	"isbn":             "b.isbn",
+	"author_id":        "b.author_id",
+	"publication_year": "b.publication_year",
+}
+
+type bookRow struct {
+	Id              int
+	Title           string
+	Isbn            string
+	AuthorId        int
+	PublisherId     *int
+	PublicationYear *int
+	Edition         string
+	PageCount       *int
+	Language        string
+	Format          string
+	Description     string
+	WorkId          *int
+}
+
+type workRow struct {
+	Id    int
+	Title string
+}
+
+type bookContributorRow struct {
+	BookId   int
+	AuthorId int
+	Name     string `gorm:"->"`
+	Role     string
+	Position int
 }
 
 type bookRepository struct {
@@ -23,25 +88,67 @@ func NewBookRepository(db *gorm.DB) BookRepository {
 	return &bookRepository{db: db}
 }
 
-func (r *bookRepository) Save(book request.CreateBook) error {
-	bookData := request.CreateBook{
-		Title:    book.Title,
-		Isbn:     book.Isbn,
-		AuthorId: book.AuthorId,
-	}
+// Save stores the book together with its contributors. book.author_id keeps
+// the first contributor so the search index and older clients still have a
+// primary author.
+func (r *bookRepository) Save(book request.CreateBook) (response.Book, error) {
+	var id int
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := checkAuthors(tx, book.Contributors)
+		if err != nil {
+			return err
+		}
 
-	err := r.db.Table("book").Create(&bookData).Error
+		row := bookRow{
+			Title:           book.Title,
+			Isbn:            book.Isbn,
+			AuthorId:        book.AuthorId,
+			PublisherId:     nullable(book.PublisherId),
+			PublicationYear: nullable(book.PublicationYear),
+			Edition:         book.Edition,
+			PageCount:       nullable(book.PageCount),
+			Language:        book.Language,
+			Format:          book.Format,
+			Description:     book.Description,
+		}
+
+		if book.EditionOf != 0 {
+			workId, err := workOf(tx, book.EditionOf)
+			if err != nil {
+				return err
+			}
+
+			row.WorkId = &workId
+		}
+
+		err = tx.Table("book").Create(&row).Error
+		if err != nil {
+			return err
+		}
+
+		err = saveCategories(tx, row.Id, book.CategoryIds)
+		if err != nil {
+			return err
+		}
+
+		id = row.Id
+
+		return saveContributors(tx, row.Id, book.Contributors)
+	})
 	if err != nil {
-		return err
+		return response.Book{}, err
 	}
 
-	return nil
+	return r.FindById(id)
 }
 
-func (r *bookRepository) FindBookByIsbn(isbn string) (response.Book, error) {
+// FindBookByIsbn also finds books in the trash, their ISBN is still taken
+// until they are purged.
+func (r *bookRepository) FindBookByIsbn(value string) (response.Book, error) {
 	var book response.Book
 
-	err := r.db.Table("book").Where("isbn = ?", isbn).First(&book).Error
+	err := r.db.Table("book").Where("isbn IN ?", isbnForms(value)).First(&book).Error
 	if err != nil {
 		return book, err
 	}
@@ -49,93 +156,659 @@ func (r *bookRepository) FindBookByIsbn(isbn string) (response.Book, error) {
 	return book, nil
 }
 
-func (r *bookRepository) FindAll() ([]response.Book, error) {
-	var books []response.Book
+func (r *bookRepository) FindAll(query request.BookQuery) ([]response.Book, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Joins(bookJoins).Where("b.deleted_at IS NULL")
 
-	err := r.db.Table("book AS b").
-		Select("b.id, b.title, b.isbn, a.id AS author_id, a.name AS author_name, a.birth_date").
-		Joins("INNER JOIN author AS a on b.author_id = a.id").
+		if query.Title != "" {
+			db = db.Where("LOWER(b.title) LIKE ?", "%"+strings.ToLower(query.Title)+"%")
+		}
+
+		if query.Isbn != "" {
+			db = db.Where("b.isbn IN ?", isbnForms(query.Isbn))
+		}
+
+		if query.AuthorId != 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM book_contributor AS bc WHERE bc.book_id = b.id AND bc.author_id = ?)", query.AuthorId)
+		}
+
+		if query.PublisherId != 0 {
+			db = db.Where("b.publisher_id = ?", query.PublisherId)
+		}
+
+		if query.WorkId != 0 {
+			db = db.Where("b.work_id = ?", query.WorkId)
+		}
+
+		if query.Language != "" {
+			db = db.Where("b.language = ?", query.Language)
+		}
+
+		if query.Format != "" {
+			db = db.Where("b.format = ?", query.Format)
+		}
+
+		if query.YearFrom != 0 {
+			db = db.Where("b.publication_year >= ?", query.YearFrom)
+		}
+
+		if query.YearTo != 0 {
+			db = db.Where("b.publication_year <= ?", query.YearTo)
+		}
+
+		if query.CategoryId != 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM book_category AS bcat WHERE bcat.book_id = b.id AND bcat.category_id IN ("+categoryTree+"))", query.CategoryId)
+		}
+
+		return db
+	}
+
+	var totalItems int64
+	err := r.db.Table("book AS b").Scopes(filter).Count(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var books []response.Book
+	err = r.db.Table("book AS b").
+		Select(bookColumns).
+		Scopes(filter).
+		Order(bookSortColumns[query.Sort] + " " + query.Order).
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
 		Find(&books).Error
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	err = r.findContributors(books)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = r.findCategories(books)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return books, nil
+	return books, totalItems, nil
 }
 
 func (r *bookRepository) FindById(id int) (response.Book, error) {
 	var book response.Book
 
 	err := r.db.Table("book AS b").
-		Select("b.id, b.title, b.isbn, a.id AS author_id, a.name AS author_name, a.birth_date").
-		Joins("INNER JOIN author AS a on b.author_id = a.id").
-		Where("b.id = ?", id).
+		Select(bookColumns).
+		Joins(bookJoins).
+		Where("b.id = ? AND b.deleted_at IS NULL", id).
 		First(&book).Error
 
 	if err != nil {
 		return book, err
 	}
 
-	return book, nil
-}
+	books := []response.Book{book}
 
-func (r *bookRepository) Delete(id int) (*response.ResultBook, error) {
+	err = r.findContributors(books)
+	if err != nil {
+		return book, err
+	}
 
-	var book response.Book
-	err := r.db.Table("book AS b").
-		Select("b.id, b.title, b.isbn, a.id AS author_id, a.name AS author_name, a.birth_date").
-		Joins("INNER JOIN author AS a on b.author_id = a.id").
-		Where("b.id = ?", id).
-		First(&book).Error
+	err = r.findCategories(books)
+	if err != nil {
+		return book, err
+	}
+
+	return books[0], nil
+}
+
+// Delete moves the book to the trash, version 0 deletes whatever version is
+// current. The book is only removed for good when the trash is purged.
+func (r *bookRepository) Delete(id, version int) (*response.ResultBook, error) {
 
+	book, err := r.FindById(id)
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.Table("book").Where("id = ?", id).Delete(&response.Book{}).Error
+	if version != 0 && version != book.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err := checkCirculation(tx, []int{id})
+		if err != nil {
+			return err
+		}
+
+		result := whereVersion(tx.Table("book"), id, book.Version).Updates(map[string]interface{}{
+			"deleted_at": time.Now().UTC(),
+			"version":    nextVersion,
+		})
+
+		return swapped(tx, "book", id, result)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &response.ResultBook{
-		Id:    book.Id,
-		Title: book.Title,
-		Isbn:  book.Isbn,
-		AuthorBook: response.AuthorBook{
-			ID:        book.AuthorId,
-			Name:      book.AuthorName,
-			BirthDate: book.BirthDate,
-		},
-	}, nil
+	deleted := book.Result()
+
+	return &deleted, nil
+}
+
+// checkCirculation fails with ErrBookCirculating while a copy of one of the
+// books is on loan or one of them has a waiting or ready hold. Copies of a
+// trashed book leave circulation, so nothing could be returned or handed on.
+func checkCirculation(tx *gorm.DB, bookIds []int) error {
+	if len(bookIds) == 0 {
+		return nil
+	}
+
+	var loans int64
+	err := tx.Table("loan AS l").
+		Joins("INNER JOIN book_copy AS c ON c.id = l.copy_id").
+		Where("c.book_id IN ? AND l.returned_at IS NULL", bookIds).
+		Count(&loans).Error
+	if err != nil {
+		return err
+	}
+
+	var holds int64
+	err = tx.Table("hold").
+		Where("book_id IN ? AND status IN ?", bookIds, []string{data.HoldStatusWaiting, data.HoldStatusReady}).
+		Count(&holds).Error
+	if err != nil {
+		return err
+	}
+
+	if loans > 0 || holds > 0 {
+		return ErrBookCirculating
+	}
+
+	return nil
 }
 
-func (r *bookRepository) Update(id int, book request.UpdateBook) (*response.ResultBook, error) {
-	var bookResponse response.Book
+// Restore takes the book out of the trash. A book whose authors are still
+// in the trash cannot be restored before them.
+func (r *bookRepository) Restore(id int) (*response.ResultBook, error) {
+	var book bookRow
 
-	err := r.db.Table("book").Where("id = ?", id).Updates(&book).Error
+	err := r.db.Table("book").Where("id = ? AND deleted_at IS NOT NULL", id).First(&book).Error
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.Table("book AS b").
-		Select("b.id, b.title, b.isbn, a.id AS author_id, a.name AS author_name, a.birth_date").
-		Joins("INNER JOIN author AS a on b.author_id = a.id").
-		Where("b.id = ?", id).
-		First(&bookResponse).Error
+	var trashedAuthors int64
+	err = r.db.Table("author").
+		Where("deleted_at IS NOT NULL AND (id = ? OR id IN (SELECT author_id FROM book_contributor WHERE book_id = ?))", book.AuthorId, id).
+		Count(&trashedAuthors).Error
+	if err != nil {
+		return nil, err
+	}
 
+	if trashedAuthors > 0 {
+		return nil, ErrAuthorInTrash
+	}
+
+	err = r.db.Table("book").Where("id = ? AND deleted_at IS NOT NULL", id).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    nextVersion,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := r.FindById(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response.ResultBook{
-		Id:    bookResponse.Id,
-		Title: bookResponse.Title,
-		Isbn:  bookResponse.Isbn,
-		AuthorBook: response.AuthorBook{
-			ID:        bookResponse.AuthorId,
-			Name:      bookResponse.AuthorName,
-			BirthDate: bookResponse.BirthDate,
-		},
-	}, nil
+	result := restored.Result()
+
+	return &result, nil
+}
+
+// Update replaces the columns of the book, a zero value clears the column.
+// Categories and contributors are only replaced when the request sends them.
+// Unless version is 0 the book is only updated while it is at that version.
+func (r *bookRepository) Update(id, version int, book request.UpdateBook) (*response.ResultBook, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := checkAuthors(tx, book.Contributors)
+		if err != nil {
+			return err
+		}
+
+		result := whereVersion(tx.Table("book"), id, version).Updates(map[string]interface{}{
+			"title":            book.Title,
+			"isbn":             book.Isbn,
+			"author_id":        book.AuthorId,
+			"publisher_id":     nullable(book.PublisherId),
+			"publication_year": nullable(book.PublicationYear),
+			"edition":          book.Edition,
+			"page_count":       nullable(book.PageCount),
+			"language":         book.Language,
+			"format":           book.Format,
+			"description":      book.Description,
+			"version":          nextVersion,
+		})
+		err = swapped(tx, "book", id, result)
+		if err != nil {
+			return err
+		}
+
+		if book.EditionOf != 0 {
+			workId, err := workOf(tx, book.EditionOf)
+			if err != nil {
+				return err
+			}
+
+			err = tx.Table("book").Where("id = ?", id).Update("work_id", workId).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if book.CategoryIds != nil {
+			err = saveCategories(tx, id, book.CategoryIds)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(book.Contributors) == 0 {
+			return nil
+		}
+
+		return saveContributors(tx, id, book.Contributors)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bookResponse, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := bookResponse.Result()
+
+	return &result, nil
+}
+
+// saveCategories replaces the categories of a book.
+func saveCategories(tx *gorm.DB, bookId int, categoryIds []int) error {
+	err := tx.Table("book_category").Where("book_id = ?", bookId).Delete(nil).Error
+	if err != nil {
+		return err
+	}
+
+	if len(categoryIds) == 0 {
+		return nil
+	}
+
+	var found int64
+	err = tx.Table("category").Where("id IN ?", categoryIds).Count(&found).Error
+	if err != nil {
+		return err
+	}
+
+	if found != int64(len(categoryIds)) {
+		return ErrCategoryNotFound
+	}
+
+	var rows []map[string]interface{}
+	for _, categoryId := range categoryIds {
+		rows = append(rows, map[string]interface{}{"book_id": bookId, "category_id": categoryId})
+	}
+
+	return tx.Table("book_category").Create(&rows).Error
+}
+
+func (r *bookRepository) findCategories(books []response.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	var ids []int
+	for _, book := range books {
+		ids = append(ids, book.Id)
+	}
+
+	var rows []struct {
+		BookId int
+		Id     int
+		Name   string
+	}
+
+	err := r.db.Table("book_category AS bcat").
+		Select("bcat.book_id, c.id, c.name").
+		Joins("INNER JOIN category AS c ON c.id = bcat.category_id").
+		Where("bcat.book_id IN ?", ids).
+		Order("bcat.book_id, c.name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	categories := map[int][]response.BookCategory{}
+	for _, row := range rows {
+		categories[row.BookId] = append(categories[row.BookId], response.BookCategory{ID: row.Id, Name: row.Name})
+	}
+
+	for i := range books {
+		books[i].Categories = categories[books[i].Id]
+	}
+
+	return nil
+}
+
+// workOf returns the work a book belongs to. A book that is not grouped yet
+// gets a new work named after its title.
+func workOf(tx *gorm.DB, bookId int) (int, error) {
+	var book bookRow
+
+	err := tx.Table("book").Where("id = ? AND deleted_at IS NULL", bookId).First(&book).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrEditionNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if book.WorkId != nil {
+		return *book.WorkId, nil
+	}
+
+	work := workRow{Title: book.Title}
+
+	err = tx.Table("work").Create(&work).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Table("book").Where("id = ?", bookId).Update("work_id", work.Id).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return work.Id, nil
+}
+
+func nullable(value int) *int {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
+
+// checkAuthors makes sure every contributor is an author that exists and is
+// not in the trash.
+func checkAuthors(tx *gorm.DB, contributors []request.BookContributor) error {
+	authorIds := map[int]bool{}
+	for _, contributor := range contributors {
+		authorIds[contributor.AuthorId] = true
+	}
+
+	var ids []int
+	for id := range authorIds {
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var authors int64
+	err := tx.Table("author").Where("id IN ? AND deleted_at IS NULL", ids).Count(&authors).Error
+	if err != nil {
+		return err
+	}
+
+	if int(authors) != len(ids) {
+		return ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// saveContributors replaces the contributors of a book, their position
+// follows the order of the request.
+func saveContributors(tx *gorm.DB, bookId int, contributors []request.BookContributor) error {
+	err := tx.Table("book_contributor").Where("book_id = ?", bookId).Delete(nil).Error
+	if err != nil {
+		return err
+	}
+
+	var rows []bookContributorRow
+	for i, contributor := range contributors {
+		rows = append(rows, bookContributorRow{
+			BookId:   bookId,
+			AuthorId: contributor.AuthorId,
+			Role:     contributor.Role,
+			Position: i + 1,
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return tx.Table("book_contributor").Create(&rows).Error
+}
+
+func (r *bookRepository) findContributors(books []response.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	var ids []int
+	for _, book := range books {
+		ids = append(ids, book.Id)
+	}
+
+	var rows []bookContributorRow
+	err := r.db.Table("book_contributor AS bc").
+		Select("bc.book_id, bc.author_id, a.name, bc.role, bc.position").
+		Joins("INNER JOIN author AS a ON a.id = bc.author_id").
+		Where("bc.book_id IN ?", ids).
+		Order("bc.book_id, bc.position").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	contributors := map[int][]response.BookContributor{}
+	for _, row := range rows {
+		contributors[row.BookId] = append(contributors[row.BookId], response.BookContributor{
+			AuthorId: row.AuthorId,
+			Name:     row.Name,
+			Role:     row.Role,
+			Position: row.Position,
+		})
+	}
+
+	for i := range books {
+		books[i].Contributors = contributors[books[i].Id]
+	}
+
+	return nil
+}
+
+func (r *bookRepository) Search(query request.SearchBook) ([]response.SearchBook, int64, error) {
+	search := newBookSearch(r.db.Dialector.Name(), query.Q)
+
+	var totalItems int64
+	err := r.db.Raw(`SELECT COUNT(*) FROM book_search
+		INNER JOIN book AS b ON b.id = book_search.`+search.key+`
+		WHERE `+search.where+` AND b.deleted_at IS NULL`, search.match).
+		Scan(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args := append(search.columnArgs, search.match, query.Limit, (query.Page-1)*query.Limit)
+
+	var books []response.SearchBook
+	err = r.db.Raw(`SELECT `+bookColumns+`, `+search.columns+`
+		FROM book_search
+		INNER JOIN book AS b ON b.id = book_search.`+search.key+`
+		`+bookJoins+`
+		WHERE `+search.where+` AND b.deleted_at IS NULL
+		ORDER BY `+search.rank+`
+		LIMIT ? OFFSET ?`, args...).
+		Scan(&books).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range books {
+		books[i].TitleHighlight = markHighlight(books[i].TitleHighlight)
+		books[i].IsbnHighlight = markHighlight(books[i].IsbnHighlight)
+		books[i].AuthorNameHighlight = markHighlight(books[i].AuthorNameHighlight)
+	}
+
+	return books, totalItems, nil
+}
+
+// The database wraps matches in control characters rather than <mark>, so
+// the text can be HTML-escaped before the marks are put in.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightMarks = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// markHighlight escapes a highlighted column and turns its matches into
+// <mark> elements, a title can never inject markup into the highlight.
+func markHighlight(text string) string {
+	return highlightMarks.Replace(html.EscapeString(text))
+}
+
+// bookSearch holds the full-text search SQL of one dialect. Every dialect
+// ranks lower-is-better so the service can keep turning rank into a score.
+type bookSearch struct {
+	key        string
+	columns    string
+	columnArgs []interface{}
+	where      string
+	match      string
+	rank       string
+}
+
+func newBookSearch(dialect string, q string) bookSearch {
+	switch dialect {
+	case "postgres":
+		match := tsQuery(q)
+		options := `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+
+		return bookSearch{
+			key: "book_id",
+			columns: `ts_headline('simple', book_search.title, to_tsquery('simple', ?), ?) AS title_highlight,
+			ts_headline('simple', book_search.isbn, to_tsquery('simple', ?), ?) AS isbn_highlight,
+			ts_headline('simple', book_search.author_name, to_tsquery('simple', ?), ?) AS author_name_highlight,
+			-ts_rank(book_search.document, to_tsquery('simple', ?)) AS rank`,
+			columnArgs: []interface{}{match, options, match, options, match, options, match},
+			where:      "book_search.document @@ to_tsquery('simple', ?)",
+			match:      match,
+			rank:       "rank",
+		}
+	case "mysql":
+		// MySQL has no highlight function, the highlights carry the plain
+		// column values.
+		return bookSearch{
+			key: "book_id",
+			columns: `book_search.title AS title_highlight,
+			book_search.isbn AS isbn_highlight,
+			book_search.author_name AS author_name_highlight,
+			-MATCH(book_search.title, book_search.isbn, book_search.author_name) AGAINST(? IN BOOLEAN MODE) AS ` + "`rank`",
+			columnArgs: []interface{}{booleanQuery(q)},
+			where:      "MATCH(book_search.title, book_search.isbn, book_search.author_name) AGAINST(? IN BOOLEAN MODE)",
+			match:      booleanQuery(q),
+			rank:       "`rank`",
+		}
+	}
+
+	return bookSearch{
+		key: "rowid",
+		columns: `highlight(book_search, 0, ?, ?) AS title_highlight,
+			highlight(book_search, 1, ?, ?) AS isbn_highlight,
+			highlight(book_search, 2, ?, ?) AS author_name_highlight,
+			bm25(book_search, 10.0, 5.0, 2.0) AS rank`,
+		columnArgs: []interface{}{highlightStart, highlightStop, highlightStart, highlightStop, highlightStart, highlightStop},
+		where:      "book_search MATCH ?",
+		match:      matchExpression(q),
+		rank:       "rank",
+	}
+}
+
+// matchExpression turns free text into an FTS5 query where every word is a
+// quoted prefix term, so user input can never break the MATCH syntax.
+func matchExpression(q string) string {
+	var terms []string
+	for _, word := range strings.Fields(q) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// tsQuery is the PostgreSQL counterpart of matchExpression, every word must
+// match as a prefix.
+func tsQuery(q string) string {
+	var terms []string
+	for _, word := range searchWords(q) {
+		terms = append(terms, word+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// booleanQuery is the MySQL counterpart of matchExpression, every word must
+// match as a prefix.
+func booleanQuery(q string) string {
+	var terms []string
+	for _, word := range searchWords(q) {
+		terms = append(terms, "+"+word+"*")
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// searchWords keeps only letters and digits, the operators of tsquery and
+// boolean mode can not be escaped.
+func searchWords(q string) []string {
+	var words []string
+	for _, word := range strings.Fields(q) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, word)
+
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// isbnForms returns an ISBN together with its ISBN-10 form, so books saved
+// before ISBNs were stored as ISBN-13 are still found.
+func isbnForms(value string) []string {
+	forms := []string{value}
+
+	isbn10, err := isbn.To10(value)
+	if err == nil && isbn10 != value {
+		forms = append(forms, isbn10)
+	}
+
+	return forms
 }
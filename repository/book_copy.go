@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCopyInCirculation = apperror.Conflict("copy.status_in_circulation")
+	ErrCopyNotOnShelf    = apperror.Conflict("copy.delete_not_on_shelf")
+	ErrCopyHasLoans      = apperror.Conflict("copy.delete_has_loans")
+)
+
+type BookCopyRepository interface {
+	Save(bookCopy request.CreateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error)
+	FindAllByBookId(bookId int) ([]response.BookCopy, error)
+	FindById(bookId, id int) (response.BookCopy, error)
+	FindByBarcode(barcode string) (response.BookCopy, error)
+	Update(bookId, id int, bookCopy request.UpdateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error)
+	Delete(bookId, id int) (*response.BookCopy, error)
+}
+
+// onLiveBook keeps the rows of books that are not in the trash, copies and
+// holds of a trashed book take no part in circulation until it is restored.
+const onLiveBook = "book_id IN (SELECT id FROM book WHERE deleted_at IS NULL)"
+
+func liveCopies(db *gorm.DB) *gorm.DB {
+	return db.Table("book_copy").Where(onLiveBook)
+}
+
+type bookCopyRepository struct {
+	db *gorm.DB
+}
+
+func NewBookCopyRepository(db *gorm.DB) BookCopyRepository {
+	return &bookCopyRepository{db: db}
+}
+
+// Save adds a copy to the book. An available copy goes to the oldest
+// waiting hold first, like a returned one, and is only available when
+// nobody is waiting.
+func (r *bookCopyRepository) Save(bookCopy request.CreateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error) {
+	var copyResponse response.BookCopy
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("book_copy").Create(&bookCopy).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Table("book_copy").Where("barcode = ?", bookCopy.Barcode).First(&copyResponse).Error
+		if err != nil {
+			return err
+		}
+
+		if copyResponse.Status != data.CopyStatusAvailable {
+			return nil
+		}
+
+		err = passCopyToNextHold(tx, copyResponse.Id, copyResponse.BookId, now, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		return tx.Table("book_copy").Where("id = ?", copyResponse.Id).First(&copyResponse).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &copyResponse, nil
+}
+
+func (r *bookCopyRepository) FindAllByBookId(bookId int) ([]response.BookCopy, error) {
+	var copies []response.BookCopy
+
+	err := liveCopies(r.db).Where("book_id = ?", bookId).Order("id").Find(&copies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
+
+func (r *bookCopyRepository) FindById(bookId, id int) (response.BookCopy, error) {
+	var bookCopy response.BookCopy
+
+	err := liveCopies(r.db).Where("book_id = ? AND id = ?", bookId, id).First(&bookCopy).Error
+	if err != nil {
+		return bookCopy, err
+	}
+
+	return bookCopy, nil
+}
+
+// FindByBarcode also finds copies of trashed books, barcodes stay unique
+// across the trash.
+func (r *bookCopyRepository) FindByBarcode(barcode string) (response.BookCopy, error) {
+	var bookCopy response.BookCopy
+
+	err := r.db.Table("book_copy").Where("barcode = ?", barcode).First(&bookCopy).Error
+	if err != nil {
+		return bookCopy, err
+	}
+
+	return bookCopy, nil
+}
+
+// Update changes the copy. The status of a copy on loan or reserved for a
+// hold is left to its loan or hold, and a copy set back to available goes
+// through the hold queue the same way as in Save.
+func (r *bookCopyRepository) Update(bookId, id int, bookCopy request.UpdateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error) {
+	var copyResponse response.BookCopy
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := liveCopies(tx).Where("book_id = ? AND id = ?", bookId, id).First(&copyResponse).Error
+		if err != nil {
+			return err
+		}
+
+		circulating := copyResponse.Status == data.CopyStatusOnLoan || copyResponse.Status == data.CopyStatusReserved
+		if bookCopy.Status != "" && circulating {
+			return ErrCopyInCirculation
+		}
+
+		release := bookCopy.Status == data.CopyStatusAvailable && copyResponse.Status != data.CopyStatusAvailable
+		if release {
+			bookCopy.Status = ""
+		}
+
+		if bookCopy != (request.UpdateBookCopy{}) {
+			err = tx.Table("book_copy").Where("id = ?", id).Updates(&bookCopy).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if release {
+			err = passCopyToNextHold(tx, id, bookId, now, expiresAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Table("book_copy").Where("id = ?", id).First(&copyResponse).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &copyResponse, nil
+}
+
+// Delete removes a copy that is on the shelf. A copy on loan or reserved for
+// a hold belongs to that loan or hold, and a copy that was ever lent keeps
+// its loan history, mark it lost instead.
+func (r *bookCopyRepository) Delete(bookId, id int) (*response.BookCopy, error) {
+	var bookCopy response.BookCopy
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := liveCopies(tx).Where("book_id = ? AND id = ?", bookId, id).First(&bookCopy).Error
+		if err != nil {
+			return err
+		}
+
+		if bookCopy.Status == data.CopyStatusOnLoan || bookCopy.Status == data.CopyStatusReserved {
+			return ErrCopyNotOnShelf
+		}
+
+		var loans int64
+		err = tx.Table("loan").Where("copy_id = ?", id).Count(&loans).Error
+		if err != nil {
+			return err
+		}
+
+		if loans > 0 {
+			return ErrCopyHasLoans
+		}
+
+		// a checkout or hold may take the copy between the read and the delete
+		result := tx.Table("book_copy").
+			Where("id = ? AND status NOT IN ?", id, []string{data.CopyStatusOnLoan, data.CopyStatusReserved}).
+			Delete(&response.BookCopy{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCopyNotOnShelf
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookCopy, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound    = apperror.NotFound("category.not_found")
+	ErrCategoryHasChildren = apperror.Conflict("category.has_children")
+)
+
+// categoryTree selects the id of a category together with the ids of all of
+// its descendants.
+const categoryTree = `WITH RECURSIVE tree (id) AS (
+		SELECT id FROM category WHERE id = ?
+		UNION ALL
+		SELECT c.id FROM category AS c INNER JOIN tree ON c.parent_id = tree.id
+	)
+	SELECT id FROM tree`
+
+type CategoryRepository interface {
+	Save(category request.CreateCategory) (response.Category, error)
+	FindAll() ([]response.Category, error)
+	FindById(id int) (response.Category, error)
+	FindByName(parentId int, name string) (response.Category, error)
+	Descendants(id int) ([]int, error)
+	UpdateById(id int, category request.UpdateCategory) (*response.Category, error)
+	DeleteById(id int) (*response.Category, error)
+}
+
+type categoryRow struct {
+	Id       int
+	Name     string
+	ParentId *int
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Save(category request.CreateCategory) (response.Category, error) {
+	row := categoryRow{
+		Name:     category.Name,
+		ParentId: nullable(category.ParentId),
+	}
+
+	err := r.db.Table("category").Create(&row).Error
+	if err != nil {
+		return response.Category{}, err
+	}
+
+	return response.Category{ID: row.Id, Name: row.Name, ParentId: row.ParentId}, nil
+}
+
+func (r *categoryRepository) FindAll() ([]response.Category, error) {
+	var categories []response.Category
+
+	err := r.db.Table("category").Order("name, id").Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) FindById(id int) (response.Category, error) {
+	var category response.Category
+
+	err := r.db.Table("category").Where("id = ?", id).First(&category).Error
+	if err != nil {
+		return category, err
+	}
+
+	return category, nil
+}
+
+// FindByName looks for a category with the same name under the same parent,
+// parentId 0 means the top of the tree.
+func (r *categoryRepository) FindByName(parentId int, name string) (response.Category, error) {
+	var category response.Category
+
+	db := r.db.Table("category").Where("LOWER(name) = ?", strings.ToLower(name))
+	if parentId == 0 {
+		db = db.Where("parent_id IS NULL")
+	} else {
+		db = db.Where("parent_id = ?", parentId)
+	}
+
+	err := db.First(&category).Error
+	if err != nil {
+		return category, err
+	}
+
+	return category, nil
+}
+
+// Descendants returns the id of the category and of every category below it.
+func (r *categoryRepository) Descendants(id int) ([]int, error) {
+	var ids []int
+
+	err := r.db.Raw(categoryTree, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *categoryRepository) UpdateById(id int, category request.UpdateCategory) (*response.Category, error) {
+	updates := map[string]interface{}{}
+
+	if category.Name != "" {
+		updates["name"] = category.Name
+	}
+
+	if category.ParentId != nil {
+		updates["parent_id"] = nullable(*category.ParentId)
+	}
+
+	err := r.db.Table("category").Where("id = ?", id).Updates(updates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	categoryResponse, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &categoryResponse, nil
+}
+
+// DeleteById only deletes leaf categories, books assigned to the category
+// lose the assignment.
+func (r *categoryRepository) DeleteById(id int) (*response.Category, error) {
+	category, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var children int64
+	err = r.db.Table("category").Where("parent_id = ?", id).Count(&children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if children > 0 {
+		return nil, ErrCategoryHasChildren
+	}
+
+	err = r.db.Table("category").Where("id = ?", id).Delete(&response.Category{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrFineExceedsBalance = apperror.Invalid("amount", "fine.exceeds_balance")
+
+type FineRepository interface {
+	Save(entry request.FineEntry) (*response.FineEntry, error)
+	FindAllByUserId(userId int) ([]response.FineEntry, error)
+	Balance(userId, loanId int) (int64, error)
+}
+
+type fineRow struct {
+	Id         int
+	UserId     int
+	LoanId     *int
+	EntryType  string
+	Amount     int64
+	Note       string
+	RecordedBy *int
+	CreatedAt  time.Time
+}
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepository{db: db}
+}
+
+// Save appends an entry to the ledger. Payments and waivers lock the member
+// and are checked against the outstanding balance inside the same
+// transaction, so concurrent ones run in turn and the balance can never go
+// negative.
+func (r *fineRepository) Save(entry request.FineEntry) (*response.FineEntry, error) {
+	var fine response.FineEntry
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if entry.EntryType != data.FineEntryCharge {
+			err := lockMember(tx, entry.UserId)
+			if err != nil {
+				return err
+			}
+
+			balance, err := fineBalance(tx, entry.UserId, entry.LoanId)
+			if err != nil {
+				return err
+			}
+
+			if entry.Amount > balance {
+				return ErrFineExceedsBalance
+			}
+		}
+
+		row, err := insertFineEntry(tx, entry)
+		if err != nil {
+			return err
+		}
+
+		return tx.Table("fine_ledger").Where("id = ?", row.Id).First(&fine).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &fine, nil
+}
+
+func (r *fineRepository) FindAllByUserId(userId int) ([]response.FineEntry, error) {
+	var fines []response.FineEntry
+
+	err := r.db.Table("fine_ledger").Where("user_id = ?", userId).Order("id").Find(&fines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return fines, nil
+}
+
+func (r *fineRepository) Balance(userId, loanId int) (int64, error) {
+	return fineBalance(r.db, userId, loanId)
+}
+
+// lockMember holds the row of the member in the user table until the
+// transaction ends (SELECT ... FOR UPDATE). SQLite has no row locks, it
+// already lets only one transaction write at a time.
+func lockMember(tx *gorm.DB, userId int) error {
+	var id int
+
+	return tx.Table("user").Select("id").Where("id = ?", userId).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scan(&id).Error
+}
+
+// fineBalance sums the ledger of a user, or of a single loan when loanId is
+// set. Charges add to the balance, payments and waivers reduce it.
+func fineBalance(db *gorm.DB, userId, loanId int) (int64, error) {
+	var balance int64
+
+	query := db.Table("fine_ledger").
+		Select("COALESCE(SUM(CASE WHEN entry_type = ? THEN amount ELSE -amount END), 0)", data.FineEntryCharge).
+		Where("user_id = ?", userId)
+
+	if loanId > 0 {
+		query = query.Where("loan_id = ?", loanId)
+	}
+
+	err := query.Scan(&balance).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func insertFineEntry(tx *gorm.DB, entry request.FineEntry) (fineRow, error) {
+	row := fineRow{
+		UserId:    entry.UserId,
+		EntryType: entry.EntryType,
+		Amount:    entry.Amount,
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+	}
+
+	if entry.LoanId > 0 {
+		row.LoanId = &entry.LoanId
+	}
+
+	if entry.RecordedBy > 0 {
+		row.RecordedBy = &entry.RecordedBy
+	}
+
+	err := tx.Table("fine_ledger").Create(&row).Error
+
+	return row, err
+}
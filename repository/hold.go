@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHoldNotActive = apperror.NotFound("hold.not_active")
+	ErrHoldExists    = apperror.Conflict("hold.exists")
+)
+
+type HoldRepository interface {
+	Save(hold request.CreateHold) (*response.Hold, error)
+	FindById(id int) (response.Hold, error)
+	FindAllByUserId(userId int) ([]response.Hold, error)
+	Cancel(id int, now, expiresAt time.Time) (*response.Hold, error)
+	Expire(now, expiresAt time.Time) (int64, error)
+}
+
+type holdRow struct {
+	Id        int
+	BookId    int
+	UserId    int
+	Status    string
+	CreatedAt time.Time
+}
+
+type holdCopy struct {
+	Id     int
+	BookId int
+	CopyId *int
+	Status string
+}
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+const holdColumns = `h.id, h.book_id, b.title, h.user_id, u.username, h.copy_id, c.barcode, h.status,
+	CASE WHEN h.status = 'waiting' THEN (
+		SELECT COUNT(*) FROM hold AS w WHERE w.book_id = h.book_id AND w.status = 'waiting' AND w.id <= h.id
+	) ELSE 0 END AS queue_position,
+	h.created_at, h.ready_at, h.expires_at`
+
+func (r *holdRepository) holdQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("hold AS h").
+		Select(holdColumns).
+		Joins("INNER JOIN book AS b ON b.id = h.book_id").
+		Joins("INNER JOIN ? AS u ON u.id = h.user_id", userTable).
+		Joins("LEFT JOIN book_copy AS c ON c.id = h.copy_id")
+}
+
+func (r *holdRepository) Save(hold request.CreateHold) (*response.Hold, error) {
+	var count int64
+
+	err := r.db.Table("hold").
+		Where("book_id = ? AND user_id = ? AND status IN ?", hold.BookId, hold.UserId, []string{data.HoldStatusWaiting, data.HoldStatusReady}).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, ErrHoldExists
+	}
+
+	row := holdRow{
+		BookId:    hold.BookId,
+		UserId:    hold.UserId,
+		Status:    data.HoldStatusWaiting,
+		CreatedAt: hold.CreatedAt,
+	}
+
+	err = r.db.Table("hold").Create(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	holdResponse, err := r.FindById(row.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &holdResponse, nil
+}
+
+func (r *holdRepository) FindById(id int) (response.Hold, error) {
+	var hold response.Hold
+
+	err := r.holdQuery(r.db).Where("h.id = ?", id).First(&hold).Error
+	if err != nil {
+		return hold, err
+	}
+
+	return hold, nil
+}
+
+func (r *holdRepository) FindAllByUserId(userId int) ([]response.Hold, error) {
+	var holds []response.Hold
+
+	err := r.holdQuery(r.db).Where("h.user_id = ?", userId).Order("h.created_at DESC").Find(&holds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// Cancel closes an active hold. A copy that was already reserved for it is
+// handed to the next person in the queue.
+func (r *holdRepository) Cancel(id int, now, expiresAt time.Time) (*response.Hold, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var hold holdCopy
+		err := tx.Table("hold").Select("id, book_id, copy_id, status").
+			Where("id = ? AND status IN ?", id, []string{data.HoldStatusWaiting, data.HoldStatusReady}).
+			Scan(&hold).Error
+		if err != nil {
+			return err
+		}
+
+		if hold.Id == 0 {
+			return ErrHoldNotActive
+		}
+
+		err = tx.Table("hold").Where("id = ?", id).Update("status", data.HoldStatusCancelled).Error
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != data.HoldStatusReady || hold.CopyId == nil {
+			return nil
+		}
+
+		return passCopyToNextHold(tx, *hold.CopyId, hold.BookId, now, expiresAt)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// Expire closes every ready hold whose pickup window has passed and moves its
+// copy along the queue.
+func (r *holdRepository) Expire(now, expiresAt time.Time) (int64, error) {
+	var expired int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var holds []holdCopy
+		err := tx.Table("hold").Select("id, book_id, copy_id, status").
+			Where("status = ? AND expires_at < ?", data.HoldStatusReady, now).
+			Order("id").
+			Scan(&holds).Error
+		if err != nil {
+			return err
+		}
+
+		for _, hold := range holds {
+			err = tx.Table("hold").Where("id = ?", hold.Id).Update("status", data.HoldStatusExpired).Error
+			if err != nil {
+				return err
+			}
+
+			if hold.CopyId != nil {
+				err = passCopyToNextHold(tx, *hold.CopyId, hold.BookId, now, expiresAt)
+				if err != nil {
+					return err
+				}
+			}
+
+			expired++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// passCopyToNextHold reserves the copy for the oldest waiting hold on the
+// book, or makes it available again when nobody is waiting. Holds on a
+// trashed book are skipped.
+func passCopyToNextHold(tx *gorm.DB, copyId, bookId int, now, expiresAt time.Time) error {
+	var nextId int
+	err := tx.Table("hold").Select("id").
+		Where("book_id = ? AND status = ?", bookId, data.HoldStatusWaiting).
+		Where(onLiveBook).
+		Order("id").Limit(1).
+		Scan(&nextId).Error
+	if err != nil {
+		return err
+	}
+
+	if nextId == 0 {
+		return tx.Table("book_copy").Where("id = ?", copyId).Update("status", data.CopyStatusAvailable).Error
+	}
+
+	err = tx.Table("hold").Where("id = ?", nextId).Updates(map[string]interface{}{
+		"status":     data.HoldStatusReady,
+		"copy_id":    copyId,
+		"ready_at":   now,
+		"expires_at": expiresAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Table("book_copy").Where("id = ?", copyId).Update("status", data.CopyStatusReserved).Error
+}
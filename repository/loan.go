@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCopyNotFound     = apperror.NotFound("copy.not_found")
+	ErrCopyNotAvailable = apperror.Conflict("loan.copy_not_available")
+	ErrLoanNotActive    = apperror.NotFound("loan.not_active")
+	ErrLoanOnHold       = apperror.Conflict("loan.renew_on_hold")
+)
+
+type LoanRepository interface {
+	Checkout(loan request.CreateLoan) (*response.Loan, error)
+	FindById(id int) (response.Loan, error)
+	FindAllByUserId(userId int) ([]response.Loan, error)
+	Return(id int, loan request.ReturnLoan) (*response.Loan, error)
+	Renew(id int, dueDate time.Time, maxRenewals int) (*response.Loan, error)
+}
+
+type loanRow struct {
+	Id       int
+	CopyId   int
+	UserId   int
+	LoanedAt time.Time
+	DueDate  time.Time
+}
+
+type loanRepository struct {
+	db *gorm.DB
+}
+
+func NewLoanRepository(db *gorm.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+const loanColumns = `l.id, l.copy_id, c.barcode, c.book_id, b.title, l.user_id, u.username,
+	l.loaned_at, l.due_date, l.returned_at, l.renewal_count`
+
+func (r *loanRepository) loanQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("loan AS l").
+		Select(loanColumns).
+		Joins("INNER JOIN book_copy AS c ON c.id = l.copy_id").
+		Joins("INNER JOIN book AS b ON b.id = c.book_id").
+		Joins("INNER JOIN ? AS u ON u.id = l.user_id", userTable)
+}
+
+// Checkout flips the copy to on_loan only while it is still available, so two
+// concurrent checkouts of the same copy cannot both succeed. A reserved copy
+// can only be borrowed by the user whose hold it is waiting for, and copies
+// of a trashed book cannot be borrowed at all.
+func (r *loanRepository) Checkout(loan request.CreateLoan) (*response.Loan, error) {
+	var loanId int
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var copyCount int64
+		err := liveCopies(tx).Where("id = ?", loan.CopyId).Count(&copyCount).Error
+		if err != nil {
+			return err
+		}
+
+		if copyCount == 0 {
+			return ErrCopyNotFound
+		}
+
+		result := liveCopies(tx).
+			Where("id = ? AND (status = ? OR (status = ? AND EXISTS (?)))",
+				loan.CopyId, data.CopyStatusAvailable, data.CopyStatusReserved,
+				tx.Table("hold").Select("1").Where("copy_id = ? AND user_id = ? AND status = ?", loan.CopyId, loan.UserId, data.HoldStatusReady),
+			).
+			Update("status", data.CopyStatusOnLoan)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrCopyNotAvailable
+		}
+
+		err = tx.Table("hold").
+			Where("copy_id = ? AND user_id = ? AND status = ?", loan.CopyId, loan.UserId, data.HoldStatusReady).
+			Update("status", data.HoldStatusFulfilled).Error
+		if err != nil {
+			return err
+		}
+
+		row := loanRow{
+			CopyId:   loan.CopyId,
+			UserId:   loan.UserId,
+			LoanedAt: loan.LoanedAt,
+			DueDate:  loan.DueDate,
+		}
+
+		err = tx.Table("loan").Create(&row).Error
+		if err != nil {
+			return err
+		}
+
+		loanId = row.Id
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	loanResponse, err := r.FindById(loanId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loanResponse, nil
+}
+
+func (r *loanRepository) FindById(id int) (response.Loan, error) {
+	var loan response.Loan
+
+	err := r.loanQuery(r.db).Where("l.id = ?", id).First(&loan).Error
+	if err != nil {
+		return loan, err
+	}
+
+	return loan, nil
+}
+
+func (r *loanRepository) FindAllByUserId(userId int) ([]response.Loan, error) {
+	var loans []response.Loan
+
+	err := r.loanQuery(r.db).Where("l.user_id = ?", userId).Order("l.loaned_at DESC").Find(&loans).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return loans, nil
+}
+
+// Return closes the loan, posts its overdue fine to the ledger and, when
+// someone is waiting for the title, reserves the copy for the next hold in
+// line instead of putting it back on the shelf.
+func (r *loanRepository) Return(id int, loan request.ReturnLoan) (*response.Loan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var loanCopy struct {
+			Id     int
+			BookId int
+			Status string
+			UserId int
+		}
+		err := tx.Table("loan AS l").Select("c.id, c.book_id, c.status, l.user_id").
+			Joins("INNER JOIN book_copy AS c ON c.id = l.copy_id").
+			Where("l.id = ? AND l.returned_at IS NULL", id).
+			Scan(&loanCopy).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Table("loan").Where("id = ? AND returned_at IS NULL", id).Update("returned_at", loan.ReturnedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrLoanNotActive
+		}
+
+		if loan.Fine > 0 {
+			_, err = insertFineEntry(tx, request.FineEntry{
+				UserId:    loanCopy.UserId,
+				LoanId:    id,
+				Amount:    loan.Fine,
+				Note:      "denda keterlambatan",
+				EntryType: data.FineEntryCharge,
+				CreatedAt: loan.ReturnedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if loanCopy.Status != data.CopyStatusOnLoan {
+			return nil
+		}
+
+		return passCopyToNextHold(tx, loanCopy.Id, loanCopy.BookId, loan.ReturnedAt, loan.HoldExpiresAt)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	loanResponse, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loanResponse, nil
+}
+
+// Renew moves the due date while nobody is waiting for the title, the copy
+// goes to the next hold in line when it comes back instead.
+func (r *loanRepository) Renew(id int, dueDate time.Time, maxRenewals int) (*response.Loan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var waiting int64
+		err := tx.Table("loan AS l").
+			Joins("INNER JOIN book_copy AS c ON c.id = l.copy_id").
+			Joins("INNER JOIN hold AS h ON h.book_id = c.book_id").
+			Where("l.id = ? AND h.status = ?", id, data.HoldStatusWaiting).
+			Count(&waiting).Error
+		if err != nil {
+			return err
+		}
+
+		if waiting > 0 {
+			return ErrLoanOnHold
+		}
+
+		result := tx.Table("loan").
+			Where("id = ? AND returned_at IS NULL AND renewal_count < ?", id, maxRenewals).
+			Updates(map[string]interface{}{
+				"due_date":      dueDate,
+				"renewal_count": gorm.Expr("renewal_count + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrLoanNotActive
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+)
+
+var ErrPublisherInUse = apperror.Conflict("publisher.in_use")
+
+type PublisherRepository interface {
+	Save(publisher request.CreatePublisher) (response.Publisher, error)
+	FindAll(query request.PublisherQuery) ([]response.Publisher, int64, error)
+	FindById(id int) (response.Publisher, error)
+	FindByName(name string) (response.Publisher, error)
+	DeleteById(id int) (*response.Publisher, error)
+	UpdateById(id int, publisher request.UpdatePublisher) (*response.Publisher, error)
+}
+
+var PublisherSortFields = []string{"id", "name", "city"}
+
+var publisherSortColumns = map[string]string{
+	"id":   "id",
+	"name": "name",
+	"city": "city",
+}
+
+type publisherRepository struct {
+	db *gorm.DB
+}
+
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &publisherRepository{db: db}
+}
+
+func (r *publisherRepository) Save(publisher request.CreatePublisher) (response.Publisher, error) {
+	row := response.Publisher{
+		Name: publisher.Name,
+		City: publisher.City,
+	}
+
+	err := r.db.Table("publisher").Create(&row).Error
+	if err != nil {
+		return row, err
+	}
+
+	return row, nil
+}
+
+func (r *publisherRepository) FindAll(query request.PublisherQuery) ([]response.Publisher, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		if query.Name != "" {
+			db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(query.Name)+"%")
+		}
+
+		if query.City != "" {
+			db = db.Where("LOWER(city) LIKE ?", "%"+strings.ToLower(query.City)+"%")
+		}
+
+		return db
+	}
+
+	var totalItems int64
+	err := r.db.Table("publisher").Scopes(filter).Count(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var publishers []response.Publisher
+	err = r.db.Table("publisher").
+		Scopes(filter).
+		Order(publisherSortColumns[query.Sort] + " " + query.Order).
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&publishers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return publishers, totalItems, nil
+}
+
+func (r *publisherRepository) FindById(id int) (response.Publisher, error) {
+	var publisher response.Publisher
+
+	err := r.db.Table("publisher").Where("id = ?", id).First(&publisher).Error
+	if err != nil {
+		return publisher, err
+	}
+
+	return publisher, nil
+}
+
+func (r *publisherRepository) FindByName(name string) (response.Publisher, error) {
+	var publisher response.Publisher
+
+	err := r.db.Table("publisher").Where("LOWER(name) = ?", strings.ToLower(name)).First(&publisher).Error
+	if err != nil {
+		return publisher, err
+	}
+
+	return publisher, nil
+}
+
+// DeleteById refuses to delete a publisher that is still referenced by a
+// book, the caller has to move those books first.
+func (r *publisherRepository) DeleteById(id int) (*response.Publisher, error) {
+	var publisher response.Publisher
+
+	err := r.db.Table("publisher").Where("id = ?", id).First(&publisher).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var books int64
+	err = r.db.Table("book").Where("publisher_id = ?", id).Count(&books).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if books > 0 {
+		return nil, ErrPublisherInUse
+	}
+
+	err = r.db.Table("publisher").Where("id = ?", id).Delete(&response.Publisher{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &publisher, nil
+}
+
+func (r *publisherRepository) UpdateById(id int, publisher request.UpdatePublisher) (*response.Publisher, error) {
+	err := r.db.Table("publisher").Where("id = ?", id).Updates(&publisher).Error
+	if err != nil {
+		return nil, err
+	}
+
+	publisherResponse, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publisherResponse, nil
+}
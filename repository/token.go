@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRefreshTokenReused = apperror.Unauthorized("user.refresh_token_reused")
+
+type TokenRepository interface {
+	Save(token request.RefreshToken) error
+	FindByHash(tokenHash string) (response.RefreshToken, error)
+	Rotate(id int, next request.RefreshToken, now time.Time) error
+	RevokeFamily(familyId string, now time.Time) error
+	RevokeAccess(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+}
+
+type refreshTokenRow struct {
+	Id              int
+	UserId          int
+	FamilyId        string
+	TokenHash       string
+	AccessJti       string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+}
+
+type revokedTokenRow struct {
+	Jti       string `gorm:"primaryKey"`
+	ExpiresAt time.Time
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) Save(token request.RefreshToken) error {
+	_, err := insertRefreshToken(r.db, token)
+
+	return err
+}
+
+func (r *tokenRepository) FindByHash(tokenHash string) (response.RefreshToken, error) {
+	var token response.RefreshToken
+
+	err := r.db.Table("refresh_token").Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return token, err
+	}
+
+	return token, nil
+}
+
+// Rotate revokes the presented refresh token and stores its successor. The
+// update only succeeds once, so a token replayed concurrently is reported as
+// reused instead of minting a second session.
+func (r *tokenRepository) Rotate(id int, next request.RefreshToken, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		nextId, err := insertRefreshToken(tx, next)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Table("refresh_token").
+			Where("id = ? AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"revoked_at":  now,
+				"replaced_by": nextId,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		return nil
+	})
+}
+
+// RevokeFamily ends a whole session: every refresh token of the family is
+// revoked and the access tokens issued with them are put on the denylist.
+func (r *tokenRepository) RevokeFamily(familyId string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var revoked []revokedTokenRow
+		err := tx.Table("refresh_token").
+			Select("access_jti AS jti, access_expires_at AS expires_at").
+			Where("family_id = ? AND access_expires_at > ?", familyId, now).
+			Scan(&revoked).Error
+		if err != nil {
+			return err
+		}
+
+		if len(revoked) > 0 {
+			err = tx.Table("revoked_token").Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Table("refresh_token").
+			Where("family_id = ? AND revoked_at IS NULL", familyId).
+			Update("revoked_at", now).Error
+	})
+}
+
+func (r *tokenRepository) RevokeAccess(jti string, expiresAt time.Time) error {
+	err := r.db.Table("revoked_token").Where("expires_at < ?", time.Now()).Delete(nil).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.Table("revoked_token").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&revokedTokenRow{Jti: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *tokenRepository) IsRevoked(jti string) (bool, error) {
+	var count int64
+
+	err := r.db.Table("revoked_token").Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func insertRefreshToken(tx *gorm.DB, token request.RefreshToken) (int, error) {
+	row := refreshTokenRow{
+		UserId:          token.UserId,
+		FamilyId:        token.FamilyId,
+		TokenHash:       token.TokenHash,
+		AccessJti:       token.AccessJti,
+		AccessExpiresAt: token.AccessExpiresAt,
+		ExpiresAt:       token.ExpiresAt,
+		CreatedAt:       token.CreatedAt,
+	}
+
+	err := tx.Table("refresh_token").Create(&row).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return row.Id, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories are the repositories a change is written through, all bound to
+// the same database session.
+type Repositories struct {
+	Author AuthorRepository
+	Book   BookRepository
+	User   UserRepository
+	Audit  AuditRepository
+}
+
+// Transactor runs fn with repositories bound to one transaction. Everything
+// written through them is rolled back when fn returns an error, so a change
+// and its audit entry are stored together or not at all.
+type Transactor interface {
+	Transaction(fn func(repos Repositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(fn func(repos Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Author: NewAuthorRepository(tx),
+			Book:   NewBookRepository(tx),
+			User:   NewUserRepository(tx),
+			Audit:  NewAuditRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrashRepository interface {
+	FindAll(query request.TrashQuery) ([]response.TrashItem, int64, error)
+	Purge(before time.Time) (*response.PurgedTrash, error)
+}
+
+var trashQueries = map[string]string{
+	"book":   "SELECT 'book' AS entity, id, title AS name, deleted_at FROM book WHERE deleted_at IS NOT NULL",
+	"author": "SELECT 'author' AS entity, id, name, deleted_at FROM author WHERE deleted_at IS NOT NULL",
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// FindAll lists the books and authors in the trash, the most recently
+// deleted first.
+func (r *trashRepository) FindAll(query request.TrashQuery) ([]response.TrashItem, int64, error) {
+	var parts []string
+	for _, entity := range []string{"book", "author"} {
+		if query.Entity == "" || query.Entity == entity {
+			parts = append(parts, trashQueries[entity])
+		}
+	}
+
+	trash := "(" + strings.Join(parts, " UNION ALL ") + ") AS trash"
+
+	var totalItems int64
+	err := r.db.Raw("SELECT COUNT(*) FROM " + trash).Scan(&totalItems).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var items []response.TrashItem
+	err = r.db.Raw("SELECT entity, id, name, deleted_at FROM "+trash+" ORDER BY deleted_at DESC, entity, id LIMIT ? OFFSET ?",
+		query.Limit, (query.Page-1)*query.Limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, totalItems, nil
+}
+
+// purgedBookChildren are the tables with rows of a book, in the order they
+// are removed when the book is purged.
+var purgedBookChildren = []string{"hold", "book_copy", "book_contributor", "book_category"}
+
+// Purge removes the books and authors deleted before the given time for
+// good. Books whose copies have loans stay in the trash to keep the loan
+// history, and so do authors that a book in the trash still refers to.
+func (r *trashRepository) Purge(before time.Time) (*response.PurgedTrash, error) {
+	purged := response.PurgedTrash{Before: before}
+
+	// deleted_at is written in UTC, SQLite compares it as text
+	before = before.UTC()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var bookIds []int
+
+		err := tx.Raw(`SELECT id FROM book WHERE deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM book_copy AS c INNER JOIN loan AS l ON l.copy_id = c.id WHERE c.book_id = book.id)`, before).
+			Scan(&bookIds).Error
+		if err != nil {
+			return err
+		}
+
+		if len(bookIds) > 0 {
+			// the rows of the books are removed here instead of being left to
+			// ON DELETE CASCADE, which only runs where foreign keys are enforced
+			for _, table := range purgedBookChildren {
+				err = tx.Exec("DELETE FROM ? WHERE book_id IN ?", clause.Table{Name: table}, bookIds).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			result := tx.Exec("DELETE FROM book WHERE id IN ?", bookIds)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			purged.Books = result.RowsAffected
+		}
+
+		result := tx.Exec(`DELETE FROM author WHERE deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM book WHERE book.author_id = author.id)
+			AND NOT EXISTS (SELECT 1 FROM book_contributor AS bc WHERE bc.author_id = author.id)`, before)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		purged.Authors = result.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &purged, nil
+}
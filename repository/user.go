@@ -2,13 +2,24 @@ package repository
 
 import (
 	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// userTable is quoted by the dialect when used as a query argument, "user"
+// is a reserved word in PostgreSQL.
+var userTable = clause.Table{Name: "user"}
+
 type UserRepository interface {
 	Save(user request.User) error
 	CheckUsername(username string) (bool, error)
 	GetUserByUsername(username string) (request.User, error)
+	FindByUsername(username string) (response.User, error)
+	FindById(id int) (response.User, error)
+	UpdateCategory(id int, category string) error
+	UpdateRole(id int, role string) error
+	CountByRole(role string) (int64, error)
 }
 
 type userRepository struct {
@@ -20,7 +31,8 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Save(user request.User) error {
-	err := r.db.Table("user").Create(&user).Error
+	err := r.db.Exec("INSERT INTO ? (username, password, role) VALUES (?, ?, ?)",
+		userTable, user.Username, user.Password, user.Role).Error
 	if err != nil {
 		return err
 	}
@@ -47,3 +59,51 @@ func (r *userRepository) GetUserByUsername(username string) (request.User, error
 
 	return user, nil
 }
+
+func (r *userRepository) FindByUsername(username string) (response.User, error) {
+	var user response.User
+	err := r.db.Table("user").Where("username = ?", username).First(&user).Error
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindById(id int) (response.User, error) {
+	var user response.User
+	err := r.db.Table("user").Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) UpdateCategory(id int, category string) error {
+	err := r.db.Table("user").Where("id = ?", id).Update("category", category).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateRole(id int, role string) error {
+	err := r.db.Table("user").Where("id = ?", id).Update("role", role).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) CountByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Table("user").Where("role = ?", role).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/ilhaamms/library-api/apperror"
+	"gorm.io/gorm"
+)
+
+// ErrVersionMismatch is an update or delete sent for a version of a book or
+// author that is no longer the current one.
+var ErrVersionMismatch = apperror.PreconditionFailed("request.version_mismatch")
+
+// nextVersion is the version column after a change, every update bumps it.
+var nextVersion = gorm.Expr("version + 1")
+
+// whereVersion narrows a query to the row with id, unless it is in the
+// trash, and unless version is 0 to that version of it, so an update is a
+// compare-and-swap.
+func whereVersion(db *gorm.DB, id, version int) *gorm.DB {
+	db = db.Where("id = ? AND deleted_at IS NULL", id)
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+
+	return db
+}
+
+// swapped tells why a compare-and-swap changed no row: the row is gone or
+// another request changed it first.
+func swapped(db *gorm.DB, table string, id int, result *gorm.DB) error {
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var count int64
+	err := db.Table(table).Where("id = ? AND deleted_at IS NULL", id).Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return ErrVersionMismatch
+}
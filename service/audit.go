@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/validation"
+)
+
+// ErrAuditEmpty is not a failure, controllers answer it with an empty list.
+var ErrAuditEmpty = errors.New("audit kosong")
+
+type AuditService interface {
+	FindAll(query request.AuditQuery) (*[]response.AuditEntry, *response.Pagination, error)
+}
+
+type AuditServices struct {
+	AuditRepository repository.AuditRepository
+}
+
+func NewAuditService(auditRepository repository.AuditRepository) AuditService {
+	return &AuditServices{AuditRepository: auditRepository}
+}
+
+func (s *AuditServices) FindAll(query request.AuditQuery) (*[]response.AuditEntry, *response.Pagination, error) {
+	err := validation.Struct(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query.Page, query.Limit, err = normalizePage(query.Page, query.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, totalItems, err := s.AuditRepository.FindAll(query)
+	if err != nil {
+		return nil, nil, apperror.Internal("audit.fetch_failed", err)
+	}
+
+	if totalItems == 0 {
+		return nil, nil, ErrAuditEmpty
+	}
+
+	pagination, err := newPagination(query.Page, query.Limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &entries, pagination, nil
+}
+
+// inTransaction runs fn with repositories bound to one transaction, so the
+// change fn makes and the audit entry it records are committed together.
+// Without a transactor, as in unit tests, fn runs on repos as they are.
+func inTransaction(transactor repository.Transactor, repos repository.Repositories, fn func(repos repository.Repositories) error) error {
+	if transactor == nil {
+		return fn(repos)
+	}
+
+	return transactor.Transaction(fn)
+}
+
+// audit records a change actor made to the entity with id. before and after
+// are the entity as the API shows it, nil before a create and after a
+// delete, and only the fields that differ are kept. It is called inside the
+// transaction of the change, a failure to record is returned so the change
+// is rolled back with it. A nil repository, as in unit tests, records
+// nothing.
+func audit(repo repository.AuditRepository, actor data.Actor, action, entity string, id int, before, after interface{}) error {
+	if repo == nil {
+		return nil
+	}
+
+	changes, err := auditChanges(before, after)
+	if err == nil {
+		err = repo.Save(response.AuditEntry{
+			CreatedAt: time.Now().UTC(),
+			Actor:     actor.Username,
+			ActorRole: actor.Role,
+			Entity:    entity,
+			EntityId:  id,
+			Action:    action,
+			Changes:   changes,
+			RequestId: actor.RequestId,
+			ClientIp:  actor.ClientIp,
+		})
+	}
+
+	if err != nil {
+		return apperror.Internal("audit.record_failed", err)
+	}
+
+	return nil
+}
+
+// auditChanges compares the JSON fields of before and after.
+func auditChanges(before, after interface{}) (map[string]response.AuditChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]response.AuditChange{}
+
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			changes[field] = response.AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = response.AuditChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(value interface{}) (map[string]interface{}, error) {
+	document, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+
+	err = json.Unmarshal(document, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
@@ -1,47 +1,69 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
-	"math"
 	"time"
 
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/mergepatch"
 	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/validation"
+	"gorm.io/gorm"
 )
 
+var ErrAuthorEmpty = errors.New("data author kosong")
+
 type AuthorService interface {
-	Save(author request.CreateAuthor) (*response.CreateAuthor, error)
-	FindAll(page, limit int) (*[]response.Author, int, error)
+	Save(actor data.Actor, author request.CreateAuthor) (*response.CreateAuthor, error)
+	FindAll(query request.AuthorQuery) (*[]response.Author, *response.Pagination, error)
 	FindById(id int) (*response.Author, error)
-	DeleteById(id int) (*response.Author, error)
-	UpdateById(id int, author request.UpdateAuthor) (*response.UpdateAuthor, error)
+	DeleteById(actor data.Actor, id, version int, options request.DeleteAuthor) (*response.AuthorDeletion, error)
+	UpdateById(actor data.Actor, id, version int, author request.UpdateAuthor) (*response.UpdateAuthor, error)
+	PatchById(actor data.Actor, id, version int, patch []byte) (*response.Author, error)
+	Restore(actor data.Actor, id int) (*response.Author, error)
 }
 
 type AuthorServices struct {
-	AuthorRepo repository.AuthorRepository
+	AuthorRepo      repository.AuthorRepository
+	AuditRepository repository.AuditRepository
+	Transactor      repository.Transactor
 }
 
-func NewAuthorService(authorRepo repository.AuthorRepository) AuthorService {
-	return &AuthorServices{AuthorRepo: authorRepo}
+func NewAuthorService(authorRepo repository.AuthorRepository, auditRepository repository.AuditRepository, transactor repository.Transactor) AuthorService {
+	return &AuthorServices{AuthorRepo: authorRepo, AuditRepository: auditRepository, Transactor: transactor}
 }
 
-func (s *AuthorServices) Save(author request.CreateAuthor) (*response.CreateAuthor, error) {
+// transaction runs a change to authors and its audit entries in one
+// transaction.
+func (s *AuthorServices) transaction(fn func(repos repository.Repositories) error) error {
+	return inTransaction(s.Transactor, repository.Repositories{Author: s.AuthorRepo, Audit: s.AuditRepository}, fn)
+}
 
-	if author.Name == "" || author.Birthdate == "" {
-		return nil, errors.New("nama dan tanggal lahir tidak boleh kosong")
-	}
+func (s *AuthorServices) Save(actor data.Actor, author request.CreateAuthor) (*response.CreateAuthor, error) {
 
-	if len(author.Name) < 3 {
-		return nil, errors.New("nama minimal 3 karakter")
+	err := validation.Struct(author)
+	if err != nil {
+		return nil, err
 	}
 
 	birthdate, err := time.Parse("2006-01-02", author.Birthdate)
 	if err != nil {
-		return nil, errors.New("format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid")
+		return nil, apperror.Invalid("birth_date", "author.birth_date_format")
 	}
 
-	err = s.AuthorRepo.Save(author)
+	err = s.transaction(func(repos repository.Repositories) error {
+		saved, err := repos.Author.Save(author)
+		if err != nil {
+			return err
+		}
+
+		return audit(repos.Audit, actor, data.AuditCreate, data.AuditAuthor, saved.ID, nil, saved)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -52,38 +74,57 @@ func (s *AuthorServices) Save(author request.CreateAuthor) (*response.CreateAuth
 	}, nil
 }
 
-func (s *AuthorServices) FindAll(page, limit int) (*[]response.Author, int, error) {
-	authors, err := s.AuthorRepo.FindAll()
+func (s *AuthorServices) FindAll(query request.AuthorQuery) (*[]response.Author, *response.Pagination, error) {
+	page, limit, err := normalizePage(query.Page, query.Limit)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	if len(authors) == 0 {
-		return nil, 0, errors.New("data author kosong")
+	sort, order, err := normalizeSort(query.Sort, query.Order, repository.AuthorSortFields)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	startIndex := (page - 1) * limit
-	endIndex := int(math.Min(float64(startIndex+limit), float64(len(authors))))
-	totalPages := int(math.Ceil(float64(len(authors)) / float64(limit)))
+	if query.BirthDateFrom != "" {
+		if _, err := time.Parse("2006-01-02", query.BirthDateFrom); err != nil {
+			return nil, nil, apperror.Invalid("birth_date_from", "author.birth_date_from_format")
+		}
+	}
 
-	if page > totalPages {
-		return nil, 0, errors.New("page sudah melebihi total page")
+	if query.BirthDateTo != "" {
+		if _, err := time.Parse("2006-01-02", query.BirthDateTo); err != nil {
+			return nil, nil, apperror.Invalid("birth_date_to", "author.birth_date_to_format")
+		}
 	}
 
-	authors = authors[startIndex:endIndex]
+	query.Page, query.Limit, query.Sort, query.Order = page, limit, sort, order
+
+	authors, totalItems, err := s.AuthorRepo.FindAll(query)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return &authors, totalPages, nil
+	if totalItems == 0 {
+		return nil, nil, ErrAuthorEmpty
+	}
+
+	pagination, err := newPagination(page, limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &authors, pagination, nil
 }
 
 func (s *AuthorServices) FindById(id int) (*response.Author, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak valid")
+		return nil, apperror.Invalid("id", "request.id_not_valid")
 	}
 
 	author, err := s.AuthorRepo.FindById(id)
 	if err != nil {
-		return nil, err
+		return nil, apperror.NotFound("author.not_found")
 	}
 
 	// if author == (response.Author{}) {
@@ -93,50 +134,228 @@ func (s *AuthorServices) FindById(id int) (*response.Author, error) {
 	return &author, nil
 }
 
-func (s *AuthorServices) DeleteById(id int) (*response.Author, error) {
+// DeleteById deletes the author. A version other than 0 is the version the
+// client last read, the delete fails when the author changed since. The
+// strategy, restrict unless set, decides what happens to the author's books.
+func (s *AuthorServices) DeleteById(actor data.Actor, id, version int, options request.DeleteAuthor) (*response.AuthorDeletion, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak valid")
+		return nil, apperror.Invalid("id", "request.id_not_valid")
 	}
 
-	author, err := s.AuthorRepo.DeleteById(id)
+	err := validation.Struct(options)
 	if err != nil {
-		return nil, errors.New("gagal menghapus data author, author tidak ditemukan")
+		return nil, err
 	}
 
-	return author, nil
+	if options.Strategy == "" {
+		options.Strategy = data.AuthorDeleteRestrict
+	}
+
+	if options.Strategy == data.AuthorDeleteReassign {
+		if options.To == 0 {
+			return nil, apperror.Invalid("to", "author.reassign_to_required")
+		}
+
+		if options.To == id {
+			return nil, apperror.Invalid("to", "author.reassign_self")
+		}
+	} else {
+		options.To = 0
+	}
+
+	var deletion *response.AuthorDeletion
+
+	err = s.transaction(func(repos repository.Repositories) error {
+		var err error
+
+		deletion, err = repos.Author.DeleteById(id, version, options)
+		if errors.Is(err, repository.ErrVersionMismatch) || errors.Is(err, repository.ErrAuthorHasBooks) ||
+			errors.Is(err, repository.ErrReassignNotFound) || errors.Is(err, repository.ErrBookCirculating) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("author.delete_not_found")
+		}
+
+		if err != nil {
+			return apperror.Internal("author.delete_failed", err)
+		}
+
+		err = audit(repos.Audit, actor, data.AuditDelete, data.AuditAuthor, id, deletion.Author, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, book := range deletion.DeletedBooks {
+			err = audit(repos.Audit, actor, data.AuditDelete, data.AuditBook, book.Id, book, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a reassigned book is credited to the other author, whatever the role
+		for _, book := range deletion.ReassignedBooks {
+			err = audit(repos.Audit, actor, data.AuditUpdate, data.AuditBook, book.Id,
+				map[string]int{"author_id": id}, map[string]int{"author_id": options.To})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deletion, nil
 }
 
-func (s *AuthorServices) UpdateById(id int, author request.UpdateAuthor) (*response.UpdateAuthor, error) {
+// UpdateById changes the fields of the author that are sent. A version other
+// than 0 is the version the client last read, the update fails when the
+// author changed since.
+func (s *AuthorServices) UpdateById(actor data.Actor, id, version int, author request.UpdateAuthor) (*response.UpdateAuthor, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak valid")
+		return nil, apperror.Invalid("id", "request.id_not_valid")
 	}
 
 	if author.Name == "" && author.Birthdate == "" {
-		return nil, errors.New("field name dan birthdate tidak boleh kosong")
+		return nil, apperror.Validation("author.update_empty")
 	}
 
-	if author.Name != "" && len(author.Name) < 3 {
-		return nil, errors.New("harap masukan nama minimal 3 karakter")
+	err := validation.Struct(author)
+	if err != nil {
+		return nil, err
 	}
 
-	if author.Birthdate != "" {
-		birthdate, err := time.Parse("2006-01-02", author.Birthdate)
+	var authorResponse *response.Author
+
+	err = s.transaction(func(repos repository.Repositories) error {
+		before, err := repos.Author.FindById(id)
 		if err != nil {
-			return nil, errors.New("format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid")
+			return apperror.NotFound("author.update_not_found")
 		}
 
-		author.Birthdate = birthdate.Format("2006-01-02")
-	}
+		authorResponse, err = repos.Author.UpdateById(id, version, author)
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return err
+		}
 
-	authorResponse, err := s.AuthorRepo.UpdateById(id, author)
+		if err != nil {
+			return apperror.NotFound("author.update_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditUpdate, data.AuditAuthor, id, before, authorResponse)
+	})
 	if err != nil {
-		return nil, errors.New("gagal mengupdate data author : " + err.Error())
+		return nil, err
 	}
 
 	return &response.UpdateAuthor{
 		Name:      authorResponse.Name,
 		BirthDate: authorResponse.BirthDate,
+		Version:   authorResponse.Version,
 	}, nil
 }
+
+// PatchById applies a JSON merge patch (RFC 7396) to the author. The merged
+// author is checked with the same rules as a new one, so null on a field
+// that is required is rejected instead of being ignored. Like a book patch,
+// it only goes through while the author is at the version it was applied to.
+func (s *AuthorServices) PatchById(actor data.Actor, id, version int, patch []byte) (*response.Author, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_not_valid")
+	}
+
+	_, err := mergepatch.Keys(patch)
+	if err != nil {
+		return nil, apperror.BadRequest("request.patch_invalid")
+	}
+
+	var authorResponse *response.Author
+
+	err = s.transaction(func(repos repository.Repositories) error {
+		current, err := repos.Author.FindById(id)
+		if err != nil {
+			return apperror.NotFound("author.not_found")
+		}
+
+		if version != 0 && version != current.Version {
+			return repository.ErrVersionMismatch
+		}
+
+		document, err := json.Marshal(request.CreateAuthor{
+			Name:      current.Name,
+			Birthdate: current.BirthDate.Format("2006-01-02"),
+		})
+		if err != nil {
+			return apperror.Internal("error.internal", err)
+		}
+
+		merged, err := mergepatch.Apply(document, patch)
+		if err != nil {
+			return apperror.BadRequest("request.patch_invalid")
+		}
+
+		var author request.CreateAuthor
+
+		decoder := json.NewDecoder(bytes.NewReader(merged))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(&author)
+		if err != nil {
+			return apperror.Malformed(err)
+		}
+
+		err = validation.Struct(author)
+		if err != nil {
+			return err
+		}
+
+		authorResponse, err = repos.Author.UpdateById(id, current.Version, request.UpdateAuthor(author))
+		if errors.Is(err, repository.ErrVersionMismatch) {
+			return err
+		}
+
+		if err != nil {
+			return apperror.NotFound("author.update_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditUpdate, data.AuditAuthor, id, current, authorResponse)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return authorResponse, nil
+}
+
+// Restore takes a deleted author out of the trash.
+func (s *AuthorServices) Restore(actor data.Actor, id int) (*response.Author, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_not_valid")
+	}
+
+	var author *response.Author
+
+	err := s.transaction(func(repos repository.Repositories) error {
+		var err error
+
+		author, err = repos.Author.Restore(id)
+		if err != nil {
+			return apperror.NotFound("author.trash_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditRestore, data.AuditAuthor, id, nil, author)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
@@ -1,58 +1,85 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
-	"math"
+	"strings"
 
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/isbn"
+	"github.com/ilhaamms/library-api/mergepatch"
 	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/validation"
 )
 
+// ErrBookEmpty is not a failure, controllers answer it with an empty list.
+var ErrBookEmpty = errors.New("data book kosong")
+
 type BookService interface {
-	Save(book request.CreateBook) (*response.CreateBook, error)
-	FindAll(page, limit int) (*[]response.ResultBook, int, error)
+	Save(actor data.Actor, book request.CreateBook) (*response.CreateBook, error)
+	FindAll(query request.BookQuery) (*[]response.ResultBook, *response.Pagination, error)
 	FindById(id int) (*response.ResultBook, error)
-	DeleteById(id int) (*response.ResultBook, error)
-	Update(id int, book request.UpdateBook) (*response.ResultBook, error)
+	DeleteById(actor data.Actor, id, version int) (*response.ResultBook, error)
+	Update(actor data.Actor, id, version int, book request.UpdateBook) (*response.ResultBook, error)
+	Patch(actor data.Actor, id, version int, patch []byte) (*response.ResultBook, error)
+	Search(query request.SearchBook) (*[]response.ResultSearchBook, *response.Pagination, error)
+	Restore(actor data.Actor, id int) (*response.ResultBook, error)
 }
 
 type BookServices struct {
-	BookRepository repository.BookRepository
+	BookRepository  repository.BookRepository
+	AuditRepository repository.AuditRepository
+	Transactor      repository.Transactor
 }
 
-func NewBookService(bookRepository repository.BookRepository) BookService {
-	return &BookServices{BookRepository: bookRepository}
+func NewBookService(bookRepository repository.BookRepository, auditRepository repository.AuditRepository, transactor repository.Transactor) BookService {
+	return &BookServices{BookRepository: bookRepository, AuditRepository: auditRepository, Transactor: transactor}
 }
 
-func (s *BookServices) Save(book request.CreateBook) (*response.CreateBook, error) {
+// transaction runs a change to books and its audit entry in one transaction.
+func (s *BookServices) transaction(fn func(repos repository.Repositories) error) error {
+	return inTransaction(s.Transactor, repository.Repositories{Book: s.BookRepository, Audit: s.AuditRepository}, fn)
+}
 
-	if book.Title == "" || book.Isbn == "" || book.AuthorId == 0 {
-		return nil, errors.New("judul, isbn, dan author_id tidak boleh kosong")
-	}
+func (s *BookServices) Save(actor data.Actor, book request.CreateBook) (*response.CreateBook, error) {
 
-	if len(book.Title) < 3 {
-		return nil, errors.New("judul minimal 3 karakter")
+	err := validation.Struct(book)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(book.Isbn) < 10 {
-		return nil, errors.New("isbn minimal 10 karakter")
+	book.Isbn, err = isbn.Normalize(book.Isbn)
+	if err != nil {
+		return nil, apperror.Invalid("isbn", isbn.MessageKey(err))
 	}
 
-	if len(book.Isbn) > 13 {
-		return nil, errors.New("isbn maksimal 13 karakter")
+	contributors, err := bookContributors(book.AuthorId, book.Contributors)
+	if err != nil {
+		return nil, err
 	}
 
-	if book.AuthorId < 0 {
-		return nil, errors.New("author_id tidak boleh negatif")
-	}
+	book.Contributors, book.AuthorId = contributors, contributors[0].AuthorId
 
-	_, err := s.BookRepository.FindBookByIsbn(book.Isbn)
+	normalizePublication(&book.BookPublication)
+
+	existing, err := s.BookRepository.FindBookByIsbn(book.Isbn)
 	if err == nil {
-		return nil, errors.New("isbn sudah digunakan oleh buku lain")
+		return nil, isbnTaken(existing)
 	}
 
-	err = s.BookRepository.Save(book)
+	err = s.transaction(func(repos repository.Repositories) error {
+		saved, err := repos.Book.Save(book)
+		if err != nil {
+			return err
+		}
+
+		return audit(repos.Audit, actor, data.AuditCreate, data.AuditBook, saved.Id, nil, saved.Result())
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -64,130 +91,403 @@ func (s *BookServices) Save(book request.CreateBook) (*response.CreateBook, erro
 
 }
 
-func (s *BookServices) FindAll(page, limit int) (*[]response.ResultBook, int, error) {
-	books, err := s.BookRepository.FindAll()
+func (s *BookServices) FindAll(query request.BookQuery) (*[]response.ResultBook, *response.Pagination, error) {
+	page, limit, err := normalizePage(query.Page, query.Limit)
 	if err != nil {
-		return nil, 0, errors.New("gagal mengambil data book : " + err.Error())
+		return nil, nil, err
 	}
 
-	var listBook []response.ResultBook
-	for _, book := range books {
-		dataBook := response.ResultBook{
-			Id:    book.Id,
-			Title: book.Title,
-			Isbn:  book.Isbn,
-			AuthorBook: response.AuthorBook{
-				ID:        book.AuthorId,
-				Name:      book.AuthorName,
-				BirthDate: book.BirthDate,
-			},
-		}
+	sort, order, err := normalizeSort(query.Sort, query.Order, repository.BookSortFields)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		listBook = append(listBook, dataBook)
+	if query.AuthorId < 0 {
+		return nil, nil, apperror.Invalid("author_id", "book.author_id_negative")
 	}
 
-	if len(listBook) == 0 {
-		return nil, 0, errors.New("data book kosong")
+	if query.PublisherId < 0 || query.WorkId < 0 {
+		return nil, nil, apperror.Validation("book.publisher_work_negative")
 	}
 
-	startIndex := (page - 1) * limit
-	endIndex := int(math.Min(float64(startIndex+limit), float64(len(listBook))))
-	totalPages := int(math.Ceil(float64(len(listBook)) / float64(limit)))
+	if query.CategoryId < 0 {
+		return nil, nil, apperror.Invalid("category", "book.category_negative")
+	}
 
-	if page > totalPages {
-		return nil, 0, errors.New("page sudah melebihi total page")
+	if query.Isbn != "" {
+		query.Isbn, err = isbn.Normalize(query.Isbn)
+		if err != nil {
+			return nil, nil, apperror.Invalid("isbn", isbn.MessageKey(err))
+		}
 	}
 
-	listBook = listBook[startIndex:endIndex]
+	query.Language = strings.ToLower(query.Language)
+	query.Format = strings.ToLower(query.Format)
 
-	return &listBook, totalPages, nil
+	query.Page, query.Limit, query.Sort, query.Order = page, limit, sort, order
+
+	books, totalItems, err := s.BookRepository.FindAll(query)
+	if err != nil {
+		return nil, nil, apperror.Internal("book.fetch_failed", err)
+	}
+
+	if totalItems == 0 {
+		return nil, nil, ErrBookEmpty
+	}
+
+	pagination, err := newPagination(page, limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var listBook []response.ResultBook
+	for _, book := range books {
+		listBook = append(listBook, book.Result())
+	}
+
+	return &listBook, pagination, nil
 }
 
 func (s *BookServices) FindById(id int) (*response.ResultBook, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak boleh negatif atau 0")
+		return nil, apperror.Invalid("id", "request.id_invalid")
 	}
 
 	book, err := s.BookRepository.FindById(id)
 	if err != nil {
-		return nil, errors.New("book tidak ditemukan")
+		return nil, apperror.NotFound("book.not_found")
 	}
 
-	dataBook := response.ResultBook{
-		Id:    book.Id,
-		Title: book.Title,
-		Isbn:  book.Isbn,
-		AuthorBook: response.AuthorBook{
-			ID:        book.AuthorId,
-			Name:      book.AuthorName,
-			BirthDate: book.BirthDate,
-		},
-	}
+	dataBook := book.Result()
 
 	return &dataBook, nil
 }
 
-func (s *BookServices) DeleteById(id int) (*response.ResultBook, error) {
+// DeleteById deletes the book. A version other than 0 is the version the
+// client last read, the delete fails when the book changed since.
+func (s *BookServices) DeleteById(actor data.Actor, id, version int) (*response.ResultBook, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak boleh negatif atau 0")
+		return nil, apperror.Invalid("id", "request.id_invalid")
 	}
 
-	book, err := s.BookRepository.Delete(id)
+	var book *response.ResultBook
+
+	err := s.transaction(func(repos repository.Repositories) error {
+		var err error
+
+		book, err = repos.Book.Delete(id, version)
+		if errors.Is(err, repository.ErrVersionMismatch) || errors.Is(err, repository.ErrBookCirculating) {
+			return err
+		}
+
+		if err != nil {
+			return apperror.NotFound("book.delete_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditDelete, data.AuditBook, id, book, nil)
+	})
 	if err != nil {
-		return nil, errors.New("gagal menghapus data book, book tidak ditemukan")
+		return nil, err
 	}
 
 	return book, nil
 }
 
-func (s *BookServices) Update(id int, book request.UpdateBook) (*response.ResultBook, error) {
+// Update replaces the book. A version other than 0 is the version the client
+// last read, the update fails when the book changed since.
+func (s *BookServices) Update(actor data.Actor, id, version int, book request.UpdateBook) (*response.ResultBook, error) {
 
 	if id <= 0 {
-		return nil, errors.New("id tidak boleh negatif atau 0")
+		return nil, apperror.Invalid("id", "request.id_invalid")
 	}
 
-	if book.Title == "" || book.Isbn == "" || book.AuthorId == 0 {
-		return nil, errors.New("judul, isbn, dan author_id tidak boleh kosong")
+	err := validation.Struct(book)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(book.Title) < 3 {
-		return nil, errors.New("judul minimal 3 karakter")
+	book.Isbn, err = isbn.Normalize(book.Isbn)
+	if err != nil {
+		return nil, apperror.Invalid("isbn", isbn.MessageKey(err))
 	}
 
-	if len(book.Isbn) < 10 {
-		return nil, errors.New("isbn minimal 10 karakter")
+	contributors, err := bookContributors(book.AuthorId, book.Contributors)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(book.Isbn) > 13 {
-		return nil, errors.New("isbn maksimal 13 karakter")
+	book.Contributors, book.AuthorId = contributors, contributors[0].AuthorId
+
+	normalizePublication(&book.BookPublication)
+
+	if book.EditionOf == id {
+		return nil, apperror.Invalid("edition_of", "book.edition_of_self")
 	}
 
-	if book.AuthorId < 0 {
-		return nil, errors.New("author_id tidak boleh negatif")
+	existing, err := s.BookRepository.FindBookByIsbn(book.Isbn)
+	if err == nil && existing.Id != id {
+		return nil, isbnTaken(existing)
 	}
 
-	_, err := s.BookRepository.FindBookByIsbn(book.Isbn)
-	if err == nil {
-		return nil, errors.New("isbn sudah digunakan oleh buku lain")
+	var bookUpdate *response.ResultBook
+
+	err = s.transaction(func(repos repository.Repositories) error {
+		before, err := repos.Book.FindById(id)
+		if err != nil {
+			return apperror.NotFound("book.update_not_found")
+		}
+
+		bookUpdate, err = repos.Book.Update(id, version, book)
+		if errors.Is(err, repository.ErrEditionNotFound) || errors.Is(err, repository.ErrCategoryNotFound) ||
+			errors.Is(err, repository.ErrAuthorNotFound) || errors.Is(err, repository.ErrVersionMismatch) {
+			return err
+		}
+
+		if err != nil {
+			return apperror.NotFound("book.update_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditUpdate, data.AuditBook, id, before.Result(), bookUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bookUpdate, nil
+}
+
+// isbnTaken tells a client whether the book holding the ISBN can be found in
+// the catalogue or has to be restored from the trash first.
+func isbnTaken(existing response.Book) error {
+	if existing.DeletedAt != nil {
+		return apperror.Conflict("book.isbn_in_trash").With(i18n.Params{"id": existing.Id})
+	}
+
+	return apperror.Conflict("book.isbn_taken")
+}
+
+// Restore takes a deleted book out of the trash.
+func (s *BookServices) Restore(actor data.Actor, id int) (*response.ResultBook, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
 	}
 
-	bookUpdate, err := s.BookRepository.Update(id, book)
+	var book *response.ResultBook
+
+	err := s.transaction(func(repos repository.Repositories) error {
+		var err error
+
+		book, err = repos.Book.Restore(id)
+		if errors.Is(err, repository.ErrAuthorInTrash) {
+			return err
+		}
+
+		if err != nil {
+			return apperror.NotFound("book.trash_not_found")
+		}
+
+		return audit(repos.Audit, actor, data.AuditRestore, data.AuditBook, id, nil, book)
+	})
 	if err != nil {
-		return nil, errors.New("gagal mengupdate data book, book tidak ditemukan")
+		return nil, err
 	}
 
-	dataBook := response.ResultBook{
-		Id:    bookUpdate.Id,
-		Title: bookUpdate.Title,
-		Isbn:  bookUpdate.Isbn,
-		AuthorBook: response.AuthorBook{
-			ID:        bookUpdate.Id,
-			Name:      bookUpdate.Name,
-			BirthDate: bookUpdate.BirthDate,
+	return book, nil
+}
+
+// Patch applies a JSON merge patch (RFC 7396) to the book. The patch is
+// merged into the current book, so members that are not sent keep their
+// value and null clears them, and the result is checked like an Update. The
+// update only goes through while the book is still at the version the patch
+// was applied to.
+func (s *BookServices) Patch(actor data.Actor, id, version int, patch []byte) (*response.ResultBook, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	keys, err := mergepatch.Keys(patch)
+	if err != nil {
+		return nil, apperror.BadRequest("request.patch_invalid")
+	}
+
+	current, err := s.BookRepository.FindById(id)
+	if err != nil {
+		return nil, apperror.NotFound("book.not_found")
+	}
+
+	if version != 0 && version != current.Version {
+		return nil, repository.ErrVersionMismatch
+	}
+
+	document, err := json.Marshal(bookDocument(current))
+	if err != nil {
+		return nil, apperror.Internal("error.internal", err)
+	}
+
+	merged, err := mergepatch.Apply(document, patch)
+	if err != nil {
+		return nil, apperror.BadRequest("request.patch_invalid")
+	}
+
+	var book request.UpdateBook
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(&book)
+	if err != nil {
+		return nil, apperror.Malformed(err)
+	}
+
+	// author_id on its own names the single author of the book, the current
+	// contributors would take precedence over it
+	if keys["author_id"] && !keys["contributors"] {
+		book.Contributors = nil
+	}
+
+	// a removed category_ids clears the categories, nil leaves them as is
+	if book.CategoryIds == nil {
+		book.CategoryIds = []int{}
+	}
+
+	return s.Update(actor, id, current.Version, book)
+}
+
+// bookDocument is the current book in the shape of an update request, the
+// document a merge patch is applied to.
+func bookDocument(book response.Book) request.UpdateBook {
+	document := request.UpdateBook{
+		Title:    book.Title,
+		Isbn:     book.Isbn,
+		AuthorId: book.AuthorId,
+		BookPublication: request.BookPublication{
+			PublisherId:     intValue(book.PublisherId),
+			PublicationYear: intValue(book.PublicationYear),
+			Edition:         book.Edition,
+			PageCount:       intValue(book.PageCount),
+			Language:        book.Language,
+			Format:          book.Format,
+			Description:     book.Description,
 		},
 	}
 
-	return &dataBook, nil
+	for _, contributor := range book.Contributors {
+		document.Contributors = append(document.Contributors, request.BookContributor{AuthorId: contributor.AuthorId, Role: contributor.Role})
+	}
+
+	for _, category := range book.Categories {
+		document.CategoryIds = append(document.CategoryIds, category.ID)
+	}
+
+	return document
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
+
+// normalizePublication lower cases the language and format codes of a book
+// request, their values are checked by the validate tags.
+func normalizePublication(publication *request.BookPublication) {
+	publication.Language = strings.ToLower(strings.TrimSpace(publication.Language))
+	publication.Format = strings.ToLower(strings.TrimSpace(publication.Format))
+}
+
+// bookContributors fills in the default role of the contributors of a book
+// request and rejects duplicates. A request that only sends author_id gets
+// that author as its single contributor.
+func bookContributors(authorId int, contributors []request.BookContributor) ([]request.BookContributor, error) {
+	if len(contributors) == 0 {
+		return []request.BookContributor{{AuthorId: authorId, Role: data.ContributorAuthor}}, nil
+	}
+
+	seen := map[request.BookContributor]bool{}
+
+	var result []request.BookContributor
+	for _, contributor := range contributors {
+		if contributor.Role == "" {
+			contributor.Role = data.ContributorAuthor
+		}
+
+		contributor.Role = strings.ToLower(strings.TrimSpace(contributor.Role))
+
+		if seen[contributor] {
+			return nil, apperror.Invalid("contributors", "book.contributor_duplicate")
+		}
+
+		seen[contributor] = true
+		result = append(result, contributor)
+	}
+
+	return result, nil
+}
+
+func (s *BookServices) Search(query request.SearchBook) (*[]response.ResultSearchBook, *response.Pagination, error) {
+
+	query.Q = strings.TrimSpace(query.Q)
+
+	if query.Q == "" {
+		return nil, nil, apperror.Invalid("q", "book.search_query_required")
+	}
+
+	// an ISBN is searched in the canonical form it is stored in
+	normalized, err := isbn.Normalize(query.Q)
+	if err == nil {
+		query.Q = normalized
+	}
+
+	page, limit, err := normalizePage(query.Page, query.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query.Page, query.Limit = page, limit
+
+	books, totalItems, err := s.BookRepository.Search(query)
+	if err != nil {
+		return nil, nil, apperror.Internal("book.search_failed", err)
+	}
+
+	if totalItems == 0 {
+		return nil, nil, ErrBookEmpty
+	}
+
+	pagination, err := newPagination(page, limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var listBook []response.ResultSearchBook
+	for _, book := range books {
+		dataBook := response.ResultSearchBook{
+			Id:              book.Id,
+			Title:           book.Title,
+			Isbn:            book.Isbn,
+			TotalCopies:     book.TotalCopies,
+			AvailableCopies: book.AvailableCopies,
+			AuthorBook: response.AuthorBook{
+				ID:        book.AuthorId,
+				Name:      book.AuthorName,
+				BirthDate: book.BirthDate,
+			},
+			Highlight: response.BookHighlight{
+				Title:      book.TitleHighlight,
+				Isbn:       book.IsbnHighlight,
+				AuthorName: book.AuthorNameHighlight,
+			},
+			Score: -book.Rank,
+		}
+
+		listBook = append(listBook, dataBook)
+	}
+
+	return &listBook, pagination, nil
 }
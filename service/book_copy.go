@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/repository"
+	"gorm.io/gorm"
+)
+
+var ErrBookCopyEmpty = errors.New("data eksemplar kosong")
+
+type BookCopyService interface {
+	Save(bookId int, bookCopy request.CreateBookCopy) (*response.BookCopy, error)
+	FindAllByBookId(bookId int) (*[]response.BookCopy, error)
+	FindById(bookId, id int) (*response.BookCopy, error)
+	Update(bookId, id int, bookCopy request.UpdateBookCopy) (*response.BookCopy, error)
+	DeleteById(bookId, id int) (*response.BookCopy, error)
+}
+
+type BookCopyServices struct {
+	BookCopyRepository repository.BookCopyRepository
+	BookRepository     repository.BookRepository
+	PickupDays         int
+}
+
+func NewBookCopyService(bookCopyRepository repository.BookCopyRepository, bookRepository repository.BookRepository, pickupDays int) BookCopyService {
+	return &BookCopyServices{BookCopyRepository: bookCopyRepository, BookRepository: bookRepository, PickupDays: pickupDays}
+}
+
+func (s *BookCopyServices) Save(bookId int, bookCopy request.CreateBookCopy) (*response.BookCopy, error) {
+
+	if bookId <= 0 {
+		return nil, apperror.Invalid("id", "book.id_invalid")
+	}
+
+	if bookCopy.Barcode == "" || bookCopy.Branch == "" {
+		return nil, apperror.Validation("copy.barcode_branch_required")
+	}
+
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = data.CopyConditionGood
+	}
+
+	if bookCopy.Status == "" {
+		bookCopy.Status = data.CopyStatusAvailable
+	}
+
+	if bookCopy.AcquisitionDate == "" {
+		bookCopy.AcquisitionDate = time.Now().Format("2006-01-02")
+	}
+
+	err := validateBookCopy(bookCopy.Condition, bookCopy.Status, bookCopy.AcquisitionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.BookRepository.FindById(bookId)
+	if err != nil {
+		return nil, apperror.NotFound("book.not_found")
+	}
+
+	_, err = s.BookCopyRepository.FindByBarcode(bookCopy.Barcode)
+	if err == nil {
+		return nil, apperror.Conflict("copy.barcode_taken")
+	}
+
+	bookCopy.BookId = bookId
+
+	now := time.Now().UTC()
+
+	copyResponse, err := s.BookCopyRepository.Save(bookCopy, now, now.AddDate(0, 0, s.PickupDays))
+	if err != nil {
+		return nil, apperror.Internal("copy.save_failed", err)
+	}
+
+	return copyResponse, nil
+}
+
+func (s *BookCopyServices) FindAllByBookId(bookId int) (*[]response.BookCopy, error) {
+
+	if bookId <= 0 {
+		return nil, apperror.Invalid("id", "book.id_invalid")
+	}
+
+	_, err := s.BookRepository.FindById(bookId)
+	if err != nil {
+		return nil, apperror.NotFound("book.not_found")
+	}
+
+	copies, err := s.BookCopyRepository.FindAllByBookId(bookId)
+	if err != nil {
+		return nil, apperror.Internal("copy.fetch_failed", err)
+	}
+
+	if len(copies) == 0 {
+		return nil, ErrBookCopyEmpty
+	}
+
+	return &copies, nil
+}
+
+func (s *BookCopyServices) FindById(bookId, id int) (*response.BookCopy, error) {
+
+	if bookId <= 0 || id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	bookCopy, err := s.BookCopyRepository.FindById(bookId, id)
+	if err != nil {
+		return nil, apperror.NotFound("copy.not_found")
+	}
+
+	return &bookCopy, nil
+}
+
+func (s *BookCopyServices) Update(bookId, id int, bookCopy request.UpdateBookCopy) (*response.BookCopy, error) {
+
+	if bookId <= 0 || id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	if bookCopy == (request.UpdateBookCopy{}) {
+		return nil, apperror.Validation("copy.update_empty")
+	}
+
+	err := validateBookCopy(bookCopy.Condition, bookCopy.Status, bookCopy.AcquisitionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if bookCopy.Barcode != "" {
+		existing, err := s.BookCopyRepository.FindByBarcode(bookCopy.Barcode)
+		if err == nil && existing.Id != id {
+			return nil, apperror.Conflict("copy.barcode_taken")
+		}
+	}
+
+	now := time.Now().UTC()
+
+	copyResponse, err := s.BookCopyRepository.Update(bookId, id, bookCopy, now, now.AddDate(0, 0, s.PickupDays))
+	if errors.Is(err, repository.ErrCopyInCirculation) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, apperror.NotFound("copy.update_not_found")
+	}
+
+	return copyResponse, nil
+}
+
+func (s *BookCopyServices) DeleteById(bookId, id int) (*response.BookCopy, error) {
+
+	if bookId <= 0 || id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	bookCopy, err := s.BookCopyRepository.Delete(bookId, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.NotFound("copy.delete_not_found")
+	}
+
+	if errors.Is(err, repository.ErrCopyNotOnShelf) || errors.Is(err, repository.ErrCopyHasLoans) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, apperror.Internal("copy.delete_failed", err)
+	}
+
+	return bookCopy, nil
+}
+
+func validateBookCopy(condition, status, acquisitionDate string) error {
+	if condition != "" && !contains(data.CopyConditions, condition) {
+		return apperror.Invalid("condition", "copy.condition_invalid").With(i18n.Params{"allowed": strings.Join(data.CopyConditions, ", ")})
+	}
+
+	if status != "" && !contains(data.CopyStatuses, status) {
+		return apperror.Invalid("status", "copy.status_invalid").With(i18n.Params{"allowed": strings.Join(data.CopyStatuses, ", ")})
+	}
+
+	if acquisitionDate != "" {
+		date, err := time.Parse("2006-01-02", acquisitionDate)
+		if err != nil {
+			return apperror.Invalid("acquisition_date", "copy.acquisition_date_format")
+		}
+
+		if date.After(time.Now()) {
+			return apperror.Invalid("acquisition_date", "copy.acquisition_date_future")
+		}
+	}
+
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+type CategoryService interface {
+	Save(category request.CreateCategory) (*response.Category, error)
+	FindTree() ([]response.CategoryTree, error)
+	FindById(id int) (*response.Category, error)
+	UpdateById(id int, category request.UpdateCategory) (*response.Category, error)
+	DeleteById(id int) (*response.Category, error)
+}
+
+type CategoryServices struct {
+	CategoryRepository repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepository repository.CategoryRepository) CategoryService {
+	return &CategoryServices{CategoryRepository: categoryRepository}
+}
+
+func (s *CategoryServices) Save(category request.CreateCategory) (*response.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+
+	if category.Name == "" {
+		return nil, apperror.Invalid("name", "category.name_required")
+	}
+
+	if category.ParentId < 0 {
+		return nil, apperror.Invalid("parent_id", "category.parent_id_negative")
+	}
+
+	if category.ParentId != 0 {
+		_, err := s.CategoryRepository.FindById(category.ParentId)
+		if err != nil {
+			return nil, apperror.NotFound("category.parent_not_found")
+		}
+	}
+
+	_, err := s.CategoryRepository.FindByName(category.ParentId, category.Name)
+	if err == nil {
+		return nil, apperror.Conflict("category.name_taken")
+	}
+
+	categoryResponse, err := s.CategoryRepository.Save(category)
+	if err != nil {
+		return nil, apperror.Internal("category.save_failed", err)
+	}
+
+	return &categoryResponse, nil
+}
+
+// FindTree returns every category nested under its parent, siblings are
+// sorted by name.
+func (s *CategoryServices) FindTree() ([]response.CategoryTree, error) {
+	categories, err := s.CategoryRepository.FindAll()
+	if err != nil {
+		return nil, apperror.Internal("category.fetch_failed", err)
+	}
+
+	children := map[int][]response.Category{}
+	for _, category := range categories {
+		parentId := 0
+		if category.ParentId != nil {
+			parentId = *category.ParentId
+		}
+
+		children[parentId] = append(children[parentId], category)
+	}
+
+	return categoryTree(children, 0), nil
+}
+
+func categoryTree(children map[int][]response.Category, parentId int) []response.CategoryTree {
+	tree := []response.CategoryTree{}
+
+	for _, category := range children[parentId] {
+		tree = append(tree, response.CategoryTree{
+			ID:       category.ID,
+			Name:     category.Name,
+			Children: categoryTree(children, category.ID),
+		})
+	}
+
+	return tree
+}
+
+func (s *CategoryServices) FindById(id int) (*response.Category, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	category, err := s.CategoryRepository.FindById(id)
+	if err != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	return &category, nil
+}
+
+func (s *CategoryServices) UpdateById(id int, category request.UpdateCategory) (*response.Category, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	category.Name = strings.TrimSpace(category.Name)
+
+	if category.Name == "" && category.ParentId == nil {
+		return nil, apperror.Validation("category.update_empty")
+	}
+
+	current, err := s.CategoryRepository.FindById(id)
+	if err != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	parentId := 0
+	if current.ParentId != nil {
+		parentId = *current.ParentId
+	}
+
+	if category.ParentId != nil {
+		parentId = *category.ParentId
+
+		if parentId < 0 {
+			return nil, apperror.Invalid("parent_id", "category.parent_id_negative")
+		}
+
+		if parentId != 0 {
+			_, err = s.CategoryRepository.FindById(parentId)
+			if err != nil {
+				return nil, apperror.NotFound("category.parent_not_found")
+			}
+
+			descendants, err := s.CategoryRepository.Descendants(id)
+			if err != nil {
+				return nil, apperror.Internal("category.descendants_failed", err)
+			}
+
+			for _, descendant := range descendants {
+				if descendant == parentId {
+					return nil, apperror.Invalid("parent_id", "category.move_into_descendant")
+				}
+			}
+		}
+	}
+
+	name := current.Name
+	if category.Name != "" {
+		name = category.Name
+	}
+
+	existing, err := s.CategoryRepository.FindByName(parentId, name)
+	if err == nil && existing.ID != id {
+		return nil, apperror.Conflict("category.name_taken")
+	}
+
+	categoryResponse, err := s.CategoryRepository.UpdateById(id, category)
+	if err != nil {
+		return nil, apperror.Internal("category.update_failed", err)
+	}
+
+	return categoryResponse, nil
+}
+
+func (s *CategoryServices) DeleteById(id int) (*response.Category, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	category, err := s.CategoryRepository.DeleteById(id)
+	if errors.Is(err, repository.ErrCategoryHasChildren) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, apperror.NotFound("category.delete_not_found")
+	}
+
+	return category, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+type FinePolicy struct {
+	GraceDays  int
+	MaxPerItem int64
+	Rates      map[string]int64
+}
+
+// Calculate returns the number of whole days a loan is overdue at the given
+// time and the fine for it. Days inside the grace period are free, the rest
+// are charged at the rate of the patron category and capped per item.
+func (p FinePolicy) Calculate(category string, dueDate, at time.Time) (int, int64) {
+	if !at.After(dueDate) {
+		return 0, 0
+	}
+
+	days := int(at.Sub(dueDate) / (24 * time.Hour))
+
+	chargeable := days - p.GraceDays
+	if chargeable <= 0 {
+		return days, 0
+	}
+
+	rate, ok := p.Rates[category]
+	if !ok {
+		rate = p.Rates[data.UserCategoryRegular]
+	}
+
+	amount := int64(chargeable) * rate
+	if p.MaxPerItem > 0 && amount > p.MaxPerItem {
+		amount = p.MaxPerItem
+	}
+
+	return days, amount
+}
+
+type FineService interface {
+	FindByUsername(username string) (*response.FineSummary, error)
+	RecordPayment(staffUsername string, entry request.FineEntry) (*response.FineEntry, error)
+	Waive(staffUsername string, entry request.FineEntry) (*response.FineEntry, error)
+}
+
+type FineServices struct {
+	FineRepository repository.FineRepository
+	LoanRepository repository.LoanRepository
+	UserRepository repository.UserRepository
+	Policy         FinePolicy
+	Now            func() time.Time
+}
+
+func NewFineService(fineRepository repository.FineRepository, loanRepository repository.LoanRepository, userRepository repository.UserRepository, policy FinePolicy) FineService {
+	return &FineServices{
+		FineRepository: fineRepository,
+		LoanRepository: loanRepository,
+		UserRepository: userRepository,
+		Policy:         policy,
+		Now:            time.Now,
+	}
+}
+
+func (s *FineServices) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
+
+func (s *FineServices) FindByUsername(username string) (*response.FineSummary, error) {
+
+	user, err := s.UserRepository.FindByUsername(username)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	ledger, err := s.FineRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return nil, apperror.Internal("fine.fetch_failed", err)
+	}
+
+	balance, err := s.FineRepository.Balance(user.ID, 0)
+	if err != nil {
+		return nil, apperror.Internal("fine.fetch_failed", err)
+	}
+
+	loans, err := s.LoanRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return nil, apperror.Internal("loan.fetch_failed", err)
+	}
+
+	summary := response.FineSummary{
+		Category:      user.Category,
+		Balance:       balance,
+		AccruingLoans: []response.AccruingFine{},
+		Ledger:        ledger,
+	}
+
+	now := s.now()
+
+	for _, loan := range loans {
+		if loan.ReturnedAt != nil {
+			continue
+		}
+
+		days, amount := s.Policy.Calculate(user.Category, loan.DueDate, now)
+		if amount == 0 {
+			continue
+		}
+
+		summary.Accruing += amount
+		summary.AccruingLoans = append(summary.AccruingLoans, response.AccruingFine{
+			LoanId:      loan.Id,
+			Title:       loan.Title,
+			DueDate:     loan.DueDate,
+			DaysOverdue: days,
+			Amount:      amount,
+		})
+	}
+
+	return &summary, nil
+}
+
+func (s *FineServices) RecordPayment(staffUsername string, entry request.FineEntry) (*response.FineEntry, error) {
+	entry.EntryType = data.FineEntryPayment
+
+	return s.record(staffUsername, entry)
+}
+
+func (s *FineServices) Waive(staffUsername string, entry request.FineEntry) (*response.FineEntry, error) {
+	entry.EntryType = data.FineEntryWaiver
+
+	return s.record(staffUsername, entry)
+}
+
+func (s *FineServices) record(staffUsername string, entry request.FineEntry) (*response.FineEntry, error) {
+
+	if entry.UserId <= 0 {
+		return nil, apperror.Invalid("user_id", "fine.user_id_invalid")
+	}
+
+	if entry.LoanId < 0 {
+		return nil, apperror.Invalid("loan_id", "fine.loan_id_negative")
+	}
+
+	if entry.Amount <= 0 {
+		return nil, apperror.Invalid("amount", "fine.amount_positive")
+	}
+
+	staff, err := s.UserRepository.FindByUsername(staffUsername)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	_, err = s.UserRepository.FindById(entry.UserId)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	if entry.LoanId > 0 {
+		loan, err := s.LoanRepository.FindById(entry.LoanId)
+		if err != nil || loan.UserId != entry.UserId {
+			return nil, apperror.NotFound("loan.not_found")
+		}
+	}
+
+	entry.RecordedBy = staff.ID
+	entry.CreatedAt = s.now()
+
+	fine, err := s.FineRepository.Save(entry)
+	if err != nil {
+		if errors.Is(err, repository.ErrFineExceedsBalance) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("fine.record_failed", err)
+	}
+
+	return fine, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+var ErrHoldEmpty = errors.New("data antrean kosong")
+
+type HoldService interface {
+	PlaceHold(username string, bookId int) (*response.Hold, error)
+	Cancel(username string, id int) (*response.Hold, error)
+	FindByUsername(username string) (*[]response.Hold, error)
+	ExpireHolds() (int64, error)
+}
+
+type HoldServices struct {
+	HoldRepository repository.HoldRepository
+	BookRepository repository.BookRepository
+	UserRepository repository.UserRepository
+	PickupDays     int
+	Now            func() time.Time
+}
+
+func NewHoldService(holdRepository repository.HoldRepository, bookRepository repository.BookRepository, userRepository repository.UserRepository, pickupDays int) HoldService {
+	return &HoldServices{
+		HoldRepository: holdRepository,
+		BookRepository: bookRepository,
+		UserRepository: userRepository,
+		PickupDays:     pickupDays,
+		Now:            time.Now,
+	}
+}
+
+func (s *HoldServices) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
+
+func (s *HoldServices) PlaceHold(username string, bookId int) (*response.Hold, error) {
+
+	if bookId <= 0 {
+		return nil, apperror.Invalid("id", "book.id_invalid")
+	}
+
+	user, err := s.UserRepository.FindByUsername(username)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	book, err := s.BookRepository.FindById(bookId)
+	if err != nil {
+		return nil, apperror.NotFound("book.not_found")
+	}
+
+	if book.TotalCopies == 0 {
+		return nil, apperror.Conflict("hold.no_copies")
+	}
+
+	if book.AvailableCopies > 0 {
+		return nil, apperror.Conflict("hold.copy_available")
+	}
+
+	hold, err := s.HoldRepository.Save(request.CreateHold{
+		BookId:    bookId,
+		UserId:    user.ID,
+		CreatedAt: s.now(),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrHoldExists) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("hold.create_failed", err)
+	}
+
+	return hold, nil
+}
+
+func (s *HoldServices) Cancel(username string, id int) (*response.Hold, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	user, err := s.UserRepository.FindByUsername(username)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	hold, err := s.HoldRepository.FindById(id)
+	if err != nil || hold.UserId != user.ID {
+		return nil, repository.ErrHoldNotActive
+	}
+
+	now := s.now()
+
+	cancelled, err := s.HoldRepository.Cancel(id, now, now.AddDate(0, 0, s.PickupDays))
+	if err != nil {
+		if errors.Is(err, repository.ErrHoldNotActive) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("hold.cancel_failed", err)
+	}
+
+	return cancelled, nil
+}
+
+func (s *HoldServices) FindByUsername(username string) (*[]response.Hold, error) {
+
+	user, err := s.UserRepository.FindByUsername(username)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	holds, err := s.HoldRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return nil, apperror.Internal("hold.fetch_failed", err)
+	}
+
+	if len(holds) == 0 {
+		return nil, ErrHoldEmpty
+	}
+
+	return &holds, nil
+}
+
+func (s *HoldServices) ExpireHolds() (int64, error) {
+	now := s.now()
+
+	return s.HoldRepository.Expire(now, now.AddDate(0, 0, s.PickupDays))
+}
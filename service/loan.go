@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+var ErrLoanEmpty = errors.New("data peminjaman kosong")
+
+type LoanService interface {
+	Checkout(username string, loan request.CreateLoan) (*response.Loan, error)
+	Return(id int) (*response.Loan, error)
+	Renew(actor data.Actor, id int) (*response.Loan, error)
+	FindByUsername(username string) (*[]response.Loan, error)
+}
+
+type LoanServices struct {
+	LoanRepository repository.LoanRepository
+	UserRepository repository.UserRepository
+	LoanDays       int
+	MaxRenewals    int
+	HoldPickupDays int
+	FinePolicy     FinePolicy
+	Now            func() time.Time
+}
+
+func NewLoanService(loanRepository repository.LoanRepository, userRepository repository.UserRepository, loanDays, maxRenewals, holdPickupDays int, finePolicy FinePolicy) LoanService {
+	return &LoanServices{
+		LoanRepository: loanRepository,
+		UserRepository: userRepository,
+		LoanDays:       loanDays,
+		MaxRenewals:    maxRenewals,
+		HoldPickupDays: holdPickupDays,
+		FinePolicy:     finePolicy,
+		Now:            time.Now,
+	}
+}
+
+func (s *LoanServices) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
+
+func (s *LoanServices) Checkout(username string, loan request.CreateLoan) (*response.Loan, error) {
+
+	if loan.CopyId <= 0 {
+		return nil, apperror.Invalid("copy_id", "loan.copy_id_invalid")
+	}
+
+	if loan.UserId < 0 {
+		return nil, apperror.Invalid("user_id", "loan.user_id_negative")
+	}
+
+	if loan.UserId == 0 {
+		user, err := s.UserRepository.FindByUsername(username)
+		if err != nil {
+			return nil, apperror.NotFound("user.not_found")
+		}
+
+		loan.UserId = user.ID
+	} else {
+		_, err := s.UserRepository.FindById(loan.UserId)
+		if err != nil {
+			return nil, apperror.NotFound("user.not_found")
+		}
+	}
+
+	loan.LoanedAt = s.now()
+	loan.DueDate = loan.LoanedAt.AddDate(0, 0, s.LoanDays)
+
+	loanResponse, err := s.LoanRepository.Checkout(loan)
+	if err != nil {
+		if errors.Is(err, repository.ErrCopyNotFound) || errors.Is(err, repository.ErrCopyNotAvailable) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("loan.checkout_failed", err)
+	}
+
+	return s.withOverdue(*loanResponse), nil
+}
+
+func (s *LoanServices) Return(id int) (*response.Loan, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	active, err := s.LoanRepository.FindById(id)
+	if err != nil || active.ReturnedAt != nil {
+		return nil, repository.ErrLoanNotActive
+	}
+
+	user, err := s.UserRepository.FindById(active.UserId)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	now := s.now()
+	_, fine := s.FinePolicy.Calculate(user.Category, active.DueDate, now)
+
+	loan, err := s.LoanRepository.Return(id, request.ReturnLoan{
+		ReturnedAt:    now,
+		HoldExpiresAt: now.AddDate(0, 0, s.HoldPickupDays),
+		Fine:          fine,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrLoanNotActive) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("loan.return_failed", err)
+	}
+
+	return s.withOverdue(*loan), nil
+}
+
+// Renew extends the due date of the actor's own loan, staff may renew any
+// loan. An overdue loan has to be returned instead so its fine is charged.
+func (s *LoanServices) Renew(actor data.Actor, id int) (*response.Loan, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	loan, err := s.LoanRepository.FindById(id)
+	if err != nil || loan.ReturnedAt != nil {
+		return nil, repository.ErrLoanNotActive
+	}
+
+	if !actor.HasRole(data.StaffRoles...) {
+		user, err := s.UserRepository.FindByUsername(actor.Username)
+		if err != nil || user.ID != loan.UserId {
+			return nil, repository.ErrLoanNotActive
+		}
+	}
+
+	if s.now().After(loan.DueDate) {
+		return nil, apperror.Conflict("loan.renew_overdue")
+	}
+
+	if loan.RenewalCount >= s.MaxRenewals {
+		return nil, apperror.Conflict("loan.renewal_limit")
+	}
+
+	renewed, err := s.LoanRepository.Renew(id, loan.DueDate.AddDate(0, 0, s.LoanDays), s.MaxRenewals)
+	if err != nil {
+		if errors.Is(err, repository.ErrLoanNotActive) || errors.Is(err, repository.ErrLoanOnHold) {
+			return nil, err
+		}
+
+		return nil, apperror.Internal("loan.renew_failed", err)
+	}
+
+	return s.withOverdue(*renewed), nil
+}
+
+func (s *LoanServices) FindByUsername(username string) (*[]response.Loan, error) {
+
+	user, err := s.UserRepository.FindByUsername(username)
+	if err != nil {
+		return nil, apperror.NotFound("user.not_found")
+	}
+
+	loans, err := s.LoanRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return nil, apperror.Internal("loan.fetch_failed", err)
+	}
+
+	if len(loans) == 0 {
+		return nil, ErrLoanEmpty
+	}
+
+	for i := range loans {
+		loans[i] = *s.withOverdue(loans[i])
+	}
+
+	return &loans, nil
+}
+
+func (s *LoanServices) withOverdue(loan response.Loan) *response.Loan {
+	loan.Overdue = loan.ReturnedAt == nil && s.now().After(loan.DueDate)
+
+	return &loan
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+func normalizePage(page, limit int) (int, int, error) {
+	if page < 0 {
+		return 0, 0, apperror.Invalid("page", "pagination.page_negative")
+	}
+
+	if limit < 0 {
+		return 0, 0, apperror.Invalid("limit", "pagination.limit_negative")
+	}
+
+	if page == 0 {
+		page = defaultPage
+	}
+
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	if limit > maxLimit {
+		return 0, 0, apperror.Invalid("limit", "pagination.limit_max")
+	}
+
+	return page, limit, nil
+}
+
+func normalizeSort(sort, order string, allowed []string) (string, string, error) {
+	sort = strings.ToLower(sort)
+	order = strings.ToLower(order)
+
+	if sort == "" {
+		sort = "id"
+	}
+
+	if order == "" {
+		order = "asc"
+	}
+
+	if !contains(allowed, sort) {
+		return "", "", apperror.Invalid("sort", "pagination.sort_invalid").With(i18n.Params{"allowed": strings.Join(allowed, ", ")})
+	}
+
+	if order != "asc" && order != "desc" {
+		return "", "", apperror.Invalid("order", "pagination.order_invalid")
+	}
+
+	return sort, order, nil
+}
+
+func newPagination(page, limit int, totalItems int64) (*response.Pagination, error) {
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	if page > totalPages {
+		return nil, apperror.Invalid("page", "pagination.page_exceeded")
+	}
+
+	return &response.Pagination{
+		CurrentPage: page,
+		TotalPage:   totalPages,
+		Limit:       limit,
+		TotalItems:  totalItems,
+	}, nil
+}
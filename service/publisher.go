@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+var ErrPublisherEmpty = errors.New("data publisher kosong")
+
+type PublisherService interface {
+	Save(publisher request.CreatePublisher) (*response.Publisher, error)
+	FindAll(query request.PublisherQuery) (*[]response.Publisher, *response.Pagination, error)
+	FindById(id int) (*response.Publisher, error)
+	DeleteById(id int) (*response.Publisher, error)
+	UpdateById(id int, publisher request.UpdatePublisher) (*response.Publisher, error)
+}
+
+type PublisherServices struct {
+	PublisherRepository repository.PublisherRepository
+}
+
+func NewPublisherService(publisherRepository repository.PublisherRepository) PublisherService {
+	return &PublisherServices{PublisherRepository: publisherRepository}
+}
+
+func (s *PublisherServices) Save(publisher request.CreatePublisher) (*response.Publisher, error) {
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	publisher.City = strings.TrimSpace(publisher.City)
+
+	if publisher.Name == "" {
+		return nil, apperror.Invalid("name", "publisher.name_required")
+	}
+
+	if len(publisher.Name) < 2 {
+		return nil, apperror.Invalid("name", "publisher.name_min")
+	}
+
+	_, err := s.PublisherRepository.FindByName(publisher.Name)
+	if err == nil {
+		return nil, apperror.Conflict("publisher.name_taken")
+	}
+
+	publisherResponse, err := s.PublisherRepository.Save(publisher)
+	if err != nil {
+		return nil, apperror.Internal("publisher.save_failed", err)
+	}
+
+	return &publisherResponse, nil
+}
+
+func (s *PublisherServices) FindAll(query request.PublisherQuery) (*[]response.Publisher, *response.Pagination, error) {
+	page, limit, err := normalizePage(query.Page, query.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort, order, err := normalizeSort(query.Sort, query.Order, repository.PublisherSortFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query.Page, query.Limit, query.Sort, query.Order = page, limit, sort, order
+
+	publishers, totalItems, err := s.PublisherRepository.FindAll(query)
+	if err != nil {
+		return nil, nil, apperror.Internal("publisher.fetch_failed", err)
+	}
+
+	if totalItems == 0 {
+		return nil, nil, ErrPublisherEmpty
+	}
+
+	pagination, err := newPagination(page, limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &publishers, pagination, nil
+}
+
+func (s *PublisherServices) FindById(id int) (*response.Publisher, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	publisher, err := s.PublisherRepository.FindById(id)
+	if err != nil {
+		return nil, apperror.NotFound("publisher.not_found")
+	}
+
+	return &publisher, nil
+}
+
+func (s *PublisherServices) DeleteById(id int) (*response.Publisher, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	publisher, err := s.PublisherRepository.DeleteById(id)
+	if errors.Is(err, repository.ErrPublisherInUse) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, apperror.NotFound("publisher.delete_not_found")
+	}
+
+	return publisher, nil
+}
+
+func (s *PublisherServices) UpdateById(id int, publisher request.UpdatePublisher) (*response.Publisher, error) {
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	publisher.City = strings.TrimSpace(publisher.City)
+
+	if publisher.Name == "" && publisher.City == "" {
+		return nil, apperror.Validation("publisher.update_empty")
+	}
+
+	if publisher.Name != "" && len(publisher.Name) < 2 {
+		return nil, apperror.Invalid("name", "publisher.name_min")
+	}
+
+	if publisher.Name != "" {
+		existing, err := s.PublisherRepository.FindByName(publisher.Name)
+		if err == nil && existing.ID != id {
+			return nil, apperror.Conflict("publisher.name_taken")
+		}
+	}
+
+	publisherResponse, err := s.PublisherRepository.UpdateById(id, publisher)
+	if err != nil {
+		return nil, apperror.NotFound("publisher.update_not_found")
+	}
+
+	return publisherResponse, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+)
+
+const (
+	accessTokenTTL  = time.Hour
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrRefreshTokenInvalid = apperror.Unauthorized("user.refresh_token_invalid")
+)
+
+type issuedTokens struct {
+	login        *response.ResponseUserLogin
+	refreshToken request.RefreshToken
+}
+
+// issueTokens signs a new access token and creates the refresh token that
+// belongs to the same session family. Only the hash of the refresh token is
+// kept for storage, the plain value goes back to the client once.
+func (s *UserServices) issueTokens(user response.User, familyId string) (*issuedTokens, error) {
+	now := s.now()
+
+	jti, err := newTokenId()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newTokenId()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &data.Claims{
+		Username: user.Username,
+		Role:     user.Role,
+		Session:  familyId,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(accessTokenTTL).Unix(),
+		},
+	}
+
+	tokenString, err := s.Keys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuedTokens{
+		login: &response.ResponseUserLogin{
+			Username:     user.Username,
+			Role:         user.Role,
+			Token:        tokenString,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int64(accessTokenTTL.Seconds()),
+		},
+		refreshToken: request.RefreshToken{
+			UserId:          user.ID,
+			FamilyId:        familyId,
+			TokenHash:       hashToken(refreshToken),
+			AccessJti:       jti,
+			AccessExpiresAt: now.Add(accessTokenTTL),
+			ExpiresAt:       now.Add(refreshTokenTTL),
+			CreatedAt:       now,
+		},
+	}, nil
+}
+
+func (s *UserServices) Refresh(refresh request.Refresh) (*response.ResponseUserLogin, error) {
+
+	if refresh.RefreshToken == "" {
+		return nil, apperror.Invalid("refresh_token", "user.refresh_token_required")
+	}
+
+	now := s.now()
+
+	stored, err := s.TokenRepository.FindByHash(hashToken(refresh.RefreshToken))
+	if err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	if stored.RevokedAt != nil {
+		if stored.ReplacedBy == nil {
+			return nil, ErrRefreshTokenInvalid
+		}
+
+		err = s.TokenRepository.RevokeFamily(stored.FamilyId, now)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, repository.ErrRefreshTokenReused
+	}
+
+	if now.After(stored.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	user, err := s.UserRepository.FindById(stored.UserId)
+	if err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	session, err := s.issueTokens(user, stored.FamilyId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.TokenRepository.Rotate(stored.Id, session.refreshToken, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			s.TokenRepository.RevokeFamily(stored.FamilyId, now)
+		}
+
+		return nil, err
+	}
+
+	return session.login, nil
+}
+
+func (s *UserServices) Logout(claims *data.Claims) error {
+	now := s.now()
+
+	if claims.Session != "" {
+		err := s.TokenRepository.RevokeFamily(claims.Session, now)
+		if err != nil {
+			return apperror.Internal("user.logout_failed", err)
+		}
+	}
+
+	if claims.Id != "" {
+		err := s.TokenRepository.RevokeAccess(claims.Id, time.Unix(claims.ExpiresAt, 0))
+		if err != nil {
+			return apperror.Internal("user.logout_failed", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *UserServices) IsRevoked(jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+
+	return s.TokenRepository.IsRevoked(jti)
+}
+
+func newTokenId() (string, error) {
+	buf := make([]byte, 32)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/validation"
+)
+
+// ErrTrashEmpty is not a failure, controllers answer it with an empty list.
+var ErrTrashEmpty = errors.New("trash kosong")
+
+type TrashService interface {
+	FindAll(query request.TrashQuery) (*[]response.TrashItem, *response.Pagination, error)
+	Purge() (*response.PurgedTrash, error)
+}
+
+type TrashServices struct {
+	TrashRepository repository.TrashRepository
+	RetentionDays   int
+	Now             func() time.Time
+}
+
+func NewTrashService(trashRepository repository.TrashRepository, retentionDays int) TrashService {
+	return &TrashServices{
+		TrashRepository: trashRepository,
+		RetentionDays:   retentionDays,
+		Now:             time.Now,
+	}
+}
+
+func (s *TrashServices) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+
+	return s.Now()
+}
+
+func (s *TrashServices) FindAll(query request.TrashQuery) (*[]response.TrashItem, *response.Pagination, error) {
+	err := validation.Struct(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query.Page, query.Limit, err = normalizePage(query.Page, query.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, totalItems, err := s.TrashRepository.FindAll(query)
+	if err != nil {
+		return nil, nil, apperror.Internal("trash.fetch_failed", err)
+	}
+
+	if totalItems == 0 {
+		return nil, nil, ErrTrashEmpty
+	}
+
+	pagination, err := newPagination(query.Page, query.Limit, totalItems)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.AddDate(0, 0, s.RetentionDays)
+	}
+
+	return &items, pagination, nil
+}
+
+// Purge removes for good what has been in the trash for longer than the
+// retention period.
+func (s *TrashServices) Purge() (*response.PurgedTrash, error) {
+	purged, err := s.TrashRepository.Purge(s.now().AddDate(0, 0, -s.RetentionDays))
+	if err != nil {
+		return nil, apperror.Internal("trash.purge_failed", err)
+	}
+
+	return purged, nil
+}
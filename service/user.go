@@ -1,75 +1,138 @@
 package service
 
 import (
-	"errors"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt"
+	"github.com/ilhaamms/library-api/apperror"
 	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/jwtkey"
 	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/validation"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
-	Save(user request.User) (*response.CreateUser, error)
+	Save(actor data.Actor, user request.User) (*response.CreateUser, error)
+	CreateAdmin(actor data.Actor, user request.User) error
 	CheckUsername(username string) (bool, error)
 	Login(user request.User) (bool, *response.ResponseUserLogin, error)
+	Refresh(refresh request.Refresh) (*response.ResponseUserLogin, error)
+	Logout(claims *data.Claims) error
+	IsRevoked(jti string) (bool, error)
+	UpdateCategory(actor data.Actor, id int, user request.UpdateUserCategory) (*response.UserProfile, error)
+	UpdateRole(actor data.Actor, id int, user request.UpdateUserRole) (*response.UserProfile, error)
 }
 
 type UserServices struct {
-	UserRepository repository.UserRepository
+	UserRepository  repository.UserRepository
+	TokenRepository repository.TokenRepository
+	AuditRepository repository.AuditRepository
+	Transactor      repository.Transactor
+	Keys            *jwtkey.KeySet
+	Now             func() time.Time
 }
 
-func NewUserService(userRepository repository.UserRepository) UserService {
-	return &UserServices{UserRepository: userRepository}
+func NewUserService(userRepository repository.UserRepository, tokenRepository repository.TokenRepository, auditRepository repository.AuditRepository, transactor repository.Transactor, keys *jwtkey.KeySet) UserService {
+	return &UserServices{UserRepository: userRepository, TokenRepository: tokenRepository, AuditRepository: auditRepository, Transactor: transactor, Keys: keys, Now: time.Now}
 }
 
-func (s *UserServices) Save(user request.User) (*response.CreateUser, error) {
+// transaction runs a change to users and its audit entry in one transaction.
+func (s *UserServices) transaction(fn func(repos repository.Repositories) error) error {
+	return inTransaction(s.Transactor, repository.Repositories{User: s.UserRepository, Audit: s.AuditRepository}, fn)
+}
 
-	if user.Username == "" || user.Password == "" {
-		return nil, errors.New("username dan password wajib diisi")
+func (s *UserServices) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
 	}
 
-	isUsername, err := s.CheckUsername(user.Username)
+	return s.Now()
+}
+
+// Save registers a member. Registration never hands out another role, the
+// first admin is created with CreateAdmin.
+func (s *UserServices) Save(actor data.Actor, user request.User) (*response.CreateUser, error) {
+
+	user.Role = data.RoleMember
+
+	hashed, err := s.create(actor, user)
 	if err != nil {
 		return nil, err
 	}
 
-	if isUsername {
-		return nil, errors.New("username sudah digunakan oleh user lain")
+	return &response.CreateUser{
+		Username: user.Username,
+		Password: hashed,
+	}, nil
+}
+
+// CreateAdmin creates an admin account, used by the create-admin command to
+// bootstrap a fresh deployment.
+func (s *UserServices) CreateAdmin(actor data.Actor, user request.User) error {
+
+	user.Role = data.RoleAdmin
+
+	_, err := s.create(actor, user)
+
+	return err
+}
+
+// create validates and stores the user with its password hashed and returns
+// the hash.
+func (s *UserServices) create(actor data.Actor, user request.User) (string, error) {
+
+	err := validation.Struct(user)
+	if err != nil {
+		return "", err
+	}
+
+	isUsername, err := s.CheckUsername(user.Username)
+	if err != nil {
+		return "", err
 	}
 
-	if len(user.Password) < 8 {
-		return nil, errors.New("harap masukkan password minimal 8 karakter")
+	if isUsername {
+		return "", apperror.Conflict("user.username_taken")
 	}
 
 	bcryptPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	user.Password = string(bcryptPassword)
 
-	err = s.UserRepository.Save(user)
+	err = s.transaction(func(repos repository.Repositories) error {
+		err := repos.User.Save(user)
+		if err != nil {
+			return err
+		}
+
+		saved, err := repos.User.FindByUsername(user.Username)
+		if err != nil {
+			return apperror.Internal("error.internal", err)
+		}
+
+		return audit(repos.Audit, actor, data.AuditCreate, data.AuditUser, saved.ID, nil, userProfile(saved))
+	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return &response.CreateUser{
-		Username: user.Username,
-		Password: user.Password,
-	}, nil
+	return user.Password, nil
 }
 
 func (s *UserServices) CheckUsername(username string) (bool, error) {
 	if len(username) < 5 {
-		return false, errors.New("username minimal 5 karakter")
+		return false, apperror.Invalid("username", "user.username_min")
 	}
 
 	if len(username) > 20 {
-		return false, errors.New("username maksimal 20 karakter")
+		return false, apperror.Invalid("username", "user.username_max")
 	}
 
 	dataUsername, err := s.UserRepository.CheckUsername(username)
@@ -83,35 +146,127 @@ func (s *UserServices) CheckUsername(username string) (bool, error) {
 func (s *UserServices) Login(user request.User) (bool, *response.ResponseUserLogin, error) {
 
 	if user.Username == "" || user.Password == "" {
-		return false, nil, errors.New("username dan password wajib diisi")
+		return false, nil, apperror.Validation("user.credentials_required")
 	}
 
 	dataUser, err := s.UserRepository.GetUserByUsername(user.Username)
 	if err != nil {
-		return false, nil, errors.New("username atau password salah")
+		return false, nil, apperror.Unauthorized("user.login_failed")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(dataUser.Password), []byte(user.Password))
 	if err != nil {
-		return false, nil, errors.New("username atau password salah")
+		return false, nil, apperror.Unauthorized("user.login_failed")
 	}
 
-	claims := &data.Claims{
-		Username: dataUser.Username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 1).Unix(),
-		},
+	familyId, err := newTokenId()
+	if err != nil {
+		return false, nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(data.JwtKey))
+	session, err := s.issueTokens(response.User{ID: dataUser.ID, Username: dataUser.Username, Role: dataUser.Role}, familyId)
 	if err != nil {
 		return false, nil, err
 	}
 
-	return true, &response.ResponseUserLogin{
-		Username: dataUser.Username,
-		Password: dataUser.Password,
-		Token:    tokenString,
-	}, nil
+	err = s.TokenRepository.Save(session.refreshToken)
+	if err != nil {
+		return false, nil, err
+	}
+
+	session.login.Password = dataUser.Password
+
+	return true, session.login, nil
+}
+
+func (s *UserServices) UpdateCategory(actor data.Actor, id int, user request.UpdateUserCategory) (*response.UserProfile, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	if !contains(data.UserCategories, user.Category) {
+		return nil, apperror.Invalid("category", "user.category_invalid").With(i18n.Params{"allowed": strings.Join(data.UserCategories, ", ")})
+	}
+
+	var updated response.UserProfile
+
+	err := s.transaction(func(repos repository.Repositories) error {
+		dataUser, err := repos.User.FindById(id)
+		if err != nil {
+			return apperror.NotFound("user.not_found")
+		}
+
+		err = repos.User.UpdateCategory(id, user.Category)
+		if err != nil {
+			return apperror.Internal("user.category_update_failed", err)
+		}
+
+		updated = userProfile(dataUser)
+		updated.Category = user.Category
+
+		return audit(repos.Audit, actor, data.AuditUpdate, data.AuditUser, id, userProfile(dataUser), updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (s *UserServices) UpdateRole(actor data.Actor, id int, user request.UpdateUserRole) (*response.UserProfile, error) {
+
+	if id <= 0 {
+		return nil, apperror.Invalid("id", "request.id_invalid")
+	}
+
+	if !contains(data.Roles, user.Role) {
+		return nil, apperror.Invalid("role", "user.role_invalid").With(i18n.Params{"allowed": strings.Join(data.Roles, ", ")})
+	}
+
+	var updated response.UserProfile
+
+	err := s.transaction(func(repos repository.Repositories) error {
+		dataUser, err := repos.User.FindById(id)
+		if err != nil {
+			return apperror.NotFound("user.not_found")
+		}
+
+		if dataUser.Role == data.RoleAdmin && user.Role != data.RoleAdmin {
+			admins, err := repos.User.CountByRole(data.RoleAdmin)
+			if err != nil {
+				return apperror.Internal("user.role_update_failed", err)
+			}
+
+			if admins <= 1 {
+				return apperror.Conflict("user.last_admin")
+			}
+		}
+
+		err = repos.User.UpdateRole(id, user.Role)
+		if err != nil {
+			return apperror.Internal("user.role_update_failed", err)
+		}
+
+		updated = userProfile(dataUser)
+		updated.Role = user.Role
+
+		return audit(repos.Audit, actor, data.AuditUpdate, data.AuditUser, id, userProfile(dataUser), updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// userProfile is the user without the password hash, the form users are
+// audited in.
+func userProfile(user response.User) response.UserProfile {
+	return response.UserProfile{
+		ID:       user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		Category: user.Category,
+	}
 }
@@ -0,0 +1,208 @@
+package configtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ilhaamms/library-api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigDefault(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "db/library.db", cfg.DBPath)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, ":8080", cfg.Addr())
+}
+
+func TestLoadConfigFailedJwtSecretEmpty(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "jwt_secret minimal 16 karakter", err.Error())
+}
+
+func TestLoadConfigFailedJwtSecretExample(t *testing.T) {
+	for _, secret := range config.ExampleJwtSecrets {
+		t.Setenv(config.EnvJwtSecret, secret)
+
+		cfg, err := config.Load(nil)
+
+		assert.Nil(t, cfg)
+		assert.NotNil(t, err)
+		assert.Equal(t, "jwt_secret masih memakai contoh yang sudah diketahui umum, ganti dengan secret acak", err.Error())
+	}
+}
+
+func TestLoadConfigFailedExampleFile(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "")
+	os.Unsetenv(config.EnvJwtSecret)
+
+	// the example only runs once a secret of its own is filled in
+	cfg, err := config.Load([]string{"-config", "../../config.example.yaml"})
+
+	assert.Nil(t, cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "jwt_secret minimal 16 karakter", err.Error())
+}
+
+func TestLoadConfigFailedPortInvalid(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+	t.Setenv(config.EnvPort, "abc")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "LIBRARY_PORT harus berupa angka", err.Error())
+}
+
+func TestLoadConfigFromYamlFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("db_path: /data/library.db\nport: 9000\njwt_secret: secret-from-yaml-file\n"), 0600)
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/data/library.db", cfg.DBPath)
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Equal(t, "secret-from-yaml-file", cfg.JwtSecret)
+}
+
+func TestLoadConfigFromTomlFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("db_path = \"/data/library.db\"\nport = 9001\njwt_secret = \"secret-from-toml-file\"\n"), 0600)
+
+	t.Setenv(config.EnvConfigFile, path)
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/data/library.db", cfg.DBPath)
+	assert.Equal(t, 9001, cfg.Port)
+	assert.Equal(t, "secret-from-toml-file", cfg.JwtSecret)
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("db_path: /data/file.db\nport: 9000\njwt_secret: secret-from-yaml-file\n"), 0600)
+
+	t.Setenv(config.EnvDBPath, "/data/env.db")
+	t.Setenv(config.EnvPort, "9100")
+
+	cfg, err := config.Load([]string{"-config", path, "-port", "9200"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/data/env.db", cfg.DBPath)
+	assert.Equal(t, 9200, cfg.Port)
+	assert.Equal(t, "secret-from-yaml-file", cfg.JwtSecret)
+}
+
+func TestLoadConfigFailedFileFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte("{}"), 0600)
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "file config harus berformat .yaml, .yml atau .toml", err.Error())
+}
+
+func TestLoadConfigFineRatesFromYamlFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("jwt_secret: secret-from-yaml-file\nfine_grace_days: 2\nfine_rates:\n  student: 25000\n"), 0600)
+
+	t.Setenv(config.EnvFineMaxPerItem, "750000")
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, cfg.FineGraceDays)
+	assert.Equal(t, int64(750000), cfg.FineMaxPerItem)
+	assert.Equal(t, int64(25000), cfg.FineRates["student"])
+	assert.Equal(t, int64(100000), cfg.FineRates["regular"])
+}
+
+func TestLoadConfigFailedFineRateNegative(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("jwt_secret: secret-from-yaml-file\nfine_rates:\n  senior: -1\n"), 0600)
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, cfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, "fine_rates.senior tidak boleh negatif", err.Error())
+}
+
+func TestLoadConfigJwtKeysWithoutSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("jwt_active_key: key-1\njwt_keys:\n  - kid: key-1\n    algorithm: RS256\n    private_key: keys/key-1.pem\n"), 0600)
+
+	t.Setenv(config.EnvJwtSecret, "")
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "key-1", cfg.JwtActiveKey)
+	assert.Equal(t, "keys/key-1.pem", cfg.JwtKeys[0].PrivateKey)
+}
+
+func TestLoadConfigFailedJwtActiveKeyUnknown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("jwt_active_key: key-2\njwt_keys:\n  - kid: key-1\n    algorithm: RS256\n    private_key: keys/key-1.pem\n"), 0600)
+
+	cfg, err := config.Load([]string{"-config", path})
+
+	assert.Nil(t, cfg)
+	assert.Equal(t, "jwt_active_key harus salah satu kid pada jwt_keys", err.Error())
+}
+
+func TestLoadConfigFailedRequireSignedWithoutKeys(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+	t.Setenv(config.EnvRequireSigned, "true")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, cfg)
+	assert.Equal(t, "require_signed_requests membutuhkan signing_keys", err.Error())
+}
+
+func TestLoadConfigPostgresDriver(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+	t.Setenv(config.EnvDBDriver, "postgres")
+	t.Setenv(config.EnvDBDSN, "host=localhost dbname=library")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, config.DriverPostgres, cfg.DBDriver)
+	assert.Equal(t, "host=localhost dbname=library", cfg.DBDSN)
+}
+
+func TestLoadConfigFailedDsnEmpty(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+
+	cfg, err := config.Load([]string{"-db-driver", "mysql"})
+
+	assert.Nil(t, cfg)
+	assert.Equal(t, "db_dsn wajib diisi untuk driver mysql", err.Error())
+}
+
+func TestLoadConfigFailedDriverUnknown(t *testing.T) {
+	t.Setenv(config.EnvJwtSecret, "library-api-secret-key")
+	t.Setenv(config.EnvDBDriver, "oracle")
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, cfg)
+	assert.Equal(t, "db_driver hanya boleh salah satu dari : sqlite, postgres, mysql", err.Error())
+}
@@ -0,0 +1,146 @@
+package controllertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ilhaamms/library-api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookHistory(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, _ := RequestConditional(r, http.MethodPatch, "/books/1", `{"title": "The Rainbow Troops"}`, token, "X-Request-Id", "req-42")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "req-42", recorder.Header().Get("X-Request-Id"))
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, recorder.Header().Get("X-Request-Id"), 32)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1/history", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(3), responseBody["pagination"].(map[string]interface{})["total_items"])
+
+	entries := responseBody["data"].([]interface{})
+
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.(map[string]interface{})["action"].(string))
+	}
+
+	// the book is deleted, its history is still there
+	assert.Equal(t, []string{"delete", "update", "create"}, actions)
+
+	update := entries[1].(map[string]interface{})
+	assert.Equal(t, "ilhamm.ms", update["actor"])
+	assert.Equal(t, "admin", update["actor_role"])
+	assert.Equal(t, "req-42", update["request_id"])
+	assert.NotEmpty(t, update["client_ip"])
+
+	changes := update["changes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"before": "Laskar Pelangi", "after": "The Rainbow Troops"}, changes["title"])
+	assert.NotContains(t, changes, "isbn")
+
+	created := entries[2].(map[string]interface{})["changes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"before": nil, "after": "9789793062792"}, created["isbn"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/2/history", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Riwayat audit kosong", responseBody["message"])
+}
+
+func TestAuditQuery(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata Seman Said Harun", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, _ := RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=reassign&to=2", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/audit?entity=author&id=1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	entries := responseBody["data"].([]interface{})
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "delete", entries[0].(map[string]interface{})["action"])
+
+	// the books moved to another author are part of the trail
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/1/history", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	reassigned := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "update", reassigned["action"])
+	assert.Equal(t, map[string]interface{}{"before": float64(1), "after": float64(2)}, reassigned["changes"].(map[string]interface{})["author_id"])
+
+	// a registration has no actor, only where it came from
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/audit?entity=user", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	registered := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "create", registered["action"])
+	assert.Equal(t, "", registered["actor"])
+	assert.NotContains(t, registered["changes"], "password")
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/audit?actor=ilhamm.ms&limit=2", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(5), responseBody["pagination"].(map[string]interface{})["total_items"])
+	assert.Len(t, responseBody["data"], 2)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/audit?entity=loan", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"entity": "oneof"}, DetailCodes(responseBody))
+
+	db, err := config.InitDB(&testConfig)
+	assert.Nil(t, err)
+
+	// entries cannot be rewritten or removed, not even outside the API
+	err = db.Exec("UPDATE audit_log SET actor = 'someone.else'").Error
+	assert.ErrorContains(t, err, "audit_log is append only")
+
+	err = db.Exec("DELETE FROM audit_log").Error
+	assert.ErrorContains(t, err, "audit_log is append only")
+}
+
+func TestAuditFailureRollsBackChange(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	db, err := config.InitDB(&testConfig)
+	assert.Nil(t, err)
+
+	// with the table out of the way no entry can be recorded
+	assert.Nil(t, db.Exec("ALTER TABLE audit_log RENAME TO audit_log_away").Error)
+	defer func() {
+		if db.Migrator().HasTable("audit_log_away") {
+			db.Exec("ALTER TABLE audit_log_away RENAME TO audit_log")
+		}
+	}()
+
+	recorder, _ := RequestBookCopy(r, http.MethodPut, "/authors/1", `{"name": "Andrea Hirata Seman Said Harun"}`, token)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	assert.Nil(t, db.Exec("ALTER TABLE audit_log_away RENAME TO audit_log").Error)
+
+	// the changes went back with the entries that could not be recorded
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/authors/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Andrea Hirata", responseBody["data"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["version"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
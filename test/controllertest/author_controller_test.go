@@ -10,55 +10,23 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ilhaamms/library-api/config"
-	"github.com/ilhaamms/library-api/controller"
-	"github.com/ilhaamms/library-api/middleware"
-	"github.com/ilhaamms/library-api/repository"
-	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/testdb"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
 func TruncateAuthorTable(db *gorm.DB) {
-	db.Exec("DELETE FROM author")
-
-	db.Exec("DELETE FROM sqlite_sequence WHERE name='author'")
+	testdb.Truncate(db, "author")
 }
 
 func SetupRouterAuthor() *gin.Engine {
 
-	gin.SetMode(gin.TestMode)
-
-	db, err := config.InitDbSQLite()
-	if err != nil {
-		panic(err)
-	}
+	db := OpenDB()
 
+	TruncateTableBook(db)
 	TruncateAuthorTable(db)
 
-	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userController := controller.NewUserController(userService)
-
-	authorRepo := repository.NewAuthorRepository(db)
-	authorService := service.NewAuthorService(authorRepo)
-	authorController := controller.NewAuthorController(authorService)
-
-	r := gin.Default()
-
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", userController.Register)
-		auth.POST("/login", userController.Login)
-	}
-
-	r.POST("/authors", middleware.Auth(), authorController.CreateAuthor)
-	r.GET("/authors", middleware.Auth(), authorController.GetAllAuthor)
-	r.GET("/authors/:id", middleware.Auth(), authorController.GetAuthorsById)
-	r.DELETE("/authors/:id", middleware.Auth(), authorController.DeleteAuthorsById)
-	r.PUT("/authors/:id", middleware.Auth(), authorController.UpdateAuthorsById)
-
-	return r
+	return NewRouter(db)
 }
 
 func RequestRegisterUser(r *gin.Engine, reqBody string) *httptest.ResponseRecorder {
@@ -112,7 +80,7 @@ func TestCreateAuthorUnauthenticated(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "invalid token", responseBody["message"])
+	assert.Equal(t, "error : token tidak valid", responseBody["error"])
 }
 
 func TestCreateAuthorSuccess(t *testing.T) {
@@ -123,8 +91,7 @@ func TestCreateAuthorSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 	assert.Equal(t, http.StatusOK, recorderLogin.Code)
@@ -169,8 +136,7 @@ func TestCreateAuthorFailedNameEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -194,8 +160,9 @@ func TestCreateAuthorFailedNameEmpty(t *testing.T) {
 	var responseAuthor map[string]interface{}
 	json.Unmarshal(body, &responseAuthor)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : nama dan tanggal lahir tidak boleh kosong", responseAuthor["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseAuthor["error"])
+	assert.Equal(t, map[string]string{"name": "required"}, DetailCodes(responseAuthor))
 }
 
 func TestCreateAuthorFailedNameLength(t *testing.T) {
@@ -206,8 +173,7 @@ func TestCreateAuthorFailedNameLength(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -228,7 +194,7 @@ func TestCreateAuthorFailedNameLength(t *testing.T) {
 	}`
 
 	recorderCreateAuthor := RequestCreateAuthor(r, reqBody, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateAuthor.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateAuthor.Code)
 
 	response = recorderCreateAuthor.Result()
 
@@ -237,8 +203,9 @@ func TestCreateAuthorFailedNameLength(t *testing.T) {
 	var responseAuthor map[string]interface{}
 	json.Unmarshal(body, &responseAuthor)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : nama minimal 3 karakter", responseAuthor["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseAuthor["error"])
+	assert.Equal(t, map[string]string{"name": "min"}, DetailCodes(responseAuthor))
 }
 
 func TestCreateAuthorFailedBirthdateFormat(t *testing.T) {
@@ -249,8 +216,7 @@ func TestCreateAuthorFailedBirthdateFormat(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -271,7 +237,7 @@ func TestCreateAuthorFailedBirthdateFormat(t *testing.T) {
 	}`
 
 	recorderCreateAuthor := RequestCreateAuthor(r, reqBody, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateAuthor.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateAuthor.Code)
 
 	response = recorderCreateAuthor.Result()
 
@@ -280,8 +246,9 @@ func TestCreateAuthorFailedBirthdateFormat(t *testing.T) {
 	var responseAuthor map[string]interface{}
 	json.Unmarshal(body, &responseAuthor)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid", responseAuthor["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseAuthor["error"])
+	assert.Equal(t, map[string]string{"birth_date": "pastdate"}, DetailCodes(responseAuthor))
 }
 
 func TestGetAllUnauthenticated(t *testing.T) {
@@ -300,7 +267,7 @@ func TestGetAllUnauthenticated(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestGetAllAuthorSuccess(t *testing.T) {
@@ -311,8 +278,7 @@ func TestGetAllAuthorSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -358,8 +324,7 @@ func TestGetAllAuthorFailedPage(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -396,7 +361,7 @@ func TestGetAllAuthorFailedPage(t *testing.T) {
 
 	log.Println("responseAuthor : ", responseAuthor)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : page sudah melebihi total page", responseAuthor["error"])
 }
 
@@ -416,7 +381,7 @@ func TestFindByIdUnauthenticated(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestFindByIdInvalidId(t *testing.T) {
@@ -427,8 +392,7 @@ func TestFindByIdInvalidId(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -461,7 +425,7 @@ func TestFindByIdInvalidId(t *testing.T) {
 
 	log.Println("responseBody : ", responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak valid", responseBody["error"])
 }
 
@@ -473,8 +437,7 @@ func TestFindByIdAuthorNotFound(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -505,8 +468,8 @@ func TestFindByIdAuthorNotFound(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : record not found", responseBody["error"])
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "error : author tidak ditemukan", responseBody["error"])
 }
 
 func TestDeleteByIdInvalidId(t *testing.T) {
@@ -517,8 +480,7 @@ func TestDeleteByIdInvalidId(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -551,7 +513,7 @@ func TestDeleteByIdInvalidId(t *testing.T) {
 
 	log.Println("responseBody : ", responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak valid", responseBody["error"])
 }
 
@@ -571,7 +533,7 @@ func TestDeleteByIdUnauthenticated(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestDeleteByIdAuthorNotFound(t *testing.T) {
@@ -582,8 +544,7 @@ func TestDeleteByIdAuthorNotFound(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -614,7 +575,7 @@ func TestDeleteByIdAuthorNotFound(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
 	assert.Equal(t, "error : gagal menghapus data author, author tidak ditemukan", responseBody["error"])
 }
 
@@ -634,7 +595,7 @@ func TestUpdateUnauthenticated(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestUpdateByIdInvalidId(t *testing.T) {
@@ -645,8 +606,7 @@ func TestUpdateByIdInvalidId(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -683,7 +643,7 @@ func TestUpdateByIdInvalidId(t *testing.T) {
 
 	log.Println("responseBody : ", responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak valid", responseBody["error"])
 }
 
@@ -695,8 +655,7 @@ func TestUpdateByIdAuthorNotFound(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -731,8 +690,8 @@ func TestUpdateByIdAuthorNotFound(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : gagal mengupdate data author : record not found", responseBody["error"])
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "error : gagal mengupdate data author, author tidak ditemukan", responseBody["error"])
 }
 
 func TestUpdateByIdNameBirthdateEmpty(t *testing.T) {
@@ -743,8 +702,7 @@ func TestUpdateByIdNameBirthdateEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -779,7 +737,7 @@ func TestUpdateByIdNameBirthdateEmpty(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : field name dan birthdate tidak boleh kosong", responseBody["error"])
 }
 
@@ -791,8 +749,7 @@ func TestUpdateByIdNameLength(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -827,8 +784,8 @@ func TestUpdateByIdNameLength(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : harap masukan nama minimal 3 karakter", responseBody["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, map[string]string{"name": "min"}, DetailCodes(responseBody))
 }
 
 func TestUpdateByIdSuccess(t *testing.T) {
@@ -839,8 +796,7 @@ func TestUpdateByIdSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 
@@ -905,8 +861,7 @@ func TestUpdateByIdBirthdateFormatFailed(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBody)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBody)
 
 	recorderLogin := RequestLoginUser(r, reqBody)
 	assert.Equal(t, http.StatusOK, recorderLogin.Code)
@@ -958,6 +913,172 @@ func TestUpdateByIdBirthdateFormatFailed(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid", responseBody["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, map[string]string{"birth_date": "pastdate"}, DetailCodes(responseBody))
+}
+
+func TestPatchAuthor(t *testing.T) {
+	r := SetupRouterAuthor()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder, responseBody := RequestMergePatch(r, "/authors/1", `{"birth_date": "1967-10-25"}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Berhasil mengupdate data author", responseBody["message"])
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["id"])
+	assert.Equal(t, "Andrea Hirata", data["name"])
+	assert.Contains(t, data["birth_date"], "1967-10-25")
+
+	recorder, responseBody = RequestMergePatch(r, "/authors/1", `{"name": null, "birth_date": "2999-01-01"}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"name": "required", "birth_date": "pastdate"}, DetailCodes(responseBody))
+}
+
+func TestAuthorConditionalRequests(t *testing.T) {
+	r := SetupRouterAuthor()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder, _ := RequestConditional(r, http.MethodGet, "/authors/1", "", token, "If-None-Match", `"1"`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"1-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+	tag := recorder.Header().Get("ETag")
+
+	recorder, _ = RequestConditional(r, http.MethodGet, "/authors/1", "", token, "If-None-Match", tag)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+
+	recorder, responseBody := RequestConditional(r, http.MethodPut, "/authors/1", `{"name": "Andrea Hirata Seman Said Harun"}`, token, "If-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"2-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["version"])
+
+	recorder, _ = RequestConditional(r, http.MethodPatch, "/authors/1", `{"name": "Andrea Hirata"}`, token, "If-Match", tag)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = RequestConditional(r, http.MethodDelete, "/authors/1", "", token, "If-Match", tag)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = RequestConditional(r, http.MethodDelete, "/authors/1", "", token, "If-Match", `"2"`)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = RequestConditional(r, http.MethodGet, "/authors/1", "", token, "If-None-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"2-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+}
+
+func TestDeleteAuthorRestrict(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Pramoedya Ananta Toer", "birth_date": "1925-02-06"}`, token)
+	RequestCreateAuthor(r, `{"name": "Max Lane", "birth_date": "1951-01-01"}`, token)
+	RequestCreateBook(r, `{"title": "Bumi Manusia", "isbn": "9789799731234", "author_id": 1}`, token)
+	RequestCreateBook(r, `{"title": "This Earth of Mankind", "isbn": "9780140256352", "contributors": [{"author_id": 1}, {"author_id": 2, "role": "translator"}]}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/authors/2", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "conflict", responseBody["code"])
+
+	books := responseBody["data"].([]interface{})
+	assert.Len(t, books, 1)
+	assert.Equal(t, "This Earth of Mankind", books[0].(map[string]interface{})["title"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=restrict", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Len(t, responseBody["data"], 2)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=orphan", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"strategy": "oneof"}, DetailCodes(responseBody))
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/authors/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestDeleteAuthorCascade(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Pramoedya Ananta Toer", "birth_date": "1925-02-06"}`, token)
+	RequestCreateAuthor(r, `{"name": "Max Lane", "birth_date": "1951-01-01"}`, token)
+	RequestCreateBook(r, `{"title": "Bumi Manusia", "isbn": "9789799731234", "author_id": 1}`, token)
+	RequestCreateBook(r, `{"title": "This Earth of Mankind", "isbn": "9780140256352", "contributors": [{"author_id": 1}, {"author_id": 2, "role": "translator"}]}`, token)
+	RequestCreateBook(r, `{"title": "Indonesia from Suharto", "isbn": "0306406152", "author_id": 2}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/authors/2?strategy=cascade", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	deletion := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "cascade", deletion["strategy"])
+	assert.Equal(t, "Max Lane", deletion["author"].(map[string]interface{})["name"])
+	assert.Len(t, deletion["deleted_books"], 2)
+	assert.Len(t, deletion["reassigned_books"], 0)
+	assert.Nil(t, deletion["reassigned_to"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, url := range []string{"/books/2", "/books/3", "/authors/2"} {
+		recorder, _ = RequestBookCopy(r, http.MethodGet, url, "", token)
+		assert.Equal(t, http.StatusNotFound, recorder.Code, url)
+	}
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"], 3)
+}
+
+func TestDeleteAuthorReassign(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata Seman Said Harun", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	RequestCreateBook(r, `{"title": "Sang Pemimpi", "isbn": "0306406152", "contributors": [{"author_id": 2}, {"author_id": 1}]}`, token)
+	RequestCreateBook(r, `{"title": "Edensor", "isbn": "9780140256352", "contributors": [{"author_id": 2}, {"author_id": 1, "role": "editor"}]}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=reassign", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"to": "invalid"}, DetailCodes(responseBody))
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=reassign&to=9", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : author tujuan tidak ditemukan", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/authors/1?strategy=reassign&to=2", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	deletion := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "reassign", deletion["strategy"])
+	assert.Len(t, deletion["deleted_books"], 0)
+	assert.Len(t, deletion["reassigned_books"], 3)
+	assert.Equal(t, float64(2), deletion["reassigned_to"].(map[string]interface{})["id"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	book := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), book["author"].(map[string]interface{})["id"])
+	assert.Equal(t, float64(2), book["version"])
+
+	// the author was already listed on the book with the same role
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/2", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].(map[string]interface{})["contributors"], 1)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/3", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	contributors := responseBody["data"].(map[string]interface{})["contributors"].([]interface{})
+	assert.Len(t, contributors, 2)
+	assert.Equal(t, float64(2), contributors[1].(map[string]interface{})["author_id"])
+	assert.Equal(t, "editor", contributors[1].(map[string]interface{})["role"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/authors/1", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
 }
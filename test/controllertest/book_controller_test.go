@@ -9,65 +9,25 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ilhaamms/library-api/config"
-	"github.com/ilhaamms/library-api/controller"
-	"github.com/ilhaamms/library-api/middleware"
-	"github.com/ilhaamms/library-api/repository"
-	"github.com/ilhaamms/library-api/service"
+	libdb "github.com/ilhaamms/library-api/db"
+	"github.com/ilhaamms/library-api/test/testdb"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
 func TruncateTableBook(db *gorm.DB) {
-	db.Exec("DELETE FROM book")
-
-	db.Exec("DELETE FROM sqlite_sequence WHERE name='book'")
+	testdb.Truncate(db, "book", "work")
 }
 
 func SetupRouterBook() *gin.Engine {
 
-	gin.SetMode(gin.TestMode)
-
-	db, err := config.InitDbSQLite()
-	if err != nil {
-		panic(err)
-	}
+	db := OpenDB()
 
 	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+	testdb.Truncate(db, "audit_log")
 
-	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userController := controller.NewUserController(userService)
-
-	authorRepo := repository.NewAuthorRepository(db)
-	authorService := service.NewAuthorService(authorRepo)
-	authorController := controller.NewAuthorController(authorService)
-
-	bookRepo := repository.NewBookRepository(db)
-	bookService := service.NewBookService(bookRepo)
-	bookController := controller.NewBookController(bookService)
-
-	r := gin.Default()
-
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", userController.Register)
-		auth.POST("/login", userController.Login)
-	}
-
-	r.POST("/authors", middleware.Auth(), authorController.CreateAuthor)
-	r.GET("/authors", middleware.Auth(), authorController.GetAllAuthor)
-	r.GET("/authors/:id", middleware.Auth(), authorController.GetAuthorsById)
-	r.DELETE("/authors/:id", middleware.Auth(), authorController.DeleteAuthorsById)
-	r.PUT("/authors/:id", middleware.Auth(), authorController.UpdateAuthorsById)
-
-	r.POST("/books", middleware.Auth(), bookController.CreateBook)
-	r.GET("/books", middleware.Auth(), bookController.GetAllBook)
-	r.GET("/books/:id", middleware.Auth(), bookController.GetBookById)
-	r.DELETE("/books/:id", middleware.Auth(), bookController.DeleteBookById)
-	r.PUT("/books/:id", middleware.Auth(), bookController.Update)
-
-	return r
+	return NewRouter(db)
 }
 
 func RequestCreateBook(r *gin.Engine, reqBody string, token string) *httptest.ResponseRecorder {
@@ -87,7 +47,7 @@ func TestSaveBookUnAuthorized(t *testing.T) {
 
 	reqBody := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -101,7 +61,7 @@ func TestSaveBookUnAuthorized(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
-	assert.Equal(t, "invalid token", responseBody["message"])
+	assert.Equal(t, "error : token tidak valid", responseBody["error"])
 }
 
 func TestSaveBookSuccess(t *testing.T) {
@@ -112,8 +72,7 @@ func TestSaveBookSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -142,7 +101,7 @@ func TestSaveBookSuccess(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -168,8 +127,7 @@ func TestSaveBookFailedTitleEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -197,12 +155,12 @@ func TestSaveBookFailedTitleEmpty(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -211,8 +169,9 @@ func TestSaveBookFailedTitleEmpty(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"title": "required"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveBookFailedIsbnEmpty(t *testing.T) {
@@ -223,8 +182,7 @@ func TestSaveBookFailedIsbnEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -258,7 +216,7 @@ func TestSaveBookFailedIsbnEmpty(t *testing.T) {
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -267,8 +225,9 @@ func TestSaveBookFailedIsbnEmpty(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"isbn": "required"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveBookFailedAuthorIdEmpty(t *testing.T) {
@@ -279,8 +238,7 @@ func TestSaveBookFailedAuthorIdEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -309,12 +267,12 @@ func TestSaveBookFailedAuthorIdEmpty(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 0
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -323,8 +281,9 @@ func TestSaveBookFailedAuthorIdEmpty(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"author_id": "required_without"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveMinTitleBook(t *testing.T) {
@@ -335,8 +294,7 @@ func TestSaveMinTitleBook(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -365,12 +323,12 @@ func TestSaveMinTitleBook(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Go",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -379,8 +337,9 @@ func TestSaveMinTitleBook(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : judul minimal 3 karakter", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"title": "min"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveMinIsbnBook(t *testing.T) {
@@ -391,8 +350,7 @@ func TestSaveMinIsbnBook(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -426,7 +384,7 @@ func TestSaveMinIsbnBook(t *testing.T) {
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -435,8 +393,9 @@ func TestSaveMinIsbnBook(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : isbn minimal 10 karakter", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"isbn": "isbn"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveMaxIsbnBook(t *testing.T) {
@@ -447,8 +406,7 @@ func TestSaveMaxIsbnBook(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -481,7 +439,7 @@ func TestSaveMaxIsbnBook(t *testing.T) {
 		"author_id": 1
 	}`
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -490,8 +448,9 @@ func TestSaveMaxIsbnBook(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : isbn maksimal 13 karakter", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"isbn": "isbn"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveAuthorIdNegativeBook(t *testing.T) {
@@ -503,8 +462,7 @@ func TestSaveAuthorIdNegativeBook(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -533,12 +491,12 @@ func TestSaveAuthorIdNegativeBook(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": -1
 	}`
 
 	recorderCreateBook := RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -547,8 +505,9 @@ func TestSaveAuthorIdNegativeBook(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
-	assert.Equal(t, "error : author_id tidak boleh negatif", responseBodyCreateBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, responseCreateBook.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyCreateBook["error"])
+	assert.Equal(t, map[string]string{"author_id": "gte"}, DetailCodes(responseBodyCreateBook))
 }
 
 func TestSaveBookIsbnExist(t *testing.T) {
@@ -559,8 +518,7 @@ func TestSaveBookIsbnExist(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -589,7 +547,7 @@ func TestSaveBookIsbnExist(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -598,12 +556,12 @@ func TestSaveBookIsbnExist(t *testing.T) {
 
 	reqCreateBook = `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
 	recorderCreateBook = RequestCreateBook(r, reqCreateBook, token)
-	assert.Equal(t, http.StatusBadRequest, recorderCreateBook.Code)
+	assert.Equal(t, http.StatusConflict, recorderCreateBook.Code)
 
 	responseCreateBook := recorderCreateBook.Result()
 
@@ -612,7 +570,7 @@ func TestSaveBookIsbnExist(t *testing.T) {
 	var responseBodyCreateBook map[string]interface{}
 	json.Unmarshal(bodyCreateBook, &responseBodyCreateBook)
 
-	assert.Equal(t, http.StatusBadRequest, responseCreateBook.StatusCode)
+	assert.Equal(t, http.StatusConflict, responseCreateBook.StatusCode)
 	assert.Equal(t, "error : isbn sudah digunakan oleh buku lain", responseBodyCreateBook["error"])
 }
 
@@ -632,7 +590,7 @@ func TestGetAllBookUnAuthorized(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestSuccessGetAllBook(t *testing.T) {
@@ -643,8 +601,7 @@ func TestSuccessGetAllBook(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -673,7 +630,7 @@ func TestSuccessGetAllBook(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -705,8 +662,7 @@ func TestGetAllBookEmptyData(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -750,8 +706,7 @@ func TestGetAllBookPageMorethanTotalPagge(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -780,7 +735,7 @@ func TestGetAllBookPageMorethanTotalPagge(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -800,7 +755,7 @@ func TestGetAllBookPageMorethanTotalPagge(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : page sudah melebihi total page", responseBodyGetAllBook["error"])
 }
 
@@ -820,7 +775,7 @@ func TestFindByIdBookUnauthorized(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestFindByBookSuccess(t *testing.T) {
@@ -831,8 +786,7 @@ func TestFindByBookSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -861,7 +815,7 @@ func TestFindByBookSuccess(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -893,8 +847,7 @@ func TestFindByIdBookNegative(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -923,7 +876,7 @@ func TestFindByIdBookNegative(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -943,7 +896,7 @@ func TestFindByIdBookNegative(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak boleh negatif atau 0", responseBodyGetAllBook["error"])
 }
 
@@ -955,8 +908,7 @@ func TestFindByIdBookNotFound(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -985,7 +937,7 @@ func TestFindByIdBookNotFound(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -1005,7 +957,7 @@ func TestFindByIdBookNotFound(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
 	assert.Equal(t, "error : book tidak ditemukan", responseBodyGetAllBook["error"])
 }
 
@@ -1025,7 +977,7 @@ func TestDeleteBookUnAuthorized(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestDeleteBookSuccess(t *testing.T) {
@@ -1036,8 +988,7 @@ func TestDeleteBookSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1066,7 +1017,7 @@ func TestDeleteBookSuccess(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -1098,8 +1049,7 @@ func TestDeleteBookNegativeId(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1131,7 +1081,7 @@ func TestDeleteBookNegativeId(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak boleh negatif atau 0", responseBodyGetAllBook["error"])
 }
 
@@ -1143,8 +1093,7 @@ func TestDeleteBookNotfoundId(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1176,7 +1125,7 @@ func TestDeleteBookNotfoundId(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
 	assert.Equal(t, "error : gagal menghapus data book, book tidak ditemukan", responseBodyGetAllBook["error"])
 }
 
@@ -1196,7 +1145,7 @@ func TestUpdateBookUnauthorized(t *testing.T) {
 	json.Unmarshal(body, &responseBody)
 
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
-	assert.Equal(t, "authorization required", responseBody["message"])
+	assert.Equal(t, "error : header Authorization wajib diisi", responseBody["error"])
 }
 
 func TestUpdateBookSuccess(t *testing.T) {
@@ -1207,8 +1156,7 @@ func TestUpdateBookSuccess(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1237,7 +1185,7 @@ func TestUpdateBookSuccess(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -1246,7 +1194,7 @@ func TestUpdateBookSuccess(t *testing.T) {
 
 	reqUpdateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "12345678910",
+		"isbn": "9780262033848",
 		"author_id": 1
 	}`
 
@@ -1276,8 +1224,7 @@ func TestUpdateBookFailedIdNegative(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1306,7 +1253,7 @@ func TestUpdateBookFailedIdNegative(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -1315,7 +1262,7 @@ func TestUpdateBookFailedIdNegative(t *testing.T) {
 
 	reqUpdateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "12345678910",
+		"isbn": "9780262033848",
 		"author_id": 1
 	}`
 
@@ -1333,7 +1280,7 @@ func TestUpdateBookFailedIdNegative(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "error : id tidak boleh negatif atau 0", responseBodyGetAllBook["error"])
 }
 
@@ -1345,8 +1292,7 @@ func TestUpdateBookIdNotFound(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1375,7 +1321,7 @@ func TestUpdateBookIdNotFound(t *testing.T) {
 
 	reqCreateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "1234567890",
+		"isbn": "0306406152",
 		"author_id": 1
 	}`
 
@@ -1384,7 +1330,7 @@ func TestUpdateBookIdNotFound(t *testing.T) {
 
 	reqUpdateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "12345678910",
+		"isbn": "9780262033848",
 		"author_id": 1
 	}`
 
@@ -1402,7 +1348,7 @@ func TestUpdateBookIdNotFound(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
 	assert.Equal(t, "error : gagal mengupdate data book, book tidak ditemukan", responseBodyGetAllBook["error"])
 }
 
@@ -1414,8 +1360,7 @@ func TestUpdateBookFailedTitleEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1435,7 +1380,7 @@ func TestUpdateBookFailedTitleEmpty(t *testing.T) {
 	token := responseBody["data"].(map[string]interface{})["token"].(string)
 	reqUpdateBook := `{
 		"title": "",
-		"isbn": "12345678910",
+		"isbn": "9780262033848",
 		"author_id": 1
 	}`
 
@@ -1453,8 +1398,9 @@ func TestUpdateBookFailedTitleEmpty(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyGetAllBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyGetAllBook["error"])
+	assert.Equal(t, map[string]string{"title": "required"}, DetailCodes(responseBodyGetAllBook))
 }
 
 func TestUpdateBookFailedIsbnEmpty(t *testing.T) {
@@ -1464,8 +1410,7 @@ func TestUpdateBookFailedIsbnEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1504,8 +1449,9 @@ func TestUpdateBookFailedIsbnEmpty(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyGetAllBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyGetAllBook["error"])
+	assert.Equal(t, map[string]string{"isbn": "required"}, DetailCodes(responseBodyGetAllBook))
 }
 
 func TestUpdateBookFailedAuthorIdEmpty(t *testing.T) {
@@ -1515,8 +1461,7 @@ func TestUpdateBookFailedAuthorIdEmpty(t *testing.T) {
 		"password": "ilhamsidiq"
 	}`
 
-	recorderRegister := RequestRegisterUser(r, reqBodyRegister)
-	assert.Equal(t, http.StatusCreated, recorderRegister.Code)
+	RegisterAdmin(t, reqBodyRegister)
 
 	reqBodyLogin := `{
 		"username": "ilhamm.ms",
@@ -1535,7 +1480,7 @@ func TestUpdateBookFailedAuthorIdEmpty(t *testing.T) {
 	token := responseBody["data"].(map[string]interface{})["token"].(string)
 	reqUpdateBook := `{
 		"title": "Belajar Golang",
-		"isbn": "12345678910",
+		"isbn": "9780262033848",
 		"author_id": 0
 	}`
 
@@ -1551,6 +1496,437 @@ func TestUpdateBookFailedAuthorIdEmpty(t *testing.T) {
 	var responseBodyGetAllBook map[string]interface{}
 	json.Unmarshal(body, &responseBodyGetAllBook)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
-	assert.Equal(t, "error : judul, isbn, dan author_id tidak boleh kosong", responseBodyGetAllBook["error"])
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBodyGetAllBook["error"])
+	assert.Equal(t, map[string]string{"author_id": "required_without"}, DetailCodes(responseBodyGetAllBook))
+}
+
+func TestSaveBookWithContributors(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	recorder := RequestCreateAuthor(r, `{"name": "Pramoedya Ananta Toer", "birth_date": "1925-02-06"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = RequestCreateAuthor(r, `{"name": "Max Lane", "birth_date": "1951-01-01"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = RequestCreateBook(r, `{
+		"title": "This Earth of Mankind",
+		"isbn": "9780140256352",
+		"contributors": [
+			{"author_id": 1, "role": "author"},
+			{"author_id": 2, "role": "translator"}
+		]
+	}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	book := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Pramoedya Ananta Toer", book["author"].(map[string]interface{})["name"])
+
+	contributors := book["contributors"].([]interface{})
+	assert.Len(t, contributors, 2)
+	assert.Equal(t, "Pramoedya Ananta Toer", contributors[0].(map[string]interface{})["name"])
+	assert.Equal(t, "author", contributors[0].(map[string]interface{})["role"])
+	assert.Equal(t, float64(1), contributors[0].(map[string]interface{})["position"])
+	assert.Equal(t, "Max Lane", contributors[1].(map[string]interface{})["name"])
+	assert.Equal(t, "translator", contributors[1].(map[string]interface{})["role"])
+	assert.Equal(t, float64(2), contributors[1].(map[string]interface{})["position"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?author_id=2", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].([]interface{}), 1)
+}
+
+func TestSaveBookWithAuthorIdOnly(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	recorder := RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	contributors := responseBody["data"].(map[string]interface{})["contributors"].([]interface{})
+	assert.Len(t, contributors, 1)
+	assert.Equal(t, "author", contributors[0].(map[string]interface{})["role"])
+}
+
+func TestUpdateBookContributors(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateAuthor(r, `{"name": "Angie Kilbane", "birth_date": "1970-01-01"}`, token)
+
+	recorder := RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPut, "/books/1", `{
+		"title": "The Rainbow Troops",
+		"isbn": "9780374246211",
+		"contributors": [
+			{"author_id": 2, "role": "editor"},
+			{"author_id": 1}
+		]
+	}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	book := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Angie Kilbane", book["author"].(map[string]interface{})["name"])
+
+	contributors := book["contributors"].([]interface{})
+	assert.Len(t, contributors, 2)
+	assert.Equal(t, "editor", contributors[0].(map[string]interface{})["role"])
+	assert.Equal(t, "author", contributors[1].(map[string]interface{})["role"])
+}
+
+func TestSaveBookFailedContributorRole(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books", `{
+		"title": "Laskar Pelangi",
+		"isbn": "9789793062792",
+		"contributors": [{"author_id": 1, "role": "publisher"}]
+	}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"contributors[0].role": "contributor_role"}, DetailCodes(responseBody))
+}
+
+func TestSaveBookNormalizesIsbn(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Gamma", "birth_date": "1961-01-01"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Design Patterns", "isbn": "0-201-63361-2", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "9780201633610", responseBody["data"].(map[string]interface{})["isbn"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Design Patterns", "isbn": "978-0-201-63361-0", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : isbn sudah digunakan oleh buku lain", responseBody["error"])
+
+	for _, value := range []string{"0201633612", "978-0201633610"} {
+		recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?isbn="+value, "", token)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, responseBody["data"].([]interface{}), 1)
+	}
+}
+
+func TestSaveBookFailedIsbnChecksum(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Gamma", "birth_date": "1961-01-01"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Design Patterns", "isbn": "0-201-63361-3", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"isbn": "isbn"}, DetailCodes(responseBody))
+}
+
+func TestValidateIsbn(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/isbn/0-201-63361-2/validate", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, true, data["valid"])
+	assert.Equal(t, "9780201633610", data["isbn13"])
+	assert.Equal(t, "0201633612", data["isbn10"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/isbn/ABCDEFGHIJ/validate", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	data = responseBody["data"].(map[string]interface{})
+	assert.Equal(t, false, data["valid"])
+	assert.Equal(t, "isbn hanya boleh berisi angka, dengan X sebagai check digit ISBN-10", data["error"])
+}
+
+func TestBookErrorCodes(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/abc", "", token)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "bad_request", responseBody["code"])
+	assert.Equal(t, "id", responseBody["details"].([]interface{})[0].(map[string]interface{})["field"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/99", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "not_found", responseBody["code"])
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Laskar Pelangi", "isbn": "978-979-3062-79-2", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "conflict", responseBody["code"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Sang Pemimpi", "isbn": "9789793062793", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "validation_failed", responseBody["code"])
+	assert.Equal(t, "isbn", responseBody["details"].([]interface{})[0].(map[string]interface{})["field"])
+}
+
+func TestBookMessagesLocalized(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books?lang=en", `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "en", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "Book saved", responseBody["message"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books?lang=en", `{"title": "Sang Pemimpi", "isbn": "9789793062793", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : the request has invalid fields, see details", responseBody["error"])
+	assert.Equal(t, "isbn check digit is not valid", responseBody["details"].([]interface{})[0].(map[string]interface{})["message"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/abc?lang=en", "", token)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "error : id must be a number", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?lang=en&sort=pages", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, responseBody["error"], "error : sort must be one of : ")
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/isbn/ABCDEFGHIJ/validate?lang=en", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ISBN is not valid", responseBody["message"])
+	assert.Equal(t, "isbn may only contain digits, with X as the ISBN-10 check digit", responseBody["data"].(map[string]interface{})["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/99", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "id", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "error : book tidak ditemukan", responseBody["error"])
+}
+
+func TestSaveBookReportsAllFieldErrors(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books", `{
+		"title": "Go",
+		"isbn": "0-201-63361-3",
+		"author_id": -1,
+		"category_ids": [1, 1],
+		"language": "indonesia"
+	}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "validation_failed", responseBody["code"])
+	assert.Equal(t, map[string]string{"title": "min", "isbn": "isbn", "author_id": "gte", "category_ids": "unique", "language": "language"}, DetailCodes(responseBody))
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books?lang=en", `{"title": "Go", "isbn": "0-201-63361-3", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"field": "title", "code": "min", "message": "title must be at least 3 characters"},
+		map[string]interface{}{"field": "isbn", "code": "isbn", "message": "isbn check digit is not valid"},
+	}, responseBody["details"])
+}
+
+func RequestMergePatch(r *gin.Engine, url, reqBody, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	body, _ := io.ReadAll(recorder.Result().Body)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	return recorder, responseBody
+}
+
+func TestUpdateBookKeepsOwnIsbn(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPut, "/books/1", `{"title": "The Rainbow Troops", "isbn": "979-3062-79-7", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestPatchBook(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateAuthor(r, `{"name": "Angie Kilbane", "birth_date": "1970-01-01"}`, token)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/books", `{
+		"title": "Laskar Pelangi",
+		"isbn": "9789793062792",
+		"contributors": [{"author_id": 1}, {"author_id": 2, "role": "editor"}],
+		"publication_year": 2005,
+		"page_count": 529,
+		"description": "Novel pertama tetralogi Laskar Pelangi"
+	}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestMergePatch(r, "/books/1", `{"title": "The Rainbow Troops", "page_count": null, "description": null}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data buku berhasil diupdate", responseBody["message"])
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "The Rainbow Troops", data["title"])
+	assert.Equal(t, "9789793062792", data["isbn"])
+	assert.Equal(t, float64(2005), data["publication_year"])
+	assert.Nil(t, data["page_count"])
+	assert.Equal(t, "", data["description"])
+	assert.Len(t, data["contributors"], 2)
+
+	recorder, responseBody = RequestMergePatch(r, "/books/1", `{"title": null}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"title": "required"}, DetailCodes(responseBody))
+
+	recorder, _ = RequestMergePatch(r, "/books/99", `{"title": "Sang Pemimpi"}`, token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder, responseBody = RequestMergePatch(r, "/books/1", `["title"]`, token)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "error : body harus berupa JSON merge patch berbentuk object", responseBody["error"])
+
+	req := httptest.NewRequest(http.MethodPatch, "/books/1", strings.NewReader(`title=Sang Pemimpi`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+}
+
+func TestPatchBookIsbnConflict(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	RequestCreateBook(r, `{"title": "Sang Pemimpi", "isbn": "0306406152", "author_id": 1}`, token)
+
+	recorder, _ := RequestMergePatch(r, "/books/2", `{"isbn": "978-979-3062-79-2"}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func RequestConditional(r *gin.Engine, method, url, reqBody, token, header, etag string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, url, strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(header, etag)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	body, _ := io.ReadAll(recorder.Result().Body)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	return recorder, responseBody
+}
+
+func TestBookConditionalRequests(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"1-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["version"])
+	tag := recorder.Header().Get("ETag")
+
+	recorder, _ = RequestConditional(r, http.MethodGet, "/books/1", "", token, "If-None-Match", tag)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	recorder, _ = RequestConditional(r, http.MethodGet, "/books/1", "", token, "If-None-Match", `"1"`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	reqBody := `{"title": "The Rainbow Troops", "isbn": "9789793062792", "author_id": 1}`
+
+	// a write answers with the tag of the book it sends back
+	recorder, responseBody = RequestConditional(r, http.MethodPut, "/books/1", reqBody, token, "If-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"2-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["version"])
+	assert.Equal(t, "The Rainbow Troops", responseBody["data"].(map[string]interface{})["title"])
+	updated := recorder.Header().Get("ETag")
+
+	recorder, _ = RequestConditional(r, http.MethodGet, "/books/1", "", token, "If-None-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, updated, recorder.Header().Get("ETag"))
+
+	recorder, responseBody = RequestConditional(r, http.MethodPut, "/books/1", reqBody, token, "If-Match", tag)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+	assert.Equal(t, "precondition_failed", responseBody["code"])
+	assert.Equal(t, "error : data sudah diubah sejak terakhir diambil, ambil ulang data lalu coba lagi", responseBody["error"])
+
+	recorder, _ = RequestConditional(r, http.MethodPatch, "/books/1", `{"title": "Laskar Pelangi"}`, token, "If-Match", tag)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = RequestConditional(r, http.MethodPatch, "/books/1", `{"title": "Laskar Pelangi"}`, token, "If-Match", updated)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `^"3-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+	patched := recorder.Header().Get("ETag")
+
+	// only tags handed out by the API match, whatever their version
+	for _, header := range []string{`W/` + patched, `"3"`, `"3-whatever"`, `"3-` + strings.Repeat("0", 15) + `"`, updated} {
+		recorder, _ = RequestConditional(r, http.MethodDelete, "/books/1", "", token, "If-Match", header)
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code, header)
+	}
+
+	recorder, _ = RequestConditional(r, http.MethodDelete, "/books/1", "", token, "If-Match", patched)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestConditional(r, http.MethodPut, "/books/1", reqBody, token, "If-Match", patched)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSearchEscapesHighlight(t *testing.T) {
+	available, err := libdb.FTS5Available(OpenDB())
+	assert.Nil(t, err)
+
+	if !available {
+		t.Skip("search needs FTS5, run the tests with -tags sqlite_fts5")
+	}
+
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Ilham Sidiq", "birth_date": "1996-01-01"}`, token)
+	recorder := RequestCreateBook(r, `{"title": "Golang <script>alert(1)</script>", "isbn": "9780306406157", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/search?q=golang", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	highlight := responseBody["data"].([]interface{})[0].(map[string]interface{})["highlight"].(map[string]interface{})
+	assert.Equal(t, "<mark>Golang</mark> &lt;script&gt;alert(1)&lt;/script&gt;", highlight["title"])
 }
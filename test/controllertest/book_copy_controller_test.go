@@ -0,0 +1,178 @@
+package controllertest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TruncateTableBookCopy(db *gorm.DB) {
+	testdb.Truncate(db, "book_copy")
+}
+
+func SetupRouterBookCopy() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateTableBookCopy(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db)
+}
+
+func PrepareBookCopy(t *testing.T, r *gin.Engine) string {
+	reqBody := `{
+		"username": "ilhamm.ms",
+		"password": "ilhamsidiq"
+	}`
+
+	RegisterAdmin(t, reqBody)
+
+	recorderLogin := RequestLoginUser(r, reqBody)
+	assert.Equal(t, http.StatusOK, recorderLogin.Code)
+
+	body, _ := io.ReadAll(recorderLogin.Result().Body)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	token := responseBody["data"].(map[string]interface{})["token"].(string)
+
+	recorderCreateAuthor := RequestCreateAuthor(r, `{"name": "Ilham Sidiq", "birth_date": "1996-01-01"}`, token)
+	assert.Equal(t, http.StatusCreated, recorderCreateAuthor.Code)
+
+	recorderCreateBook := RequestCreateBook(r, `{"title": "Belajar Golang", "isbn": "0306406152", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorderCreateBook.Code)
+
+	return token
+}
+
+func RequestBookCopy(r *gin.Engine, method, url, reqBody, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, url, strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	body, _ := io.ReadAll(recorder.Result().Body)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	return recorder, responseBody
+}
+
+// DetailCodes returns the code of every field in the details of an error
+// response by field name.
+func DetailCodes(responseBody map[string]interface{}) map[string]string {
+	codes := map[string]string{}
+
+	details, _ := responseBody["details"].([]interface{})
+	for _, detail := range details {
+		field := detail.(map[string]interface{})
+		codes[field["field"].(string)] = field["code"].(string)
+	}
+
+	return codes
+}
+
+func TestCreateBookCopySuccess(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{
+		"barcode": "LIB-0001",
+		"branch": "Pusat",
+		"shelf": "A-01",
+		"acquisition_date": "2024-01-15"
+	}`, token)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "Berhasil menyimpan data eksemplar", responseBody["message"])
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "LIB-0001", data["barcode"])
+	assert.Equal(t, "available", data["status"])
+	assert.Equal(t, "good", data["condition"])
+}
+
+func TestCreateBookCopyFailedBookNotFound(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/99/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : book tidak ditemukan", responseBody["error"])
+}
+
+func TestCreateBookCopyFailedBarcodeExist(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Cabang"}`, token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : barcode sudah digunakan oleh eksemplar lain", responseBody["error"])
+}
+
+func TestGetBookReportsCopyCount(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+	RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0002", "branch": "Pusat", "status": "in_repair"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["total_copies"])
+	assert.Equal(t, float64(1), data["available_copies"])
+}
+
+func TestUpdateBookCopySuccess(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, "/books/1/copies/1", `{"status": "lost"}`, token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "lost", responseBody["data"].(map[string]interface{})["status"])
+}
+
+func TestDeleteBookCopyNotFound(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/books/1/copies/5", "", token)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : gagal menghapus data eksemplar, eksemplar tidak ditemukan", responseBody["error"])
+}
+
+func TestGetAllBookCopyEmpty(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := PrepareBookCopy(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1/copies", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data eksemplar kosong", responseBody["message"])
+}
@@ -0,0 +1,141 @@
+package controllertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TruncateTableCategory(db *gorm.DB) {
+	db.Exec("UPDATE category SET parent_id = NULL")
+	testdb.Truncate(db, "book_category", "category")
+}
+
+func SetupRouterCategory() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateTableCategory(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db)
+}
+
+// PrepareCategory creates Fiksi > Fiksi Ilmiah > Cyberpunk and Non Fiksi.
+func PrepareCategory(t *testing.T, r *gin.Engine) string {
+	token := LoginAdmin(t, r)
+
+	for _, reqBody := range []string{
+		`{"name": "Fiksi"}`,
+		`{"name": "Fiksi Ilmiah", "parent_id": 1}`,
+		`{"name": "Cyberpunk", "parent_id": 2}`,
+		`{"name": "Non Fiksi"}`,
+	} {
+		recorder, _ := RequestBookCopy(r, http.MethodPost, "/categories", reqBody, token)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	}
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/authors", `{"name": "William Gibson", "birth_date": "1948-03-17"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	return token
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/categories", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	tree := responseBody["data"].([]interface{})
+	assert.Len(t, tree, 2)
+
+	fiction := tree[0].(map[string]interface{})
+	assert.Equal(t, "Fiksi", fiction["name"])
+
+	scifi := fiction["children"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Fiksi Ilmiah", scifi["name"])
+	assert.Equal(t, "Cyberpunk", scifi["children"].([]interface{})[0].(map[string]interface{})["name"])
+}
+
+func TestCreateCategoryFailedNameExist(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/categories", `{"name": "Cyberpunk", "parent_id": 2}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : nama category sudah digunakan pada parent yang sama", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/categories", `{"name": "Cyberpunk", "parent_id": 4}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestGetCategoryBooksIncludesDescendants(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Neuromancer", "isbn": "9780441569595", "author_id": 1, "category_ids": [3]}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Sapiens", "isbn": "9780062316097", "author_id": 1, "category_ids": [4]}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/categories/1/books", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	books := responseBody["data"].([]interface{})
+	assert.Len(t, books, 1)
+
+	book := books[0].(map[string]interface{})
+	assert.Equal(t, "Neuromancer", book["title"])
+	assert.Equal(t, "Cyberpunk", book["categories"].([]interface{})[0].(map[string]interface{})["name"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?category=4", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].([]interface{}), 1)
+	assert.Equal(t, "Sapiens", responseBody["data"].([]interface{})[0].(map[string]interface{})["title"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/categories/2/books", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].([]interface{}), 1)
+}
+
+func TestCreateBookFailedCategoryNotFound(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Neuromancer", "isbn": "9780441569595", "author_id": 1, "category_ids": [99]}`, token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : category tidak ditemukan", responseBody["error"])
+}
+
+func TestUpdateCategoryFailedCycle(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, "/categories/1", `{"parent_id": 3}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : category tidak boleh dipindah ke dalam dirinya sendiri atau sub category-nya", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPut, "/categories/3", `{"parent_id": 0}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["parent_id"])
+}
+
+func TestDeleteCategoryFailedHasChildren(t *testing.T) {
+	r := SetupRouterCategory()
+	token := PrepareCategory(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/categories/2", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : category masih memiliki sub category", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/categories/3", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,137 @@
+package controllertest
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func SetupRouterFine() (*gin.Engine, *gorm.DB) {
+
+	db := OpenDB()
+
+	TruncateTableHold(db)
+	TruncateTableLoan(db)
+	TruncateTableBookCopy(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db), db
+}
+
+// PrepareOverdueReturn borrows copy 1, moves its due date four days into the
+// past and returns it, so a fine is posted for three days after the grace day.
+func PrepareOverdueReturn(t *testing.T, r *gin.Engine, db *gorm.DB, category string) (string, int) {
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	userId := int(responseBody["data"].(map[string]interface{})["user_id"].(float64))
+
+	recorder, _ = RequestBookCopy(r, http.MethodPut, fmt.Sprintf("/users/%d/category", userId), fmt.Sprintf(`{"category": "%s"}`, category), token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	db.Exec("UPDATE loan SET due_date = ? WHERE id = 1", time.Now().AddDate(0, 0, -4))
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	return token, userId
+}
+
+func TestReturnOverduePostsFine(t *testing.T) {
+	r, db := SetupRouterFine()
+	token, _ := PrepareOverdueReturn(t, r, db, "regular")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/fines", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	fines := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(300000), fines["balance"])
+	assert.Len(t, fines["ledger"], 1)
+	assert.Equal(t, "charge", fines["ledger"].([]interface{})[0].(map[string]interface{})["entry_type"])
+}
+
+func TestReturnOverdueUsesCategoryRate(t *testing.T) {
+	r, db := SetupRouterFine()
+	token, _ := PrepareOverdueReturn(t, r, db, "student")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/fines", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(150000), responseBody["data"].(map[string]interface{})["balance"])
+}
+
+func TestUpdateCategoryFailedUnknown(t *testing.T) {
+	r, _ := SetupRouterFine()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, "/users/1/category", `{"category": "vip"}`, token)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : category hanya boleh salah satu dari : regular, student, senior", responseBody["error"])
+}
+
+func TestRecordPaymentAndWaiver(t *testing.T) {
+	r, db := SetupRouterFine()
+	token, userId := PrepareOverdueReturn(t, r, db, "regular")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/fines/payments", fmt.Sprintf(`{"user_id": %d, "amount": 400000}`, userId), token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "error : nominal melebihi sisa denda", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/fines/payments", fmt.Sprintf(`{"user_id": %d, "loan_id": 1, "amount": 200000}`, userId), token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "payment", responseBody["data"].(map[string]interface{})["entry_type"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/fines/waivers", fmt.Sprintf(`{"user_id": %d, "amount": 100000, "note": "pengembalian via dropbox"}`, userId), token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "pengembalian via dropbox", responseBody["data"].(map[string]interface{})["note"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/users/me/fines", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	fines := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(0), fines["balance"])
+	assert.Len(t, fines["ledger"], 3)
+}
+
+func TestConcurrentPaymentsNeverOverdraw(t *testing.T) {
+	r, db := SetupRouterFine()
+	token, userId := PrepareOverdueReturn(t, r, db, "regular")
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	paid := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			recorder, _ := RequestBookCopy(r, http.MethodPost, "/fines/payments", fmt.Sprintf(`{"user_id": %d, "amount": 200000}`, userId), token)
+			if recorder.Code == http.StatusCreated {
+				mu.Lock()
+				paid++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, paid)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/fines", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(100000), responseBody["data"].(map[string]interface{})["balance"])
+}
@@ -0,0 +1,238 @@
+package controllertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TruncateTableHold(db *gorm.DB) {
+	testdb.Truncate(db, "hold")
+}
+
+func SetupRouterHold() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateTableHold(db)
+	TruncateTableLoan(db)
+	TruncateTableBookCopy(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db)
+}
+
+// LoginAnotherUser registers a second account without truncating the user
+// table like RequestRegisterUser does.
+func LoginAnotherUser(t *testing.T, r *gin.Engine, username string) string {
+	reqBody := fmt.Sprintf(`{"username": "%s", "password": "rahasia123"}`, username)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/auth/register", strings.NewReader(reqBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorderLogin := RequestLoginUser(r, reqBody)
+	assert.Equal(t, http.StatusOK, recorderLogin.Code)
+
+	body, _ := io.ReadAll(recorderLogin.Result().Body)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+
+	return responseBody["data"].(map[string]interface{})["token"].(string)
+}
+
+// PrepareHold leaves copy 1 on loan to the first user, with two more users
+// waiting for it in order.
+func PrepareHold(t *testing.T, r *gin.Engine) (string, string, string) {
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	tokenBudi := LoginAnotherUser(t, r, "budiman")
+	tokenCitra := LoginAnotherUser(t, r, "citrawati")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/holds", "", tokenBudi)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["queue_position"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books/1/holds", "", tokenCitra)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, float64(2), responseBody["data"].(map[string]interface{})["queue_position"])
+
+	return token, tokenBudi, tokenCitra
+}
+
+func RequestMyHold(t *testing.T, r *gin.Engine, token string) map[string]interface{} {
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/holds", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	return responseBody["data"].([]interface{})[0].(map[string]interface{})
+}
+
+func TestPlaceHoldFailedCopyAvailable(t *testing.T) {
+	r := SetupRouterHold()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/holds", "", token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : masih ada eksemplar yang tersedia, silakan langsung meminjam", responseBody["error"])
+}
+
+func TestPlaceHoldFailedAlreadyQueued(t *testing.T) {
+	r := SetupRouterHold()
+	_, tokenBudi, _ := PrepareHold(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/holds", "", tokenBudi)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : kamu sudah berada di antrean buku ini", responseBody["error"])
+}
+
+func TestRenewFailedWhileHoldWaiting(t *testing.T) {
+	r := SetupRouterHold()
+	token, tokenBudi, _ := PrepareHold(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans/1/renew", "", tokenBudi)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : peminjaman tidak ditemukan atau sudah dikembalikan", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/loans/1/renew", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : anggota lain sedang mengantre buku ini, peminjaman tidak dapat diperpanjang", responseBody["error"])
+}
+
+func TestNewCopyReservedForNextHold(t *testing.T) {
+	r := SetupRouterHold()
+	token, tokenBudi, tokenCitra := PrepareHold(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0002", "branch": "Pusat"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "reserved", responseBody["data"].(map[string]interface{})["status"])
+
+	hold := RequestMyHold(t, r, tokenBudi)
+	assert.Equal(t, "ready", hold["status"])
+	assert.Equal(t, "LIB-0002", hold["barcode"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0003", "branch": "Pusat", "status": "in_repair"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	hold = RequestMyHold(t, r, tokenCitra)
+	assert.Equal(t, "waiting", hold["status"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPut, "/books/1/copies/3", `{"status": "available"}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "reserved", responseBody["data"].(map[string]interface{})["status"])
+
+	hold = RequestMyHold(t, r, tokenCitra)
+	assert.Equal(t, "ready", hold["status"])
+	assert.Equal(t, "LIB-0003", hold["barcode"])
+}
+
+func TestReturnReservesCopyForNextHold(t *testing.T) {
+	r := SetupRouterHold()
+	token, tokenBudi, tokenCitra := PrepareHold(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	hold := RequestMyHold(t, r, tokenBudi)
+	assert.Equal(t, "ready", hold["status"])
+	assert.Equal(t, "LIB-0001", hold["barcode"])
+	assert.NotNil(t, hold["expires_at"])
+
+	hold = RequestMyHold(t, r, tokenCitra)
+	assert.Equal(t, "waiting", hold["status"])
+	assert.Equal(t, float64(1), hold["queue_position"])
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : eksemplar sedang tidak tersedia untuk dipinjam", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, tokenBudi)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	hold = RequestMyHold(t, r, tokenBudi)
+	assert.Equal(t, "fulfilled", hold["status"])
+}
+
+func TestCancelReadyHoldPassesCopyOn(t *testing.T) {
+	r := SetupRouterHold()
+	token, tokenBudi, tokenCitra := PrepareHold(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/holds/1", "", tokenCitra)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : antrean tidak ditemukan atau sudah tidak aktif", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/holds/1", "", tokenBudi)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "cancelled", responseBody["data"].(map[string]interface{})["status"])
+
+	hold := RequestMyHold(t, r, tokenCitra)
+	assert.Equal(t, "ready", hold["status"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/holds/2", "", tokenCitra)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["available_copies"])
+}
+
+func TestExpiredHoldPassesToNextInLine(t *testing.T) {
+	r := SetupRouterHold()
+	token, tokenBudi, tokenCitra := PrepareHold(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+
+	db := OpenDB()
+
+	holdService := service.HoldServices{
+		HoldRepository: repository.NewHoldRepository(db),
+		PickupDays:     3,
+		Now:            func() time.Time { return time.Now().AddDate(0, 0, 4) },
+	}
+
+	expired, err := holdService.ExpireHolds()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	hold := RequestMyHold(t, r, tokenBudi)
+	assert.Equal(t, "expired", hold["status"])
+
+	hold = RequestMyHold(t, r, tokenCitra)
+	assert.Equal(t, "ready", hold["status"])
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, tokenCitra)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestGetMyHoldsEmpty(t *testing.T) {
+	r := SetupRouterHold()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/holds", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data antrean kosong", responseBody["message"])
+}
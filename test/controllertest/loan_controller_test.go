@@ -0,0 +1,241 @@
+package controllertest
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TruncateTableLoan(db *gorm.DB) {
+	testdb.Truncate(db, "fine_ledger", "loan")
+}
+
+func SetupRouterLoan() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateTableHold(db)
+	TruncateTableLoan(db)
+	TruncateTableBookCopy(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db)
+}
+
+func PrepareLoan(t *testing.T, r *gin.Engine) string {
+	token := PrepareBookCopy(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	return token
+}
+
+func TestCheckoutSuccess(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "Berhasil meminjam eksemplar", responseBody["message"])
+	assert.Equal(t, "ilhamm.ms", responseBody["data"].(map[string]interface{})["username"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(0), responseBody["data"].(map[string]interface{})["available_copies"])
+}
+
+func TestCheckoutFailedCopyOnLoan(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : eksemplar sedang tidak tersedia untuk dipinjam", responseBody["error"])
+}
+
+func TestCheckoutFailedCopyNotFound(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 9}`, token)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : eksemplar tidak ditemukan", responseBody["error"])
+}
+
+func TestCheckoutConcurrentOnlyOneSucceeds(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+			if recorder.Code == http.StatusCreated {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, created)
+}
+
+func TestReturnAndRenew(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans/1/renew", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["renewal_count"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/loans/1/renew", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : peminjaman sudah mencapai batas maksimal perpanjangan", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotNil(t, responseBody["data"].(map[string]interface{})["returned_at"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : peminjaman tidak ditemukan atau sudah dikembalikan", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestGetMyLoans(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/users/me/loans", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data peminjaman kosong", responseBody["message"])
+
+	RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/users/me/loans", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"], 1)
+}
+
+func TestCheckoutFailedBookInTrash(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : eksemplar tidak ditemukan", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/books/1/copies/1", "", token)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestDeleteBookFailedWhileOnLoan(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : buku masih dipinjam atau diantre anggota, selesaikan peminjaman dan hold-nya terlebih dahulu", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestBookEtagChangesWithAvailableCopies(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	tag := recorder.Header().Get("ETag")
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestConditional(r, http.MethodGet, "/books/1", "", token, "If-None-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(0), responseBody["data"].(map[string]interface{})["available_copies"])
+	assert.NotEqual(t, tag, recorder.Header().Get("ETag"))
+
+	recorder, _ = RequestConditional(r, http.MethodPatch, "/books/1", `{"title": "Laskar Pelangi"}`, token, "If-Match", tag)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestUpdateCopyStatusFailedWhileOnLoan(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, "/books/1/copies/1", `{"status": "available"}`, token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : eksemplar sedang dipinjam atau disiapkan untuk hold, statusnya hanya berubah lewat peminjaman dan hold", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPut, "/books/1/copies/1", `{"shelf": "B-2"}`, token)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "on_loan", responseBody["data"].(map[string]interface{})["status"])
+}
+
+func TestDeleteCopyFailedWhileOnLoan(t *testing.T) {
+	r := SetupRouterLoan()
+	token := PrepareLoan(t, r)
+
+	recorder, _ := RequestBookCopy(r, http.MethodPost, "/loans", `{"copy_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/books/1/copies/1", "", token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : eksemplar sedang dipinjam atau disiapkan untuk hold, eksemplar tidak bisa dihapus", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/loans/1/return", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodDelete, "/books/1/copies/1", "", token)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : eksemplar pernah dipinjam sehingga riwayatnya harus disimpan, ubah statusnya menjadi lost", responseBody["error"])
+}
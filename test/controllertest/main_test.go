@@ -0,0 +1,121 @@
+package controllertest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/api"
+	"github.com/ilhaamms/library-api/config"
+	"github.com/ilhaamms/library-api/controller"
+	"github.com/ilhaamms/library-api/jwtkey"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"gorm.io/gorm"
+)
+
+var testJwtKey = []byte("library-api-test-secret")
+
+var testKeys = jwtkey.NewHMAC(testJwtKey)
+
+var testConfig = config.Default()
+
+var testFinePolicy = service.FinePolicy{
+	GraceDays:  1,
+	MaxPerItem: 500000,
+	Rates:      map[string]int64{"regular": 100000, "student": 50000},
+}
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "library-api-test")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Configure(&testConfig, dir)
+	testConfig.JwtSecret = string(testJwtKey)
+	testConfig.MaxRenewals = 1
+	testConfig.FineGraceDays = testFinePolicy.GraceDays
+	testConfig.FineMaxPerItem = testFinePolicy.MaxPerItem
+	testConfig.FineRates = testFinePolicy.Rates
+
+	db, err := config.InitDB(&testConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	err = testdb.Migrate(db)
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// OpenDB connects to the test database, the caller truncates the tables it
+// is about to seed.
+func OpenDB() *gorm.DB {
+	db, err := config.InitDB(&testConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// NewRouter wires every repository, service and controller the way main does
+// and returns the routes of the API, so a test runs against the same
+// middleware and role checks as production.
+func NewRouter(db *gorm.DB) *gin.Engine {
+
+	gin.SetMode(gin.TestMode)
+
+	authorRepo := repository.NewAuthorRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	bookRepo := repository.NewBookRepository(db)
+	bookCopyRepo := repository.NewBookCopyRepository(db)
+	loanRepo := repository.NewLoanRepository(db)
+	holdRepo := repository.NewHoldRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	authorService := service.NewAuthorService(authorRepo, auditRepo, transactor)
+	userService := service.NewUserService(userRepo, tokenRepo, auditRepo, transactor, testKeys)
+	bookService := service.NewBookService(bookRepo, auditRepo, transactor)
+	bookCopyService := service.NewBookCopyService(bookCopyRepo, bookRepo, testConfig.HoldPickupDays)
+	loanService := service.NewLoanService(loanRepo, userRepo, testConfig.LoanDays, testConfig.MaxRenewals, testConfig.HoldPickupDays, testFinePolicy)
+	holdService := service.NewHoldService(holdRepo, bookRepo, userRepo, testConfig.HoldPickupDays)
+	fineService := service.NewFineService(fineRepo, loanRepo, userRepo, testFinePolicy)
+	publisherService := service.NewPublisherService(publisherRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	trashService := service.NewTrashService(trashRepo, testConfig.TrashRetentionDays)
+	auditService := service.NewAuditService(auditRepo)
+
+	return api.NewAPI(
+		&testConfig,
+		testKeys,
+		controller.NewAuthorController(authorService),
+		controller.NewUserController(userService),
+		controller.NewBookController(bookService),
+		controller.NewBookCopyController(bookCopyService),
+		controller.NewLoanController(loanService),
+		controller.NewHoldController(holdService),
+		controller.NewFineController(fineService),
+		controller.NewPublisherController(publisherService),
+		controller.NewCategoryController(categoryService, bookService),
+		controller.NewTrashController(trashService),
+		controller.NewAuditController(auditService),
+		controller.NewIsbnController(),
+		controller.NewJwksController(testKeys),
+		userService,
+	).RegisterRoutes()
+}
@@ -0,0 +1,153 @@
+package controllertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TruncateTablePublisher(db *gorm.DB) {
+	testdb.Truncate(db, "publisher")
+}
+
+func SetupRouterPublisher() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+	TruncateTablePublisher(db)
+
+	return NewRouter(db)
+}
+
+func TestCreatePublisherSuccess(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Bentang Pustaka", "city": "Yogyakarta"}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, float64(1), responseBody["data"].(map[string]interface{})["id"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "bentang pustaka"}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : nama publisher sudah digunakan", responseBody["error"])
+}
+
+func TestGetAllPublisherFilterName(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Bentang Pustaka", "city": "Yogyakarta"}`, token)
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Gramedia Pustaka Utama", "city": "Jakarta"}`, token)
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Mizan", "city": "Bandung"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/publishers?name=pustaka&sort=name&order=desc", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	publishers := responseBody["data"].([]interface{})
+	assert.Len(t, publishers, 2)
+	assert.Equal(t, "Gramedia Pustaka Utama", publishers[0].(map[string]interface{})["name"])
+	assert.Equal(t, float64(2), responseBody["pagination"].(map[string]interface{})["total_items"])
+}
+
+func TestUpdatePublisherSuccess(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Mizan", "city": "Bandung"}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, "/publishers/1", `{"city": "Jakarta"}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Mizan", responseBody["data"].(map[string]interface{})["name"])
+	assert.Equal(t, "Jakarta", responseBody["data"].(map[string]interface{})["city"])
+}
+
+func TestDeletePublisherFailedInUse(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Bentang Pustaka"}`, token)
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder := RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1, "publisher_id": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/publishers/1", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : publisher masih dipakai oleh buku", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/publishers/2", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSaveBookWithPublication(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	RequestBookCopy(r, http.MethodPost, "/publishers", `{"name": "Bentang Pustaka", "city": "Yogyakarta"}`, token)
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+
+	recorder := RequestCreateBook(r, `{
+		"title": "Laskar Pelangi",
+		"isbn": "9789793062792",
+		"author_id": 1,
+		"publisher_id": 1,
+		"publication_year": 2005,
+		"edition": "1",
+		"page_count": 529,
+		"language": "ID",
+		"format": "paperback",
+		"description": "Novel tentang sepuluh anak di Belitung"
+	}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	book := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Bentang Pustaka", book["publisher"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(2005), book["publication_year"])
+	assert.Equal(t, float64(529), book["page_count"])
+	assert.Equal(t, "id", book["language"])
+	assert.Equal(t, "paperback", book["format"])
+	assert.Nil(t, book["work_id"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?publisher_id=1&language=id&year_from=2000&year_to=2010", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].([]interface{}), 1)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?format=ebook", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data book kosong", responseBody["message"])
+}
+
+func TestSaveBookAsEdition(t *testing.T) {
+	r := SetupRouterPublisher()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1, "format": "paperback"}`, token)
+	RequestCreateBook(r, `{"title": "Sang Pemimpi", "isbn": "9789793062921", "author_id": 1}`, token)
+
+	recorder := RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9786022910039", "author_id": 1, "format": "ebook", "edition_of": 1}`, token)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	workId := responseBody["data"].(map[string]interface{})["work_id"]
+	assert.Equal(t, float64(1), workId)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/books?work_id=1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, responseBody["data"].([]interface{}), 2)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Edensor", "isbn": "9789791227001", "author_id": 1, "edition_of": 99}`, token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "error : buku pada edition_of tidak ditemukan", responseBody["error"])
+}
@@ -0,0 +1,124 @@
+package controllertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func SetupRouterRole() (*gin.Engine, *gorm.DB) {
+
+	db := OpenDB()
+
+	TruncateTableHold(db)
+	TruncateTableLoan(db)
+	TruncateTableBookCopy(db)
+	TruncateTableBook(db)
+	TruncateAuthorTable(db)
+
+	return NewRouter(db), db
+}
+
+func UserIdByUsername(db *gorm.DB, username string) int {
+	var id int
+	db.Table("user").Select("id").Where("username = ?", username).Scan(&id)
+
+	return id
+}
+
+// RegisterAdmin creates the account in reqBody as an admin the way the
+// create-admin command does, registering only ever creates members.
+func RegisterAdmin(t *testing.T, reqBody string) {
+	TruncateUserTable()
+
+	var user request.User
+	err := json.Unmarshal([]byte(reqBody), &user)
+	assert.Nil(t, err)
+
+	db := OpenDB()
+	userService := service.NewUserService(repository.NewUserRepository(db), repository.NewTokenRepository(db), repository.NewAuditRepository(db), repository.NewTransactor(db), testKeys)
+
+	err = userService.CreateAdmin(data.Actor{}, user)
+	assert.Nil(t, err)
+}
+
+func LoginAdmin(t *testing.T, r *gin.Engine) string {
+	reqBody := `{"username": "ilhamm.ms", "password": "ilhamsidiq"}`
+
+	RegisterAdmin(t, reqBody)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/login", reqBody, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	return responseBody["data"].(map[string]interface{})["token"].(string)
+}
+
+func TestRegisterCreatesMember(t *testing.T) {
+	r, _ := SetupRouterRole()
+
+	reqBody := `{"username": "ilhamm.ms", "password": "ilhamsidiq"}`
+
+	recorder := RequestRegisterUser(r, reqBody)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/login", reqBody, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "member", responseBody["data"].(map[string]interface{})["role"])
+}
+
+func TestMemberIsReadOnly(t *testing.T) {
+	r, _ := SetupRouterRole()
+	LoginAdmin(t, r)
+
+	tokenMember := LoginAnotherUser(t, r, "budiman")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/authors", `{"name": "Tere Liye", "birth_date": "1979-05-21"}`, tokenMember)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, "error : role tidak memiliki akses", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/authors", "", tokenMember)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPut, "/users/1/role", `{"role": "admin"}`, tokenMember)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestAdminAssignsLibrarianRole(t *testing.T) {
+	r, db := SetupRouterRole()
+	token := LoginAdmin(t, r)
+
+	LoginAnotherUser(t, r, "budiman")
+	memberId := UserIdByUsername(db, "budiman")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, fmt.Sprintf("/users/%d/role", memberId), `{"role": "librarian"}`, token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "librarian", responseBody["data"].(map[string]interface{})["role"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/auth/login", `{"username": "budiman", "password": "rahasia123"}`, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	tokenLibrarian := responseBody["data"].(map[string]interface{})["token"].(string)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/authors", `{"name": "Tere Liye", "birth_date": "1979-05-21"}`, tokenLibrarian)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestAdminCannotDemoteLastAdmin(t *testing.T) {
+	r, db := SetupRouterRole()
+	token := LoginAdmin(t, r)
+
+	adminId := UserIdByUsername(db, "ilhamm.ms")
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPut, fmt.Sprintf("/users/%d/role", adminId), `{"role": "member"}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : minimal harus ada satu admin", responseBody["error"])
+}
@@ -0,0 +1,96 @@
+package controllertest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func SetupRouterToken() *gin.Engine {
+
+	db := OpenDB()
+
+	TruncateAuthorTable(db)
+
+	return NewRouter(db)
+}
+
+func LoginWithRefreshToken(t *testing.T, r *gin.Engine) (string, string) {
+	reqBody := `{"username": "ilhamm.ms", "password": "ilhamsidiq"}`
+
+	recorder := RequestRegisterUser(r, reqBody)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/login", reqBody, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	dataUser := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(3600), dataUser["expires_in"])
+
+	return dataUser["token"].(string), dataUser["refresh_token"].(string)
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	r := SetupRouterToken()
+	_, refreshToken := LoginWithRefreshToken(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/refresh", fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken), "")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "refresh token berhasil", responseBody["message"])
+
+	dataUser := responseBody["data"].(map[string]interface{})
+	assert.NotEqual(t, refreshToken, dataUser["refresh_token"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/authors", "", dataUser["token"].(string))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	r := SetupRouterToken()
+	_, refreshToken := LoginWithRefreshToken(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/refresh", fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	dataUser := responseBody["data"].(map[string]interface{})
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/auth/refresh", fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "error : refresh token sudah pernah dipakai, sesi dicabut", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/auth/refresh", fmt.Sprintf(`{"refresh_token": "%s"}`, dataUser["refresh_token"]), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/authors", "", dataUser["token"].(string))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "error : token sudah dicabut", responseBody["error"])
+}
+
+func TestRefreshTokenFailedUnknown(t *testing.T) {
+	r := SetupRouterToken()
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/refresh", `{"refresh_token": "bukan-token"}`, "")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "error : refresh token tidak valid atau sudah kedaluwarsa", responseBody["error"])
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	r := SetupRouterToken()
+	token, refreshToken := LoginWithRefreshToken(t, r)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/auth/logout", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "logout berhasil", responseBody["message"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/authors", "", token)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "error : token sudah dicabut", responseBody["error"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/auth/refresh", fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
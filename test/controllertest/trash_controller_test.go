@@ -0,0 +1,183 @@
+package controllertest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteAndRestoreBook(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, _ := RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodGet, "/books", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data book kosong", responseBody["message"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	items := responseBody["data"].([]interface{})
+	assert.Len(t, items, 1)
+
+	item := items[0].(map[string]interface{})
+	assert.Equal(t, "book", item["entity"])
+	assert.Equal(t, float64(1), item["id"])
+	assert.Equal(t, "Laskar Pelangi", item["name"])
+
+	deletedAt, _ := time.Parse(time.RFC3339, item["deleted_at"].(string))
+	purgeAt, _ := time.Parse(time.RFC3339, item["purge_at"].(string))
+	assert.Equal(t, deletedAt.AddDate(0, 0, testConfig.TrashRetentionDays), purgeAt)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Laskar Pelangi", "isbn": "979-3062-79-7", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : isbn sudah digunakan oleh buku dengan id 1 yang ada di trash, pulihkan buku tersebut", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books/1/restore", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Data buku berhasil dipulihkan", responseBody["message"])
+	assert.Regexp(t, `^"3-[0-9a-f]{16}"$`, recorder.Header().Get("ETag"))
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/books/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books/1/restore", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Trash kosong", responseBody["message"])
+}
+
+func TestDeleteAndRestoreAuthor(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodDelete, "/authors/1", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : author masih memiliki buku yang tercantum di data, hapus bukunya dengan strategy=cascade atau pindahkan dengan strategy=reassign", responseBody["error"])
+
+	RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+
+	recorder, _ = RequestBookCopy(r, http.MethodDelete, "/authors/1", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = RequestBookCopy(r, http.MethodGet, "/authors/1", "", token)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash?entity=author", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, float64(1), responseBody["pagination"].(map[string]interface{})["total_items"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Sang Pemimpi", "isbn": "0306406152", "author_id": 1}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/books/1/restore", "", token)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "error : author buku ini masih ada di trash, pulihkan author terlebih dahulu", responseBody["error"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodPost, "/authors/1/restore", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Andrea Hirata", responseBody["data"].(map[string]interface{})["name"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books/1/restore", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash?entity=user", "", token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, map[string]string{"entity": "oneof"}, DetailCodes(responseBody))
+}
+
+func TestPurgeTrash(t *testing.T) {
+	r := SetupRouterBook()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	RequestCreateBook(r, `{"title": "Sang Pemimpi", "isbn": "0306406152", "author_id": 1}`, token)
+
+	RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+	RequestBookCopy(r, http.MethodDelete, "/books/2", "", token)
+	RequestBookCopy(r, http.MethodDelete, "/authors/1", "", token)
+
+	db, err := config.InitDB(&testConfig)
+	assert.Nil(t, err)
+
+	expired := time.Now().UTC().AddDate(0, 0, -testConfig.TrashRetentionDays-1)
+	db.Exec("UPDATE book SET deleted_at = ? WHERE id = 1", expired)
+	db.Exec("UPDATE author SET deleted_at = ? WHERE id = 1", expired)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/trash/purge", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// the author is kept while a book in the trash still refers to them
+	purged := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), purged["books"])
+	assert.Equal(t, float64(0), purged["authors"])
+
+	recorder, responseBody = RequestBookCopy(r, http.MethodGet, "/trash?entity=book", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	items := responseBody["data"].([]interface{})
+	assert.Len(t, items, 1)
+	assert.Equal(t, float64(2), items[0].(map[string]interface{})["id"])
+
+	recorder, _ = RequestBookCopy(r, http.MethodPost, "/books", `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 2}`, token)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+func TestPurgeTrashRemovesBookRows(t *testing.T) {
+	r := SetupRouterBookCopy()
+	token := LoginAdmin(t, r)
+
+	RequestCreateAuthor(r, `{"name": "Andrea Hirata", "birth_date": "1967-10-24"}`, token)
+	RequestCreateBook(r, `{"title": "Laskar Pelangi", "isbn": "9789793062792", "author_id": 1}`, token)
+	RequestBookCopy(r, http.MethodPost, "/books/1/copies", `{"barcode": "LIB-0001", "branch": "Pusat"}`, token)
+
+	RequestBookCopy(r, http.MethodDelete, "/books/1", "", token)
+	RequestBookCopy(r, http.MethodDelete, "/authors/1", "", token)
+
+	db, err := config.InitDB(&testConfig)
+	assert.Nil(t, err)
+
+	expired := time.Now().UTC().AddDate(0, 0, -testConfig.TrashRetentionDays-1)
+	db.Exec("UPDATE book SET deleted_at = ? WHERE id = 1", expired)
+	db.Exec("UPDATE author SET deleted_at = ? WHERE id = 1", expired)
+
+	recorder, responseBody := RequestBookCopy(r, http.MethodPost, "/trash/purge", "", token)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// the contributor row goes with the book, so the author is purged too
+	purged := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), purged["books"])
+	assert.Equal(t, float64(1), purged["authors"])
+
+	for _, table := range []string{"book_copy", "book_contributor", "book_category", "hold"} {
+		var rows int64
+		db.Table(table).Where("book_id = ?", 1).Count(&rows)
+		assert.Equal(t, int64(0), rows, table)
+	}
+
+	if db.Dialector.Name() == config.DriverSQLite {
+		var foreignKeys int
+		db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys)
+
+		// enforced by every connection of the pool, not only the first one
+		assert.Equal(t, 1, foreignKeys)
+	}
+}
@@ -9,47 +9,22 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ilhaamms/library-api/config"
-	"github.com/ilhaamms/library-api/controller"
-	"github.com/ilhaamms/library-api/repository"
-	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/testdb"
 	"github.com/stretchr/testify/assert"
 )
 
 func SetupRouterUser() *gin.Engine {
 
-	gin.SetMode(gin.TestMode)
-
-	db, err := config.InitDbSQLite()
-	if err != nil {
-		panic(err)
-	}
-
 	TruncateUserTable()
 
-	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userController := controller.NewUserController(userService)
-
-	r := gin.Default()
-
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", userController.Register)
-		auth.POST("/login", userController.Login)
-	}
-
-	return r
+	return NewRouter(OpenDB())
 }
 
 func TruncateUserTable() {
 
-	db, err := config.InitDbSQLite()
-	if err != nil {
-		panic(err)
-	}
+	db := OpenDB()
 
-	db.Exec("DELETE FROM user")
+	testdb.Truncate(db, "hold", "fine_ledger", "loan", "refresh_token", "revoked_token", "user")
 }
 
 func TestRegisterUserSuccess(t *testing.T) {
@@ -99,9 +74,10 @@ func TestRegisterUserFailedUsernameEmpty(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
-	assert.Equal(t, "error : username dan password wajib diisi", responseBody["error"])
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"username": "required"}, DetailCodes(responseBody))
 }
 
 func TestRegisterUserFailedPasswordEmpty(t *testing.T) {
@@ -125,9 +101,10 @@ func TestRegisterUserFailedPasswordEmpty(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
-	assert.Equal(t, "error : username dan password wajib diisi", responseBody["error"])
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"password": "required"}, DetailCodes(responseBody))
 }
 
 func TestRegisterUserFailedMinUsername(t *testing.T) {
@@ -151,9 +128,10 @@ func TestRegisterUserFailedMinUsername(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
-	assert.Equal(t, "error : username minimal 5 karakter", responseBody["error"])
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"username": "min"}, DetailCodes(responseBody))
 }
 
 func TestRegisterUserFailedMaxUsername(t *testing.T) {
@@ -178,9 +156,10 @@ func TestRegisterUserFailedMaxUsername(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
-	assert.Equal(t, "error : username maksimal 20 karakter", responseBody["error"])
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"username": "max"}, DetailCodes(responseBody))
 }
 
 func TestRegisterUserFailedMinPassword(t *testing.T) {
@@ -204,9 +183,10 @@ func TestRegisterUserFailedMinPassword(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
-	assert.Equal(t, "error : harap masukkan password minimal 8 karakter", responseBody["error"])
+	assert.Equal(t, "error : data yang dikirim tidak valid, lihat details", responseBody["error"])
+	assert.Equal(t, map[string]string{"password": "min"}, DetailCodes(responseBody))
 }
 
 func TestLoginFailed(t *testing.T) {
@@ -230,7 +210,7 @@ func TestLoginFailed(t *testing.T) {
 	var responseBody map[string]interface{}
 	json.Unmarshal(body, &responseBody)
 
-	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("Content-Type"))
 	assert.Equal(t, "error : username atau password salah", responseBody["error"])
 }
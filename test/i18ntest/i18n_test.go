@@ -0,0 +1,41 @@
+package i18ntest
+
+import (
+	"testing"
+
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/isbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCataloguesHaveSameKeys(t *testing.T) {
+	assert.Equal(t, []string{"en", "id"}, i18n.Languages())
+	assert.Equal(t, i18n.Keys(i18n.Default), i18n.Keys(i18n.English))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "book tidak ditemukan", i18n.T("id", "book.not_found"))
+	assert.Equal(t, "book not found", i18n.T("en", "book.not_found"))
+	assert.Equal(t, "book tidak ditemukan", i18n.T("fr", "book.not_found"))
+	assert.Equal(t, "book tidak ditemukan", i18n.T("", "book.not_found"))
+	assert.Equal(t, "unknown.key", i18n.T("en", "unknown.key"))
+
+	assert.Equal(t, "sort must be one of : title, year", i18n.T("en", "pagination.sort_invalid", i18n.Params{"allowed": "title, year"}))
+	assert.Equal(t, "page harus berupa angka", i18n.T("id", "request.param_not_number", i18n.Params{"name": "page"}))
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, "en", i18n.Match("en"))
+	assert.Equal(t, "en", i18n.Match("EN-us"))
+	assert.Equal(t, "id", i18n.Match("id-ID,id;q=0.9,en;q=0.8"))
+	assert.Equal(t, "en", i18n.Match("fr-FR,id;q=0.5,en;q=0.8"))
+	assert.Equal(t, "", i18n.Match("fr"))
+	assert.Equal(t, "", i18n.Match(""))
+}
+
+func TestIsbnMessageKeys(t *testing.T) {
+	for _, err := range []error{isbn.ErrLength, isbn.ErrCharacter, isbn.ErrPrefix, isbn.ErrChecksum, isbn.ErrNotConvertible} {
+		assert.Equal(t, err.Error(), i18n.T(i18n.Indonesian, isbn.MessageKey(err)))
+		assert.NotEqual(t, isbn.MessageKey(err), i18n.T(i18n.English, isbn.MessageKey(err)))
+	}
+}
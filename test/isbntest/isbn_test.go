@@ -0,0 +1,48 @@
+package isbntest
+
+import (
+	"testing"
+
+	"github.com/ilhaamms/library-api/isbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	for _, value := range []string{"0306406152", "0-306-40615-2", "080442957x", "978-0-13-468599-1", "979 10 90636 07 1"} {
+		assert.Nil(t, isbn.Validate(value), value)
+	}
+
+	assert.Equal(t, isbn.ErrLength, isbn.Validate("123456789"))
+	assert.Equal(t, isbn.ErrLength, isbn.Validate("12345678901234"))
+	assert.Equal(t, isbn.ErrCharacter, isbn.Validate("ABCDEFGHIJ"))
+	assert.Equal(t, isbn.ErrCharacter, isbn.Validate("03064X6152"))
+	assert.Equal(t, isbn.ErrChecksum, isbn.Validate("0306406153"))
+	assert.Equal(t, isbn.ErrChecksum, isbn.Validate("9780134685990"))
+	assert.Equal(t, isbn.ErrPrefix, isbn.Validate("1234567890128"))
+}
+
+func TestNormalize(t *testing.T) {
+	normalized, err := isbn.Normalize("0-306-40615-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "9780306406157", normalized)
+
+	normalized, err = isbn.Normalize("978-0-13-468599-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "9780134685991", normalized)
+
+	_, err = isbn.Normalize("1234567890")
+	assert.Equal(t, isbn.ErrChecksum, err)
+}
+
+func TestConvert(t *testing.T) {
+	isbn13, err := isbn.To13("080442957X")
+	assert.Nil(t, err)
+	assert.Equal(t, "9780804429573", isbn13)
+
+	isbn10, err := isbn.To10(isbn13)
+	assert.Nil(t, err)
+	assert.Equal(t, "080442957X", isbn10)
+
+	_, err = isbn.To10("9791090636071")
+	assert.Equal(t, isbn.ErrNotConvertible, err)
+}
@@ -0,0 +1,204 @@
+package jwtkeytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/ilhaamms/library-api/config"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/jwtkey"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func writeRSAKey(t *testing.T) (string, string) {
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	public, _ := x509.MarshalPKIXPublicKey(&private.PublicKey)
+
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)), writePEM(t, "rsa.pub", "PUBLIC KEY", public)
+}
+
+func writeECKey(t *testing.T) string {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalECPrivateKey(private)
+
+	return writePEM(t, "ec.pem", "EC PRIVATE KEY", der)
+}
+
+func writeEdKey(t *testing.T) string {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+
+	return writePEM(t, "ed.pem", "PRIVATE KEY", der)
+}
+
+func claims() *data.Claims {
+	return &data.Claims{
+		Username: "ilhamm.ms",
+		Role:     "member",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+}
+
+func verify(keys *jwtkey.KeySet, tokenString string) error {
+	_, err := jwt.ParseWithClaims(tokenString, &data.Claims{}, keys.Keyfunc)
+
+	return err
+}
+
+func TestSignAndVerifyEveryAlgorithm(t *testing.T) {
+	rsaPrivate, _ := writeRSAKey(t)
+
+	for algorithm, path := range map[string]string{
+		jwtkey.AlgorithmRS256: rsaPrivate,
+		jwtkey.AlgorithmES256: writeECKey(t),
+		jwtkey.AlgorithmEdDSA: writeEdKey(t),
+	} {
+		keys, err := jwtkey.FromConfig(&config.Config{
+			JwtKeys:      []config.JwtKey{{Kid: "key-1", Algorithm: algorithm, PrivateKey: path}},
+			JwtActiveKey: "key-1",
+		})
+		assert.Nil(t, err, algorithm)
+
+		tokenString, err := keys.Sign(claims())
+		assert.Nil(t, err, algorithm)
+
+		token, _, _ := new(jwt.Parser).ParseUnverified(tokenString, &data.Claims{})
+		assert.Equal(t, "key-1", token.Header["kid"], algorithm)
+		assert.Equal(t, algorithm, token.Header["alg"], algorithm)
+
+		assert.Nil(t, verify(keys, tokenString), algorithm)
+	}
+}
+
+func TestRotatedKeyStillVerifies(t *testing.T) {
+	oldPrivate, oldPublic := writeRSAKey(t)
+	newPrivate := writeEdKey(t)
+
+	before, err := jwtkey.FromConfig(&config.Config{
+		JwtKeys:      []config.JwtKey{{Kid: "2024-01", Algorithm: "RS256", PrivateKey: oldPrivate}},
+		JwtActiveKey: "2024-01",
+	})
+	assert.Nil(t, err)
+
+	oldToken, _ := before.Sign(claims())
+
+	after, err := jwtkey.FromConfig(&config.Config{
+		JwtKeys: []config.JwtKey{
+			{Kid: "2024-01", Algorithm: "RS256", PublicKey: oldPublic},
+			{Kid: "2024-10", Algorithm: "EdDSA", PrivateKey: newPrivate},
+		},
+		JwtActiveKey: "2024-10",
+	})
+	assert.Nil(t, err)
+
+	newToken, _ := after.Sign(claims())
+
+	assert.Nil(t, verify(after, oldToken))
+	assert.Nil(t, verify(after, newToken))
+	assert.NotNil(t, verify(before, newToken))
+}
+
+func TestActiveKeyWithoutPrivateKey(t *testing.T) {
+	_, public := writeRSAKey(t)
+
+	keys, err := jwtkey.FromConfig(&config.Config{
+		JwtKeys:      []config.JwtKey{{Kid: "key-1", Algorithm: "RS256", PublicKey: public}},
+		JwtActiveKey: "key-1",
+	})
+
+	assert.Nil(t, keys)
+	assert.Equal(t, "jwt_active_key key-1 harus memiliki private_key", err.Error())
+}
+
+func TestMalformedPublicKey(t *testing.T) {
+	malformed := writePEM(t, "broken.pub", "PUBLIC KEY", []byte("bukan public key"))
+
+	for _, algorithm := range jwtkey.Algorithms {
+		keys, err := jwtkey.FromConfig(&config.Config{
+			JwtKeys:      []config.JwtKey{{Kid: "key-1", Algorithm: algorithm, PublicKey: malformed}},
+			JwtActiveKey: "key-1",
+		})
+
+		assert.Nil(t, keys, algorithm)
+		assert.NotNil(t, err, algorithm)
+	}
+}
+
+func TestPublicKeyNotMatchingPrivateKey(t *testing.T) {
+	private, _ := writeRSAKey(t)
+	_, otherPublic := writeRSAKey(t)
+
+	keys, err := jwtkey.FromConfig(&config.Config{
+		JwtKeys:      []config.JwtKey{{Kid: "key-1", Algorithm: "RS256", PrivateKey: private, PublicKey: otherPublic}},
+		JwtActiveKey: "key-1",
+	})
+
+	assert.Nil(t, keys)
+	assert.Equal(t, "jwt_keys.key-1 : public_key tidak cocok dengan private_key", err.Error())
+}
+
+func TestHMACTokenRejectedByAsymmetricKeySet(t *testing.T) {
+	rsaPrivate, _ := writeRSAKey(t)
+
+	keys, _ := jwtkey.FromConfig(&config.Config{
+		JwtKeys:      []config.JwtKey{{Kid: "key-1", Algorithm: "RS256", PrivateKey: rsaPrivate}},
+		JwtActiveKey: "key-1",
+	})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	token.Header["kid"] = "key-1"
+	tokenString, _ := token.SignedString([]byte("library-api-secret-key"))
+
+	assert.NotNil(t, verify(keys, tokenString))
+}
+
+func TestJWKSPublishesPublicKeys(t *testing.T) {
+	rsaPrivate, _ := writeRSAKey(t)
+
+	keys, _ := jwtkey.FromConfig(&config.Config{
+		JwtKeys: []config.JwtKey{
+			{Kid: "ec", Algorithm: "ES256", PrivateKey: writeECKey(t)},
+			{Kid: "ed", Algorithm: "EdDSA", PrivateKey: writeEdKey(t)},
+			{Kid: "rsa", Algorithm: "RS256", PrivateKey: rsaPrivate},
+		},
+		JwtActiveKey: "rsa",
+	})
+
+	jwks := keys.JWKS()
+
+	assert.Len(t, jwks.Keys, 3)
+	assert.Equal(t, "EC", jwks.Keys[0].Kty)
+	assert.Equal(t, "P-256", jwks.Keys[0].Crv)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "RSA", jwks.Keys[2].Kty)
+	assert.Equal(t, "AQAB", jwks.Keys[2].E)
+}
+
+func TestJWKSHidesHMACSecret(t *testing.T) {
+	keys := jwtkey.NewHMAC([]byte("library-api-secret-key"))
+
+	assert.Empty(t, keys.JWKS().Keys)
+}
@@ -0,0 +1,51 @@
+package mergepatchtest
+
+import (
+	"testing"
+
+	"github.com/ilhaamms/library-api/mergepatch"
+	"github.com/stretchr/testify/assert"
+)
+
+// the examples of RFC 7396 appendix A
+func TestApply(t *testing.T) {
+	cases := []struct {
+		document, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, c := range cases {
+		result, err := mergepatch.Apply([]byte(c.document), []byte(c.patch))
+		assert.Nil(t, err, c.patch)
+		assert.JSONEq(t, c.result, string(result), c.patch)
+	}
+}
+
+func TestApplyInvalidPatch(t *testing.T) {
+	_, err := mergepatch.Apply([]byte(`{"a":"b"}`), []byte(`{"a":`))
+	assert.Equal(t, mergepatch.ErrInvalid, err)
+}
+
+func TestKeys(t *testing.T) {
+	keys, err := mergepatch.Keys([]byte(`{"title":"Laskar Pelangi","description":null}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"title": true, "description": true}, keys)
+
+	_, err = mergepatch.Keys([]byte(`["title"]`))
+	assert.Equal(t, mergepatch.ErrInvalid, err)
+}
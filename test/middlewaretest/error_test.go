@@ -0,0 +1,121 @@
+package middlewaretest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func SetupRouterError() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.Locale(), middleware.ErrorHandler())
+
+	r.GET("/not-found", func(c *gin.Context) {
+		c.Error(apperror.NotFound("book.not_found"))
+	})
+	r.GET("/invalid", func(c *gin.Context) {
+		c.Error(apperror.Invalid("isbn", "isbn.checksum"))
+	})
+	r.GET("/wrapped", func(c *gin.Context) {
+		c.Error(fmt.Errorf("simpan book : %w", apperror.Conflict("book.isbn_taken")))
+	})
+	r.GET("/untyped", func(c *gin.Context) {
+		c.Error(errors.New("koneksi database terputus"))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.Error(apperror.NotFound("book.not_found"))
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	return r
+}
+
+func RequestError(r *gin.Engine, url string, headers ...string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	request := httptest.NewRequest(http.MethodGet, url, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+
+	return recorder, responseBody
+}
+
+func TestErrorHandlerMapsKinds(t *testing.T) {
+	r := SetupRouterError()
+
+	recorder, responseBody := RequestError(r, "/not-found")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, apperror.CodeNotFound, responseBody["code"])
+	assert.Equal(t, "error : book tidak ditemukan", responseBody["error"])
+	assert.Nil(t, responseBody["details"])
+
+	recorder, responseBody = RequestError(r, "/invalid")
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, apperror.CodeValidation, responseBody["code"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "isbn", "code": "invalid", "message": "check digit isbn tidak valid"}}, responseBody["details"])
+
+	recorder, responseBody = RequestError(r, "/wrapped")
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, apperror.CodeConflict, responseBody["code"])
+
+	recorder, responseBody = RequestError(r, "/untyped")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, apperror.CodeInternal, responseBody["code"])
+	assert.Equal(t, "error : terjadi kesalahan pada server : koneksi database terputus", responseBody["error"])
+}
+
+func TestErrorHandlerLocalizes(t *testing.T) {
+	r := SetupRouterError()
+
+	recorder, responseBody := RequestError(r, "/invalid?lang=en")
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "en", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "error : isbn check digit is not valid", responseBody["error"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "isbn", "code": "invalid", "message": "isbn check digit is not valid"}}, responseBody["details"])
+
+	recorder, responseBody = RequestError(r, "/not-found", "Accept-Language", "fr-FR,en-US;q=0.8,id;q=0.5")
+	assert.Equal(t, "en", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "error : book not found", responseBody["error"])
+
+	recorder, responseBody = RequestError(r, "/not-found?lang=id", "Accept-Language", "en")
+	assert.Equal(t, "id", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "error : book tidak ditemukan", responseBody["error"])
+
+	recorder, responseBody = RequestError(r, "/not-found?lang=fr")
+	assert.Equal(t, "id", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "error : book tidak ditemukan", responseBody["error"])
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	r := SetupRouterError()
+
+	recorder, responseBody := RequestError(r, "/written")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok", responseBody["message"])
+}
+
+func TestErrorIsKind(t *testing.T) {
+	err := fmt.Errorf("hapus publisher : %w", apperror.Conflict("publisher.in_use"))
+
+	assert.True(t, errors.Is(err, apperror.ErrConflict))
+	assert.False(t, errors.Is(err, apperror.ErrNotFound))
+
+	internal := apperror.Internal("book.fetch_failed", errors.New("timeout"))
+	assert.Equal(t, "gagal mengambil data book : timeout", internal.Error())
+	assert.True(t, errors.Is(internal, apperror.ErrInternal))
+}
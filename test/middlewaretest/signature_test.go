@@ -0,0 +1,125 @@
+package middlewaretest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ilhaamms/library-api/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecrets = map[string]string{"partner-1": "partner-1-secret-key"}
+
+func SetupRouterSignature(required bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.Locale(), middleware.ErrorHandler())
+	r.Use(middleware.Signature(testSecrets, 5*time.Minute, required))
+
+	r.POST("/books", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	return r
+}
+
+func SignedRequest(r *gin.Engine, url, body, signedBody string, signedAt time.Time, nonce string) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+
+	request := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	request.Header.Set(middleware.HeaderKeyId, "partner-1")
+	request.Header.Set(middleware.HeaderTimestamp, timestamp)
+	request.Header.Set(middleware.HeaderNonce, nonce)
+	request.Header.Set(middleware.HeaderSignature, middleware.Sign([]byte(testSecrets["partner-1"]), http.MethodPost, "/books", timestamp, nonce, []byte(signedBody)))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestSignatureValid(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	recorder := SignedRequest(r, "/books", `{"title": "Laskar Pelangi"}`, `{"title": "Laskar Pelangi"}`, time.Now(), "nonce-1")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `{"title": "Laskar Pelangi"}`, recorder.Body.String())
+}
+
+func TestSignatureFailedTamperedBody(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	recorder := SignedRequest(r, "/books", `{"title": "Bumi Manusia"}`, `{"title": "Laskar Pelangi"}`, time.Now(), "nonce-1")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "signature tidak valid")
+}
+
+func TestSignatureFailedTamperedQuery(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	recorder := SignedRequest(r, "/books?force=true", "", "", time.Now(), "nonce-1")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "signature tidak valid")
+}
+
+func TestSignatureFailedStaleTimestamp(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	recorder := SignedRequest(r, "/books", "", "", time.Now().Add(-10*time.Minute), "nonce-1")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "timestamp request sudah kedaluwarsa")
+}
+
+func TestSignatureFailedReplay(t *testing.T) {
+	r := SetupRouterSignature(true)
+	signedAt := time.Now()
+
+	recorder := SignedRequest(r, "/books", "", "", signedAt, "nonce-1")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = SignedRequest(r, "/books", "", "", signedAt, "nonce-1")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "nonce sudah pernah dipakai")
+}
+
+func TestSignatureRequired(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "request wajib ditandatangani")
+}
+
+func TestSignatureOptionalAllowsUnsigned(t *testing.T) {
+	r := SetupRouterSignature(false)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestSignatureFailedBodyTooLarge(t *testing.T) {
+	r := SetupRouterSignature(true)
+
+	body := strings.Repeat("a", middleware.MaxSignedBody+1)
+	recorder := SignedRequest(r, "/books", body, body, time.Now(), "nonce-1")
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "payload_too_large")
+	assert.Contains(t, recorder.Body.String(), "body request maksimal 1048576 byte")
+}
@@ -0,0 +1,211 @@
+package migrationtest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ilhaamms/library-api/config"
+	libdb "github.com/ilhaamms/library-api/db"
+	"github.com/ilhaamms/library-api/isbn"
+	"github.com/ilhaamms/library-api/migration"
+	"github.com/ilhaamms/library-api/test/testdb"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func SetupMigrator(t *testing.T) (*migration.Migrator, *gorm.DB) {
+	cfg := config.Default()
+	testdb.Configure(&cfg, t.TempDir())
+
+	db, err := config.InitDB(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := testdb.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = migrator.Goto(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return migrator, db
+}
+
+func HasTable(db *gorm.DB, name string) bool {
+	return db.Migrator().HasTable(name)
+}
+
+func TestMigrateUpAndDownAll(t *testing.T) {
+	migrator, db := SetupMigrator(t)
+
+	ran, err := migrator.Up()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, ran)
+
+	version, _ := migrator.Version()
+	assert.Equal(t, ran[len(ran)-1].Version, version)
+	assert.True(t, HasTable(db, "refresh_token"))
+
+	ran, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.Empty(t, ran)
+
+	_, err = migrator.Goto(0)
+	assert.Nil(t, err)
+
+	version, _ = migrator.Version()
+	assert.Equal(t, int64(0), version)
+
+	for _, table := range []string{"author", "user", "book", "book_copy", "loan", "hold", "fine_ledger", "refresh_token", "book_contributor", "publisher", "work", "category", "book_category"} {
+		assert.False(t, HasTable(db, table), table)
+	}
+}
+
+func TestMigrateDownKeepsData(t *testing.T) {
+	migrator, db := SetupMigrator(t)
+
+	_, err := migrator.Up()
+	assert.Nil(t, err)
+
+	db.Exec("INSERT INTO author (name, birth_date) VALUES ('Andrea Hirata', '1967-10-24')")
+	db.Exec("INSERT INTO book (title, isbn, author_id) VALUES ('Laskar Pelangi', '9789793062792', 1)")
+	db.Exec("INSERT INTO ? (username, password) VALUES ('ilhamm.ms', 'rahasia')", clause.Table{Name: "user"})
+	db.Exec("INSERT INTO book_copy (book_id, barcode, branch, acquisition_date, status) VALUES (1, 'LIB-0001', 'pusat', '2024-01-01', 'reserved')")
+	db.Exec("INSERT INTO loan (copy_id, user_id, loaned_at, due_date) VALUES (1, 1, '2024-10-01', '2024-10-15')")
+
+	_, err = migrator.Goto(20241018110000)
+	assert.Nil(t, err)
+	assert.False(t, HasTable(db, "hold"))
+
+	var status string
+	db.Raw("SELECT status FROM book_copy WHERE id = 1").Scan(&status)
+	assert.Equal(t, "available", status)
+
+	var loans int64
+	db.Raw("SELECT COUNT(*) FROM loan").Scan(&loans)
+	assert.Equal(t, int64(1), loans)
+
+	_, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.True(t, HasTable(db, "hold"))
+
+	var role string
+	db.Raw("SELECT role FROM book_contributor WHERE book_id = 1 AND author_id = 1 AND position = 1").Scan(&role)
+	assert.Equal(t, "author", role)
+}
+
+func TestMigrateDownSteps(t *testing.T) {
+	migrator, _ := SetupMigrator(t)
+
+	_, err := migrator.Up()
+	assert.Nil(t, err)
+
+	ran, err := migrator.Down(2)
+	assert.Nil(t, err)
+	assert.Len(t, ran, 2)
+
+	status, err := migrator.Status()
+	assert.Nil(t, err)
+	assert.False(t, status[len(status)-1].Applied)
+	assert.False(t, status[len(status)-2].Applied)
+	assert.True(t, status[len(status)-3].Applied)
+}
+
+func TestMigrateGotoUnknownVersion(t *testing.T) {
+	migrator, _ := SetupMigrator(t)
+
+	_, err := migrator.Goto(1)
+
+	assert.Equal(t, "versi migrasi 1 tidak ditemukan", err.Error())
+}
+
+func TestMigrateCommandGotoFailed(t *testing.T) {
+	migrator, _ := SetupMigrator(t)
+
+	var out bytes.Buffer
+
+	err := migration.Run(migrator, []string{"goto", "1"}, &out)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "versi migrasi 1 tidak ditemukan", err.Error())
+	assert.NotContains(t, out.String(), "versi schema sekarang")
+
+	err = migration.Run(migrator, []string{"goto", "satu"}, &out)
+
+	assert.Equal(t, "versi migrasi harus berupa angka", err.Error())
+}
+
+func TestMigrateCommandUp(t *testing.T) {
+	migrator, _ := SetupMigrator(t)
+
+	var out bytes.Buffer
+
+	err := migration.Run(migrator, []string{"up"}, &out)
+
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "create_table_audit_log")
+	assert.Contains(t, out.String(), "versi schema sekarang")
+}
+
+func TestRequireFTS5(t *testing.T) {
+	_, db := SetupMigrator(t)
+
+	err := libdb.RequireFTS5(db)
+
+	available, probeErr := libdb.FTS5Available(db)
+	assert.Nil(t, probeErr)
+
+	if db.Dialector.Name() == config.DriverSQLite && !available {
+		assert.Equal(t, libdb.ErrNoFTS5, err)
+		assert.Equal(t, "driver sqlite tidak mendukung FTS5, build ulang dengan -tags sqlite_fts5", err.Error())
+		return
+	}
+
+	assert.Nil(t, err)
+}
+
+func TestFTS5AvailableProbesRepeatedly(t *testing.T) {
+	_, db := SetupMigrator(t)
+
+	// the probe table is gone after each probe, whichever connection ran it
+	first, err := libdb.FTS5Available(db)
+	assert.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		available, err := libdb.FTS5Available(db)
+		assert.Nil(t, err)
+		assert.Equal(t, first, available)
+	}
+}
+
+func TestMigrateNormalizesLegacyIsbn(t *testing.T) {
+	migrator, db := SetupMigrator(t)
+
+	_, err := migrator.Goto(20241019160000)
+	assert.Nil(t, err)
+
+	legacy := []string{"978-979-3062-79-2", "0-306-40615-2", "080442957x", "9786020332956"}
+
+	db.Exec("INSERT INTO author (name, birth_date) VALUES ('Andrea Hirata', '1967-10-24')")
+	for i, value := range legacy {
+		db.Exec("INSERT INTO book (title, isbn, author_id) VALUES (?, ?, 1)", fmt.Sprintf("Buku %d", i+1), value)
+	}
+
+	_, err = migrator.Up()
+	assert.Nil(t, err)
+
+	var stored []string
+	db.Raw("SELECT isbn FROM book ORDER BY id").Scan(&stored)
+
+	for i, value := range legacy {
+		canonical, err := isbn.Normalize(value)
+		assert.Nil(t, err)
+		assert.Equal(t, canonical, stored[i], value)
+	}
+}
@@ -0,0 +1,26 @@
+package repomock
+
+import (
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *AuditRepositoryMock) Save(entry response.AuditEntry) error {
+	args := r.Mock.Called(entry)
+
+	return args.Error(0)
+}
+
+func (r *AuditRepositoryMock) FindAll(query request.AuditQuery) ([]response.AuditEntry, int64, error) {
+	args := r.Mock.Called(query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]response.AuditEntry), args.Get(1).(int64), args.Error(2)
+}
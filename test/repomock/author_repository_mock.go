@@ -10,26 +10,27 @@ type AuthorRepositoryMock struct {
 	Mock mock.Mock
 }
 
-func (r *AuthorRepositoryMock) Save(author request.CreateAuthor) error {
+func (r *AuthorRepositoryMock) Save(author request.CreateAuthor) (response.Author, error) {
 	args := r.Mock.Called(author)
 	if args.Get(0) == nil {
-		return nil
+		return response.Author{}, args.Error(1)
 	}
 
-	dataAuthor := args.Get(0).(error)
+	dataAuthor := args.Get(0).(response.Author)
 
-	return dataAuthor
+	return dataAuthor, args.Error(1)
 }
 
-func (r *AuthorRepositoryMock) FindAll() ([]response.Author, error) {
-	args := r.Mock.Called()
+func (r *AuthorRepositoryMock) FindAll(query request.AuthorQuery) ([]response.Author, int64, error) {
+	args := r.Mock.Called(query)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	dataAuthors := args.Get(0).([]response.Author)
+	totalItems := args.Get(1).(int64)
 
-	return dataAuthors, nil
+	return dataAuthors, totalItems, nil
 }
 
 func (r *AuthorRepositoryMock) FindById(id int) (response.Author, error) {
@@ -43,10 +44,21 @@ func (r *AuthorRepositoryMock) FindById(id int) (response.Author, error) {
 	return dataAuthor, nil
 }
 
-func (r *AuthorRepositoryMock) DeleteById(id int) (*response.Author, error) {
-	args := r.Mock.Called(id)
+func (r *AuthorRepositoryMock) DeleteById(id, version int, options request.DeleteAuthor) (*response.AuthorDeletion, error) {
+	args := r.Mock.Called(id, version, options)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, args.Error(1)
+	}
+
+	deletion := args.Get(0).(*response.AuthorDeletion)
+
+	return deletion, nil
+}
+
+func (r *AuthorRepositoryMock) UpdateById(id, version int, author request.UpdateAuthor) (*response.Author, error) {
+	args := r.Mock.Called(id, version, author)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
 
 	dataAuthor := args.Get(0).(*response.Author)
@@ -54,10 +66,10 @@ func (r *AuthorRepositoryMock) DeleteById(id int) (*response.Author, error) {
 	return dataAuthor, nil
 }
 
-func (r *AuthorRepositoryMock) UpdateById(id int, author request.UpdateAuthor) (*response.Author, error) {
-	args := r.Mock.Called(id, author)
+func (r *AuthorRepositoryMock) Restore(id int) (*response.Author, error) {
+	args := r.Mock.Called(id)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, args.Error(1)
 	}
 
 	dataAuthor := args.Get(0).(*response.Author)
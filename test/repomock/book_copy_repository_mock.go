@@ -0,0 +1,79 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type BookCopyRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *BookCopyRepositoryMock) Save(bookCopy request.CreateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error) {
+	args := r.Mock.Called(bookCopy, now, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataCopy := args.Get(0).(*response.BookCopy)
+
+	return dataCopy, nil
+}
+
+func (r *BookCopyRepositoryMock) FindAllByBookId(bookId int) ([]response.BookCopy, error) {
+	args := r.Mock.Called(bookId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataCopies := args.Get(0).([]response.BookCopy)
+
+	return dataCopies, nil
+}
+
+func (r *BookCopyRepositoryMock) FindById(bookId, id int) (response.BookCopy, error) {
+	args := r.Mock.Called(bookId, id)
+	if args.Get(0) == nil {
+		return response.BookCopy{}, args.Error(1)
+	}
+
+	dataCopy := args.Get(0).(response.BookCopy)
+
+	return dataCopy, nil
+}
+
+func (r *BookCopyRepositoryMock) FindByBarcode(barcode string) (response.BookCopy, error) {
+	args := r.Mock.Called(barcode)
+	if args.Get(0) == nil {
+		return response.BookCopy{}, args.Error(1)
+	}
+
+	dataCopy := args.Get(0).(response.BookCopy)
+
+	return dataCopy, nil
+}
+
+func (r *BookCopyRepositoryMock) Update(bookId, id int, bookCopy request.UpdateBookCopy, now, expiresAt time.Time) (*response.BookCopy, error) {
+	args := r.Mock.Called(bookId, id, bookCopy, now, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataCopy := args.Get(0).(*response.BookCopy)
+
+	return dataCopy, nil
+}
+
+func (r *BookCopyRepositoryMock) Delete(bookId, id int) (*response.BookCopy, error) {
+	args := r.Mock.Called(bookId, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataCopy := args.Get(0).(*response.BookCopy)
+
+	return dataCopy, nil
+}
@@ -10,32 +10,33 @@ type BookRepositoryMock struct {
 	Mock mock.Mock
 }
 
-func (r *BookRepositoryMock) Save(book request.CreateBook) error {
+func (r *BookRepositoryMock) Save(book request.CreateBook) (response.Book, error) {
 	args := r.Mock.Called(book)
 	if args.Get(0) == nil {
-		return nil
+		return response.Book{}, args.Error(1)
 	}
 
-	dataBook := args.Get(0).(error)
+	dataBook := args.Get(0).(response.Book)
 
-	return dataBook
+	return dataBook, args.Error(1)
 }
 
-func (r *BookRepositoryMock) FindAll() ([]response.Book, error) {
-	args := r.Mock.Called()
+func (r *BookRepositoryMock) FindAll(query request.BookQuery) ([]response.Book, int64, error) {
+	args := r.Mock.Called(query)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	dataBooks := args.Get(0).([]response.Book)
+	totalItems := args.Get(1).(int64)
 
-	return dataBooks, nil
+	return dataBooks, totalItems, nil
 }
 
 func (r *BookRepositoryMock) FindById(id int) (response.Book, error) {
 	args := r.Mock.Called(id)
 	if args.Get(0) == nil {
-		return response.Book{}, nil
+		return response.Book{}, args.Error(1)
 	}
 
 	dataBook := args.Get(0).(response.Book)
@@ -43,10 +44,10 @@ func (r *BookRepositoryMock) FindById(id int) (response.Book, error) {
 	return dataBook, nil
 }
 
-func (r *BookRepositoryMock) Delete(id int) (*response.ResultBook, error) {
-	args := r.Mock.Called(id)
+func (r *BookRepositoryMock) Delete(id, version int) (*response.ResultBook, error) {
+	args := r.Mock.Called(id, version)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, args.Error(1)
 	}
 
 	dataBook := args.Get(0).(*response.ResultBook)
@@ -54,10 +55,10 @@ func (r *BookRepositoryMock) Delete(id int) (*response.ResultBook, error) {
 	return dataBook, nil
 }
 
-func (r *BookRepositoryMock) Update(id int, book request.UpdateBook) (*response.ResultBook, error) {
-	args := r.Mock.Called(id, book)
+func (r *BookRepositoryMock) Update(id, version int, book request.UpdateBook) (*response.ResultBook, error) {
+	args := r.Mock.Called(id, version, book)
 	if args.Get(0) == nil {
-		return nil, nil
+		return nil, args.Error(1)
 	}
 
 	dataBook := args.Get(0).(*response.ResultBook)
@@ -75,3 +76,26 @@ func (r *BookRepositoryMock) FindBookByIsbn(isbn string) (response.Book, error)
 
 	return dataBook, nil
 }
+
+func (r *BookRepositoryMock) Search(query request.SearchBook) ([]response.SearchBook, int64, error) {
+	args := r.Mock.Called(query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	dataBooks := args.Get(0).([]response.SearchBook)
+	totalItems := args.Get(1).(int64)
+
+	return dataBooks, totalItems, nil
+}
+
+func (r *BookRepositoryMock) Restore(id int) (*response.ResultBook, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataBook := args.Get(0).(*response.ResultBook)
+
+	return dataBook, nil
+}
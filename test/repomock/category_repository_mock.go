@@ -0,0 +1,74 @@
+package repomock
+
+import (
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type CategoryRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *CategoryRepositoryMock) Save(category request.CreateCategory) (response.Category, error) {
+	args := r.Mock.Called(category)
+	if args.Get(0) == nil {
+		return response.Category{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Category), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) FindAll() ([]response.Category, error) {
+	args := r.Mock.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]response.Category), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) FindById(id int) (response.Category, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return response.Category{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Category), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) FindByName(parentId int, name string) (response.Category, error) {
+	args := r.Mock.Called(parentId, name)
+	if args.Get(0) == nil {
+		return response.Category{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Category), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) Descendants(id int) ([]int, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) UpdateById(id int, category request.UpdateCategory) (*response.Category, error) {
+	args := r.Mock.Called(id, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*response.Category), args.Error(1)
+}
+
+func (r *CategoryRepositoryMock) DeleteById(id int) (*response.Category, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*response.Category), args.Error(1)
+}
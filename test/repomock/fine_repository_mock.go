@@ -0,0 +1,39 @@
+package repomock
+
+import (
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type FineRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *FineRepositoryMock) Save(entry request.FineEntry) (*response.FineEntry, error) {
+	args := r.Mock.Called(entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataFine := args.Get(0).(*response.FineEntry)
+
+	return dataFine, nil
+}
+
+func (r *FineRepositoryMock) FindAllByUserId(userId int) ([]response.FineEntry, error) {
+	args := r.Mock.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataFines := args.Get(0).([]response.FineEntry)
+
+	return dataFines, nil
+}
+
+func (r *FineRepositoryMock) Balance(userId, loanId int) (int64, error) {
+	args := r.Mock.Called(userId, loanId)
+
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,63 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type HoldRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *HoldRepositoryMock) Save(hold request.CreateHold) (*response.Hold, error) {
+	args := r.Mock.Called(hold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataHold := args.Get(0).(*response.Hold)
+
+	return dataHold, nil
+}
+
+func (r *HoldRepositoryMock) FindById(id int) (response.Hold, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return response.Hold{}, args.Error(1)
+	}
+
+	dataHold := args.Get(0).(response.Hold)
+
+	return dataHold, nil
+}
+
+func (r *HoldRepositoryMock) FindAllByUserId(userId int) ([]response.Hold, error) {
+	args := r.Mock.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataHolds := args.Get(0).([]response.Hold)
+
+	return dataHolds, nil
+}
+
+func (r *HoldRepositoryMock) Cancel(id int, now, expiresAt time.Time) (*response.Hold, error) {
+	args := r.Mock.Called(id, now, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataHold := args.Get(0).(*response.Hold)
+
+	return dataHold, nil
+}
+
+func (r *HoldRepositoryMock) Expire(now, expiresAt time.Time) (int64, error) {
+	args := r.Mock.Called(now, expiresAt)
+
+	return args.Get(0).(int64), args.Error(1)
+}
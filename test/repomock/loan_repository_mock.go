@@ -0,0 +1,68 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type LoanRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *LoanRepositoryMock) Checkout(loan request.CreateLoan) (*response.Loan, error) {
+	args := r.Mock.Called(loan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataLoan := args.Get(0).(*response.Loan)
+
+	return dataLoan, nil
+}
+
+func (r *LoanRepositoryMock) FindById(id int) (response.Loan, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return response.Loan{}, args.Error(1)
+	}
+
+	dataLoan := args.Get(0).(response.Loan)
+
+	return dataLoan, nil
+}
+
+func (r *LoanRepositoryMock) FindAllByUserId(userId int) ([]response.Loan, error) {
+	args := r.Mock.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataLoans := args.Get(0).([]response.Loan)
+
+	return dataLoans, nil
+}
+
+func (r *LoanRepositoryMock) Return(id int, loan request.ReturnLoan) (*response.Loan, error) {
+	args := r.Mock.Called(id, loan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataLoan := args.Get(0).(*response.Loan)
+
+	return dataLoan, nil
+}
+
+func (r *LoanRepositoryMock) Renew(id int, dueDate time.Time, maxRenewals int) (*response.Loan, error) {
+	args := r.Mock.Called(id, dueDate, maxRenewals)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	dataLoan := args.Get(0).(*response.Loan)
+
+	return dataLoan, nil
+}
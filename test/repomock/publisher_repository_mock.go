@@ -0,0 +1,65 @@
+package repomock
+
+import (
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type PublisherRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *PublisherRepositoryMock) Save(publisher request.CreatePublisher) (response.Publisher, error) {
+	args := r.Mock.Called(publisher)
+	if args.Get(0) == nil {
+		return response.Publisher{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Publisher), args.Error(1)
+}
+
+func (r *PublisherRepositoryMock) FindAll(query request.PublisherQuery) ([]response.Publisher, int64, error) {
+	args := r.Mock.Called(query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]response.Publisher), args.Get(1).(int64), args.Error(2)
+}
+
+func (r *PublisherRepositoryMock) FindById(id int) (response.Publisher, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return response.Publisher{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Publisher), args.Error(1)
+}
+
+func (r *PublisherRepositoryMock) FindByName(name string) (response.Publisher, error) {
+	args := r.Mock.Called(name)
+	if args.Get(0) == nil {
+		return response.Publisher{}, args.Error(1)
+	}
+
+	return args.Get(0).(response.Publisher), args.Error(1)
+}
+
+func (r *PublisherRepositoryMock) DeleteById(id int) (*response.Publisher, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*response.Publisher), args.Error(1)
+}
+
+func (r *PublisherRepositoryMock) UpdateById(id int, publisher request.UpdatePublisher) (*response.Publisher, error) {
+	args := r.Mock.Called(id, publisher)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*response.Publisher), args.Error(1)
+}
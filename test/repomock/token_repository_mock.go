@@ -0,0 +1,54 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type TokenRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *TokenRepositoryMock) Save(token request.RefreshToken) error {
+	args := r.Mock.Called(token)
+
+	return args.Error(0)
+}
+
+func (r *TokenRepositoryMock) FindByHash(tokenHash string) (response.RefreshToken, error) {
+	args := r.Mock.Called(tokenHash)
+	if args.Get(0) == nil {
+		return response.RefreshToken{}, args.Error(1)
+	}
+
+	dataToken := args.Get(0).(response.RefreshToken)
+
+	return dataToken, nil
+}
+
+func (r *TokenRepositoryMock) Rotate(id int, next request.RefreshToken, now time.Time) error {
+	args := r.Mock.Called(id, next, now)
+
+	return args.Error(0)
+}
+
+func (r *TokenRepositoryMock) RevokeFamily(familyId string, now time.Time) error {
+	args := r.Mock.Called(familyId, now)
+
+	return args.Error(0)
+}
+
+func (r *TokenRepositoryMock) RevokeAccess(jti string, expiresAt time.Time) error {
+	args := r.Mock.Called(jti, expiresAt)
+
+	return args.Error(0)
+}
+
+func (r *TokenRepositoryMock) IsRevoked(jti string) (bool, error) {
+	args := r.Mock.Called(jti)
+
+	return args.Bool(0), args.Error(1)
+}
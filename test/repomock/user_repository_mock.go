@@ -53,3 +53,43 @@ func (r *UserRepositoryMock) GetUserByUsername(username string) (request.User, e
 
 	return dataUser, nil
 }
+
+func (r *UserRepositoryMock) FindByUsername(username string) (response.User, error) {
+	args := r.Mock.Called(username)
+	if args.Get(0) == nil {
+		return response.User{}, args.Error(1)
+	}
+
+	dataUser := args.Get(0).(response.User)
+
+	return dataUser, nil
+}
+
+func (r *UserRepositoryMock) FindById(id int) (response.User, error) {
+	args := r.Mock.Called(id)
+	if args.Get(0) == nil {
+		return response.User{}, args.Error(1)
+	}
+
+	dataUser := args.Get(0).(response.User)
+
+	return dataUser, nil
+}
+
+func (r *UserRepositoryMock) UpdateCategory(id int, category string) error {
+	args := r.Mock.Called(id, category)
+
+	return args.Error(0)
+}
+
+func (r *UserRepositoryMock) UpdateRole(id int, role string) error {
+	args := r.Mock.Called(id, role)
+
+	return args.Error(0)
+}
+
+func (r *UserRepositoryMock) CountByRole(role string) (int64, error) {
+	args := r.Mock.Called(role)
+
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -5,8 +5,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/repository"
 	"github.com/ilhaamms/library-api/service"
 	"github.com/ilhaamms/library-api/test/repomock"
 	"github.com/stretchr/testify/assert"
@@ -23,14 +27,14 @@ func TestAuthorService_SaveFailedNameEmpty(t *testing.T) {
 		Birthdate: "2000-01-01",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(nil)
+	authorRepositoryMock.Mock.On("Save", author).Return(response.Author{}, nil)
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "", author.Name)
-	assert.Equal(t, "nama dan tanggal lahir tidak boleh kosong", err.Error())
+	assert.Equal(t, map[string]string{"name": "name wajib diisi"}, FieldMessages(err))
 	assert.Equal(t, "2000-01-01", author.Birthdate)
 }
 
@@ -44,14 +48,14 @@ func TestAuthorService_SaveFailedBirthdateEmpty(t *testing.T) {
 		Birthdate: "",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(nil)
+	authorRepositoryMock.Mock.On("Save", author).Return(response.Author{}, nil)
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "", author.Birthdate)
-	assert.Equal(t, "nama dan tanggal lahir tidak boleh kosong", err.Error())
+	assert.Equal(t, map[string]string{"birth_date": "birth_date wajib diisi"}, FieldMessages(err))
 }
 
 func TestAuthorService_SaveFailedNameLength(t *testing.T) {
@@ -64,14 +68,14 @@ func TestAuthorService_SaveFailedNameLength(t *testing.T) {
 		Birthdate: "2000-01-01",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(nil)
+	authorRepositoryMock.Mock.On("Save", author).Return(response.Author{}, nil)
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "Il", author.Name)
-	assert.Equal(t, "nama minimal 3 karakter", err.Error())
+	assert.Equal(t, map[string]string{"name": "name minimal 3 karakter"}, FieldMessages(err))
 	assert.Equal(t, "2000-01-01", author.Birthdate)
 }
 
@@ -85,17 +89,35 @@ func TestAuthorService_SaveFailedBirthdateFormat(t *testing.T) {
 		Birthdate: "2000-01-",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(nil)
+	authorRepositoryMock.Mock.On("Save", author).Return(response.Author{}, nil)
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "Ilhaam", author.Name)
-	assert.Equal(t, "format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid", err.Error())
+	assert.Equal(t, map[string]string{"birth_date": "birth_date harus berupa tanggal YYYY-MM-DD yang tidak melebihi hari ini"}, FieldMessages(err))
 	assert.Equal(t, "2000-01-", author.Birthdate)
 }
 
+func TestAuthorService_SaveFailedBirthdateFuture(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	author := request.CreateAuthor{
+		Name:      "Il",
+		Birthdate: time.Now().AddDate(0, 0, 2).Format("2006-01-02"),
+	}
+
+	result, err := authorService.Save(data.Actor{}, author)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	assert.Equal(t, map[string]string{"name": "name minimal 3 karakter", "birth_date": "birth_date harus berupa tanggal YYYY-MM-DD yang tidak melebihi hari ini"}, FieldMessages(err))
+	authorRepositoryMock.Mock.AssertNotCalled(t, "Save", author)
+}
+
 func TestAuthorService_SaveFailedSaveAuthor(t *testing.T) {
 
 	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
@@ -106,9 +128,9 @@ func TestAuthorService_SaveFailedSaveAuthor(t *testing.T) {
 		Birthdate: "2000-01-01",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(errors.New("gagal menyimpan data author"))
+	authorRepositoryMock.Mock.On("Save", author).Return(nil, errors.New("gagal menyimpan data author"))
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -127,9 +149,9 @@ func TestAuthorService_SaveSuccess(t *testing.T) {
 		Birthdate: "2000-06-01",
 	}
 
-	authorRepositoryMock.Mock.On("Save", author).Return(nil)
+	authorRepositoryMock.Mock.On("Save", author).Return(response.Author{}, nil)
 
-	result, err := authorService.Save(author)
+	result, err := authorService.Save(data.Actor{}, author)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
@@ -142,9 +164,9 @@ func TestAuthorService_FindAllDataEmpty(t *testing.T) {
 	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
 	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
 
-	authorRepositoryMock.Mock.On("FindAll").Return(nil, errors.New("data author kosong"))
+	authorRepositoryMock.Mock.On("FindAll", mock.Anything).Return(nil, int64(0), errors.New("data author kosong"))
 
-	result, _, err := authorService.FindAll(1, 10)
+	result, _, err := authorService.FindAll(request.AuthorQuery{Page: 1, Limit: 10})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -167,9 +189,9 @@ func TestAuthorService_FindAllSuccess(t *testing.T) {
 		},
 	}
 
-	authorRepositoryMock.Mock.On("FindAll").Return(authors, nil)
+	authorRepositoryMock.Mock.On("FindAll", mock.Anything).Return(authors, int64(1), nil)
 
-	result, _, err := authorService.FindAll(1, 10)
+	result, _, err := authorService.FindAll(request.AuthorQuery{Page: 1, Limit: 10})
 
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
@@ -193,15 +215,96 @@ func TestAuthorService_FindAllPageFailed(t *testing.T) {
 		},
 	}
 
-	authorRepositoryMock.Mock.On("FindAll").Return(authors, nil)
+	authorRepositoryMock.Mock.On("FindAll", mock.Anything).Return(authors, int64(1), nil)
 
-	result, _, err := authorService.FindAll(2, 10)
+	result, _, err := authorService.FindAll(request.AuthorQuery{Page: 2, Limit: 10})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "page sudah melebihi total page", err.Error())
 }
 
+func TestAuthorService_FindAllPagination(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	birthDate, _ := time.Parse("2006-01-02", "2000-06-11")
+
+	authors := []response.Author{
+		{
+			ID:        3,
+			Name:      "Ilhaam Sidiq",
+			BirthDate: birthDate,
+		},
+	}
+
+	query := request.AuthorQuery{Page: 2, Limit: 2, Sort: "name", Order: "desc", Name: "ilhaam"}
+	authorRepositoryMock.Mock.On("FindAll", query).Return(authors, int64(3), nil)
+
+	result, pagination, err := authorService.FindAll(request.AuthorQuery{Page: 2, Limit: 2, Sort: "NAME", Order: "DESC", Name: "ilhaam"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 2, pagination.CurrentPage)
+	assert.Equal(t, 2, pagination.TotalPage)
+	assert.Equal(t, 2, pagination.Limit)
+	assert.Equal(t, int64(3), pagination.TotalItems)
+}
+
+func TestAuthorService_FindAllDefaultPage(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	authors := []response.Author{{ID: 1, Name: "Ilhaam Sidiq"}}
+
+	query := request.AuthorQuery{Page: 1, Limit: 10, Sort: "id", Order: "asc"}
+	authorRepositoryMock.Mock.On("FindAll", query).Return(authors, int64(1), nil)
+
+	_, pagination, err := authorService.FindAll(request.AuthorQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pagination.CurrentPage)
+	assert.Equal(t, 10, pagination.Limit)
+}
+
+func TestAuthorService_FindAllFailedSort(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	result, _, err := authorService.FindAll(request.AuthorQuery{Sort: "password"})
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "sort hanya boleh salah satu dari : id, name, birth_date", err.Error())
+}
+
+func TestAuthorService_FindAllFailedOrder(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	result, _, err := authorService.FindAll(request.AuthorQuery{Order: "random"})
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "order hanya boleh asc atau desc", err.Error())
+}
+
+func TestAuthorService_FindAllFailedBirthDateRange(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	result, _, err := authorService.FindAll(request.AuthorQuery{BirthDateFrom: "2000-13-01"})
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "format birth_date_from salah, format harus YYYY-MM-DD", err.Error())
+}
+
 func TestAuthorService_FindByIdFailedIdInvalid(t *testing.T) {
 
 	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
@@ -246,9 +349,9 @@ func TestAuthorService_DeleteByIdFailedIdInvalid(t *testing.T) {
 	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
 	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
 
-	authorRepositoryMock.Mock.On("DeleteById", 0).Return(response.Author{}, nil)
+	authorRepositoryMock.Mock.On("DeleteById", 0, 0, request.DeleteAuthor{}).Return(&response.AuthorDeletion{}, nil)
 
-	result, err := authorService.DeleteById(0)
+	result, err := authorService.DeleteById(data.Actor{}, 0, 0, request.DeleteAuthor{})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -269,9 +372,9 @@ func TestAuthorService_DeleteByIdSuccess(t *testing.T) {
 		BirthDate: birthDate,
 	}
 
-	authorRepositoryMock.Mock.On("DeleteById", 1).Return(&author, nil)
+	authorRepositoryMock.Mock.On("DeleteById", 1, 0, request.DeleteAuthor{Strategy: "restrict"}).Return(&response.AuthorDeletion{Strategy: "restrict", Author: author}, nil)
 
-	result, err := authorService.DeleteById(1)
+	result, err := authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{})
 
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
@@ -290,9 +393,9 @@ func TestAuthorService_UpdateByIdFailedIdInvalid(t *testing.T) {
 		Birthdate: "2000-06-11",
 	}
 
-	authorRepositoryMock.Mock.On("UpdateById", 0, author).Return(response.Author{}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 0, 0, author).Return(response.Author{}, nil)
 
-	result, err := authorService.UpdateById(0, author)
+	result, err := authorService.UpdateById(data.Actor{}, 0, 0, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -308,13 +411,13 @@ func TestAuthorService_UpdateByIdFailedNameLength(t *testing.T) {
 		Birthdate: "2000-06-11",
 	}
 
-	authorRepositoryMock.Mock.On("UpdateById", 1, author).Return(&response.Author{}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 0, author).Return(&response.Author{}, nil)
 
-	result, err := authorService.UpdateById(1, author)
+	result, err := authorService.UpdateById(data.Actor{}, 1, 0, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
-	assert.Equal(t, "harap masukan nama minimal 3 karakter", err.Error())
+	assert.Equal(t, map[string]string{"name": "name minimal 3 karakter"}, FieldMessages(err))
 }
 
 func TestAuthorService_UpdateByIdFailedNameBirthdateEmpty(t *testing.T) {
@@ -326,9 +429,9 @@ func TestAuthorService_UpdateByIdFailedNameBirthdateEmpty(t *testing.T) {
 		Birthdate: "",
 	}
 
-	authorRepositoryMock.Mock.On("UpdateById", 1, author).Return(&response.Author{}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 0, author).Return(&response.Author{}, nil)
 
-	result, err := authorService.UpdateById(1, author)
+	result, err := authorService.UpdateById(data.Actor{}, 1, 0, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -344,11 +447,213 @@ func TestAuthorService_UpdateByIdFormat(t *testing.T) {
 		Birthdate: "2000-06-",
 	}
 
-	authorRepositoryMock.Mock.On("UpdateById", 1, author).Return(&response.Author{}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 0, author).Return(&response.Author{}, nil)
 
-	result, err := authorService.UpdateById(1, author)
+	result, err := authorService.UpdateById(data.Actor{}, 1, 0, author)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
-	assert.Equal(t, "format bithdate salah, format harus YYYY-MM-DD atau tanggal, bulan anda tidak valid", err.Error())
+	assert.Equal(t, map[string]string{"birth_date": "birth_date harus berupa tanggal YYYY-MM-DD yang tidak melebihi hari ini"}, FieldMessages(err))
+}
+
+func TestAuthorService_UpdateByIdFailedAllFields(t *testing.T) {
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	author := request.UpdateAuthor{
+		Name:      "Il",
+		Birthdate: "2999-01-01",
+	}
+
+	result, err := authorService.UpdateById(data.Actor{}, 1, 0, author)
+
+	// every field is reported at once, a birth date in the future included
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	assert.Equal(t, map[string]string{"name": "name minimal 3 karakter", "birth_date": "birth_date harus berupa tanggal YYYY-MM-DD yang tidak melebihi hari ini"}, FieldMessages(err))
+	authorRepositoryMock.Mock.AssertNotCalled(t, "UpdateById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// FieldMessages returns the field errors of a validation error by field,
+// fields breaking several rules list their messages comma separated.
+func FieldMessages(err error) map[string]string {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return nil
+	}
+
+	messages := map[string]string{}
+	for _, field := range appErr.Details(i18n.Default) {
+		if messages[field.Field] != "" {
+			messages[field.Field] += ", "
+		}
+
+		messages[field.Field] += field.Message
+	}
+
+	return messages
+}
+
+func TestAuthorService_PatchById(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	birthDate := time.Date(1967, 10, 24, 0, 0, 0, 0, time.UTC)
+
+	authorRepositoryMock.Mock.On("FindById", 1).Return(response.Author{ID: 1, Name: "Andrea Hirata", BirthDate: birthDate, Version: 1}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 1, request.UpdateAuthor{Name: "Andrea Hirata Seman Said Harun", Birthdate: "1967-10-24"}).
+		Return(&response.Author{ID: 1, Name: "Andrea Hirata Seman Said Harun", BirthDate: birthDate}, nil)
+
+	author, err := authorService.PatchById(data.Actor{}, 1, 0, []byte(`{"name": "Andrea Hirata Seman Said Harun"}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, author.ID)
+	assert.Equal(t, "Andrea Hirata Seman Said Harun", author.Name)
+}
+
+func TestAuthorService_PatchByIdFailedClearName(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	authorRepositoryMock.Mock.On("FindById", 1).Return(response.Author{ID: 1, Name: "Andrea Hirata", BirthDate: time.Date(1967, 10, 24, 0, 0, 0, 0, time.UTC)}, nil)
+
+	author, err := authorService.PatchById(data.Actor{}, 1, 0, []byte(`{"name": null}`))
+
+	assert.Nil(t, author)
+	assert.Equal(t, map[string]string{"name": "name wajib diisi"}, FieldMessages(err))
+	authorRepositoryMock.Mock.AssertNotCalled(t, "UpdateById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorService_DeleteByIdFailedVersionMismatch(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	authorRepositoryMock.Mock.On("DeleteById", 1, 2, request.DeleteAuthor{Strategy: "restrict"}).Return(nil, repository.ErrVersionMismatch)
+
+	author, err := authorService.DeleteById(data.Actor{}, 1, 2, request.DeleteAuthor{})
+
+	assert.Nil(t, author)
+	assert.True(t, errors.Is(err, apperror.ErrPrecondition))
+}
+
+func TestAuthorService_DeleteByIdFailedHasBooks(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	books := []response.AuthorBookRef{{Id: 1, Title: "Laskar Pelangi", Isbn: "9789793062792"}}
+	authorRepositoryMock.Mock.On("DeleteById", 1, 0, request.DeleteAuthor{Strategy: "restrict"}).Return(nil, repository.ErrAuthorHasBooks.WithData(books))
+
+	author, err := authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{Strategy: "restrict", To: 2})
+
+	assert.Nil(t, author)
+	assert.True(t, errors.Is(err, repository.ErrAuthorHasBooks))
+	assert.Equal(t, books, apperror.From(err).Data)
+}
+
+func TestAuthorService_DeleteByIdFailedStrategyInvalid(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	author, err := authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{Strategy: "orphan"})
+
+	assert.Nil(t, author)
+	assert.Equal(t, map[string]string{"strategy": "strategy hanya boleh salah satu dari : restrict, cascade, reassign"}, FieldMessages(err))
+	authorRepositoryMock.Mock.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorService_DeleteByIdFailedReassignTarget(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock}
+
+	author, err := authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{Strategy: "reassign"})
+
+	assert.Nil(t, author)
+	assert.Equal(t, map[string]string{"to": "to wajib diisi dengan id author tujuan jika strategy reassign"}, FieldMessages(err))
+
+	author, err = authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{Strategy: "reassign", To: 1})
+
+	assert.Nil(t, author)
+	assert.Equal(t, map[string]string{"to": "author tujuan tidak boleh sama dengan author yang dihapus"}, FieldMessages(err))
+	authorRepositoryMock.Mock.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorService_UpdateByIdRecordsAudit(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var auditRepositoryMock = repomock.AuditRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock, AuditRepository: &auditRepositoryMock}
+
+	birthDate := time.Date(1967, 10, 24, 0, 0, 0, 0, time.UTC)
+	author := request.UpdateAuthor{Name: "Andrea Hirata Seman Said Harun"}
+
+	authorRepositoryMock.Mock.On("FindById", 1).Return(response.Author{ID: 1, Name: "Andrea Hirata", BirthDate: birthDate, Version: 1}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 0, author).Return(&response.Author{ID: 1, Name: "Andrea Hirata Seman Said Harun", BirthDate: birthDate, Version: 2}, nil)
+
+	// only the fields that changed are kept
+	auditRepositoryMock.Mock.On("Save", mock.MatchedBy(func(entry response.AuditEntry) bool {
+		return entry.Actor == "staff.one" && entry.ActorRole == "librarian" && entry.RequestId == "req-1" &&
+			entry.Entity == "author" && entry.EntityId == 1 && entry.Action == "update" &&
+			assert.ObjectsAreEqual(map[string]response.AuditChange{
+				"name":    {Before: "Andrea Hirata", After: "Andrea Hirata Seman Said Harun"},
+				"version": {Before: float64(1), After: float64(2)},
+			}, entry.Changes)
+	})).Return(nil)
+
+	result, err := authorService.UpdateById(data.Actor{Username: "staff.one", Role: "librarian", RequestId: "req-1"}, 1, 0, author)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Version)
+	auditRepositoryMock.Mock.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestAuthorService_UpdateByIdFailedAudit(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var auditRepositoryMock = repomock.AuditRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock, AuditRepository: &auditRepositoryMock}
+
+	birthDate := time.Date(1967, 10, 24, 0, 0, 0, 0, time.UTC)
+	author := request.UpdateAuthor{Name: "Andrea Hirata Seman Said Harun"}
+
+	authorRepositoryMock.Mock.On("FindById", 1).Return(response.Author{ID: 1, Name: "Andrea Hirata", BirthDate: birthDate, Version: 1}, nil)
+	authorRepositoryMock.Mock.On("UpdateById", 1, 0, author).Return(&response.Author{ID: 1, Name: "Andrea Hirata Seman Said Harun", BirthDate: birthDate, Version: 2}, nil)
+	auditRepositoryMock.Mock.On("Save", mock.Anything).Return(errors.New("audit_log is append only"))
+
+	result, err := authorService.UpdateById(data.Actor{}, 1, 0, author)
+
+	// the update is rolled back with the entry that could not be recorded
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.Internal("audit.record_failed", nil)))
+}
+
+func TestAuthorService_DeleteByIdCascadeRecordsAudit(t *testing.T) {
+
+	var authorRepositoryMock = repomock.AuthorRepositoryMock{Mock: mock.Mock{}}
+	var auditRepositoryMock = repomock.AuditRepositoryMock{Mock: mock.Mock{}}
+	var authorService = service.AuthorServices{AuthorRepo: &authorRepositoryMock, AuditRepository: &auditRepositoryMock}
+
+	authorRepositoryMock.Mock.On("DeleteById", 1, 0, request.DeleteAuthor{Strategy: "cascade"}).Return(&response.AuthorDeletion{
+		Strategy:     "cascade",
+		Author:       response.Author{ID: 1, Name: "Andrea Hirata"},
+		DeletedBooks: []response.AuthorBookRef{{Id: 4, Title: "Laskar Pelangi", Isbn: "9789793062792"}},
+	}, nil)
+
+	auditRepositoryMock.Mock.On("Save", mock.Anything).Return(nil)
+
+	_, err := authorService.DeleteById(data.Actor{}, 1, 0, request.DeleteAuthor{Strategy: "cascade"})
+
+	assert.Nil(t, err)
+	auditRepositoryMock.Mock.AssertNumberOfCalls(t, "Save", 2)
+
+	book := auditRepositoryMock.Mock.Calls[1].Arguments.Get(0).(response.AuditEntry)
+	assert.Equal(t, "book", book.Entity)
+	assert.Equal(t, 4, book.EntityId)
+	assert.Equal(t, "delete", book.Action)
+	assert.Equal(t, response.AuditChange{Before: "Laskar Pelangi"}, book.Changes["title"])
 }
@@ -0,0 +1,227 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestBookCopyService_SaveFailedBarcodeEmpty(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	result, err := bookCopyService.Save(1, request.CreateBookCopy{Branch: "Pusat"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "barcode dan branch tidak boleh kosong", err.Error())
+}
+
+func TestBookCopyService_SaveFailedStatusInvalid(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	result, err := bookCopyService.Save(1, request.CreateBookCopy{Barcode: "LIB-0001", Branch: "Pusat", Status: "borrowed"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "status hanya boleh salah satu dari : available, lost, in_repair", err.Error())
+}
+
+func TestBookCopyService_SaveFailedAcquisitionDateFuture(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	result, err := bookCopyService.Save(1, request.CreateBookCopy{Barcode: "LIB-0001", Branch: "Pusat", AcquisitionDate: "2999-01-01"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "acquisition_date tidak boleh melebihi tanggal hari ini", err.Error())
+}
+
+func TestBookCopyService_SaveFailedBookNotFound(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 99).Return(nil, errors.New("record not found"))
+
+	result, err := bookCopyService.Save(99, request.CreateBookCopy{Barcode: "LIB-0001", Branch: "Pusat"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book tidak ditemukan", err.Error())
+}
+
+func TestBookCopyService_SaveFailedBarcodeExist(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1}, nil)
+	bookCopyRepositoryMock.Mock.On("FindByBarcode", "LIB-0001").Return(response.BookCopy{Id: 4, Barcode: "LIB-0001"}, nil)
+
+	result, err := bookCopyService.Save(1, request.CreateBookCopy{Barcode: "LIB-0001", Branch: "Pusat"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "barcode sudah digunakan oleh eksemplar lain", err.Error())
+}
+
+func TestBookCopyService_SaveSuccess(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock, PickupDays: 3}
+
+	var now time.Time
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1}, nil)
+	bookCopyRepositoryMock.Mock.On("FindByBarcode", "LIB-0001").Return(nil, errors.New("record not found"))
+	bookCopyRepositoryMock.Mock.On("Save", mock.MatchedBy(func(c request.CreateBookCopy) bool {
+		return c.BookId == 1 && c.Status == "available" && c.Condition == "good" && c.AcquisitionDate != ""
+	}), mock.MatchedBy(func(at time.Time) bool {
+		now = at
+		return true
+	}), mock.MatchedBy(func(expiresAt time.Time) bool {
+		// an available copy may go straight to a waiting hold
+		return expiresAt.Equal(now.AddDate(0, 0, 3))
+	})).Return(&response.BookCopy{Id: 1, BookId: 1, Barcode: "LIB-0001", Branch: "Pusat", Status: "available"}, nil)
+
+	result, err := bookCopyService.Save(1, request.CreateBookCopy{Barcode: "LIB-0001", Branch: "Pusat"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Id)
+	assert.Equal(t, "available", result.Status)
+}
+
+func TestBookCopyService_FindAllDataEmpty(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1}, nil)
+	bookCopyRepositoryMock.Mock.On("FindAllByBookId", 1).Return([]response.BookCopy{}, nil)
+
+	result, err := bookCopyService.FindAllByBookId(1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "data eksemplar kosong", err.Error())
+}
+
+func TestBookCopyService_UpdateFailedDataEmpty(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	result, err := bookCopyService.Update(1, 1, request.UpdateBookCopy{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "data eksemplar yang diupdate tidak boleh kosong", err.Error())
+}
+
+func TestBookCopyService_UpdateSameBarcodeSuccess(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookCopy := request.UpdateBookCopy{Barcode: "LIB-0001", Status: "in_repair"}
+
+	bookCopyRepositoryMock.Mock.On("FindByBarcode", "LIB-0001").Return(response.BookCopy{Id: 1, Barcode: "LIB-0001"}, nil)
+	bookCopyRepositoryMock.Mock.On("Update", 1, 1, bookCopy, mock.Anything, mock.Anything).Return(&response.BookCopy{Id: 1, Barcode: "LIB-0001", Status: "in_repair"}, nil)
+
+	result, err := bookCopyService.Update(1, 1, bookCopy)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "in_repair", result.Status)
+}
+
+func TestBookCopyService_UpdateFailedCirculationStatus(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	for _, status := range []string{"on_loan", "reserved"} {
+		result, err := bookCopyService.Update(1, 1, request.UpdateBookCopy{Status: status})
+
+		assert.Nil(t, result)
+		assert.Equal(t, "status hanya boleh salah satu dari : available, lost, in_repair", err.Error())
+	}
+
+	bookCopyRepositoryMock.Mock.AssertNotCalled(t, "Update")
+}
+
+func TestBookCopyService_UpdateFailedCopyInCirculation(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookCopy := request.UpdateBookCopy{Status: "lost"}
+
+	bookCopyRepositoryMock.Mock.On("Update", 1, 1, bookCopy, mock.Anything, mock.Anything).Return(nil, repository.ErrCopyInCirculation)
+
+	result, err := bookCopyService.Update(1, 1, bookCopy)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrCopyInCirculation)
+}
+
+func TestBookCopyService_DeleteFailedNotFound(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookCopyRepositoryMock.Mock.On("Delete", 1, 9).Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := bookCopyService.DeleteById(1, 9)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "gagal menghapus data eksemplar, eksemplar tidak ditemukan", err.Error())
+}
+
+func TestBookCopyService_DeleteFailedNotOnShelf(t *testing.T) {
+
+	var bookCopyRepositoryMock = repomock.BookCopyRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookCopyService = service.BookCopyServices{BookCopyRepository: &bookCopyRepositoryMock, BookRepository: &bookRepositoryMock}
+
+	bookCopyRepositoryMock.Mock.On("Delete", 1, 1).Return(nil, repository.ErrCopyNotOnShelf)
+	bookCopyRepositoryMock.Mock.On("Delete", 1, 2).Return(nil, errors.New("FOREIGN KEY constraint failed"))
+
+	result, err := bookCopyService.DeleteById(1, 1)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrCopyNotOnShelf)
+
+	result, err = bookCopyService.DeleteById(1, 2)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperror.ErrInternal)
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
 	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
 	"github.com/ilhaamms/library-api/service"
 	"github.com/ilhaamms/library-api/test/repomock"
 	"github.com/stretchr/testify/assert"
@@ -23,10 +26,10 @@ func TestBookService_SaveFailedAllDataRequestEmpty(t *testing.T) {
 		AuthorId: 0,
 	}
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "judul, isbn, dan author_id tidak boleh kosong", err.Error())
+	assert.Equal(t, map[string]string{"title": "title wajib diisi", "isbn": "isbn wajib diisi", "author_id": "author_id wajib diisi jika contributors kosong"}, FieldMessages(err))
 
 }
 
@@ -37,14 +40,14 @@ func TestBookService_SaveFailedMinTitle(t *testing.T) {
 
 	book := request.CreateBook{
 		Title:    "il",
-		Isbn:     "1234567890",
+		Isbn:     "9780306406157",
 		AuthorId: 1,
 	}
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "judul minimal 3 karakter", err.Error())
+	assert.Equal(t, map[string]string{"title": "title minimal 3 karakter"}, FieldMessages(err))
 
 }
 
@@ -59,10 +62,10 @@ func TestBookService_SaveFailedMinIsbn(t *testing.T) {
 		AuthorId: 1,
 	}
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "isbn minimal 10 karakter", err.Error())
+	assert.Equal(t, map[string]string{"isbn": "isbn harus terdiri dari 10 atau 13 digit"}, FieldMessages(err))
 
 }
 
@@ -77,10 +80,10 @@ func TestBookService_SaveFailedMaxIsbn(t *testing.T) {
 		AuthorId: 1,
 	}
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "isbn maksimal 13 karakter", err.Error())
+	assert.Equal(t, map[string]string{"isbn": "isbn harus terdiri dari 10 atau 13 digit"}, FieldMessages(err))
 
 }
 
@@ -91,14 +94,14 @@ func TestBookService_SaveFailedAuthorIdNegative(t *testing.T) {
 
 	book := request.CreateBook{
 		Title:    "ilham",
-		Isbn:     "1234567890",
+		Isbn:     "9780306406157",
 		AuthorId: -1,
 	}
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "author_id tidak boleh negatif", err.Error())
+	assert.Equal(t, map[string]string{"author_id": "author_id tidak boleh kurang dari 0"}, FieldMessages(err))
 
 }
 
@@ -109,27 +112,61 @@ func TestBookService_SaveFailedIsbnExist(t *testing.T) {
 
 	book := request.CreateBook{
 		Title:    "ilham",
-		Isbn:     "1234567890",
+		Isbn:     "9780306406157",
 		AuthorId: 1,
 	}
 
 	bookRepositoryMock.Mock.On("FindBookByIsbn", book.Isbn).Return(nil, errors.New("isbn sudah digunakan oleh buku lain"))
 
-	_, err := bookService.Save(book)
+	_, err := bookService.Save(data.Actor{}, book)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "isbn sudah digunakan oleh buku lain", err.Error())
 
 }
 
+func TestBookService_SaveFailedContributorAuthorIdEmpty(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book := request.CreateBook{
+		Title:        "ilham",
+		Isbn:         "9780306406157",
+		Contributors: []request.BookContributor{{AuthorId: 1}, {Role: "editor"}},
+	}
+
+	_, err := bookService.Save(data.Actor{}, book)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]string{"contributors[1].author_id": "contributors[1].author_id harus lebih dari 0"}, FieldMessages(err))
+}
+
+func TestBookService_SaveFailedContributorDuplicate(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book := request.CreateBook{
+		Title:        "ilham",
+		Isbn:         "9780306406157",
+		Contributors: []request.BookContributor{{AuthorId: 1}, {AuthorId: 1, Role: "author"}},
+	}
+
+	_, err := bookService.Save(data.Actor{}, book)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "contributor dengan author_id dan role yang sama tidak boleh duplikat", err.Error())
+}
+
 func TestBookService_FindAllFailed(t *testing.T) {
 
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	bookRepositoryMock.Mock.On("FindAll").Return(nil, errors.New("gagal mengambil data book"))
+	bookRepositoryMock.Mock.On("FindAll", mock.Anything).Return(nil, int64(0), errors.New("gagal mengambil data book"))
 
-	book, _, err := bookService.FindAll(1, 10)
+	book, _, err := bookService.FindAll(request.BookQuery{Page: 1, Limit: 10})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -140,14 +177,67 @@ func TestBookService_PageMoreThanTotalPages(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	bookRepositoryMock.Mock.On("FindAll").Return([]response.Book{}, nil)
+	bookRepositoryMock.Mock.On("FindAll", mock.Anything).Return([]response.Book{}, int64(0), nil)
 
-	book, _, err := bookService.FindAll(3, 10)
+	book, _, err := bookService.FindAll(request.BookQuery{Page: 3, Limit: 10})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
 }
 
+func TestBookService_FindAllSuccess(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	books := []response.Book{
+		{
+			Id:         11,
+			Title:      "Belajar Golang",
+			Isbn:       "9780306406157",
+			AuthorId:   1,
+			AuthorName: "Ilham Sidiq",
+			BirthDate:  "1996-01-01",
+		},
+	}
+
+	query := request.BookQuery{Page: 2, Limit: 10, Sort: "title", Order: "asc", Title: "golang", AuthorId: 1}
+	bookRepositoryMock.Mock.On("FindAll", query).Return(books, int64(11), nil)
+
+	result, pagination, err := bookService.FindAll(request.BookQuery{Page: 2, Sort: "title", Title: "golang", AuthorId: 1})
+
+	assert.Nil(t, err)
+	assert.Len(t, *result, 1)
+	assert.Equal(t, "Belajar Golang", (*result)[0].Title)
+	assert.Equal(t, "Ilham Sidiq", (*result)[0].AuthorBook.Name)
+	assert.Equal(t, 2, pagination.TotalPage)
+	assert.Equal(t, int64(11), pagination.TotalItems)
+}
+
+func TestBookService_FindAllFailedSort(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book, _, err := bookService.FindAll(request.BookQuery{Sort: "author_name; DROP TABLE book"})
+
+	assert.Nil(t, book)
+	assert.NotNil(t, err)
+	assert.Equal(t, "sort hanya boleh salah satu dari : id, title, isbn, author_id, publication_year", err.Error())
+}
+
+func TestBookService_FindAllFailedLimit(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book, _, err := bookService.FindAll(request.BookQuery{Limit: 1000})
+
+	assert.Nil(t, book)
+	assert.NotNil(t, err)
+	assert.Equal(t, "limit maksimal 100", err.Error())
+}
+
 func TestBookService_InvalidId(t *testing.T) {
 
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
@@ -164,7 +254,7 @@ func TestBookService_DeleteFailedIdNegative(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.DeleteById(-1)
+	book, err := bookService.DeleteById(data.Actor{}, -1, 0)
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -175,9 +265,9 @@ func TestBookService_FailedDeleteBookSuccess(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	bookRepositoryMock.Mock.On("Delete", 1).Return(nil, nil)
+	bookRepositoryMock.Mock.On("Delete", 1, 0).Return(nil, nil)
 
-	book, _ := bookService.DeleteById(1)
+	book, _ := bookService.DeleteById(data.Actor{}, 1, 0)
 
 	assert.Nil(t, book)
 }
@@ -187,7 +277,7 @@ func TestBookService_FailedUpdateBookIdNegative(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(-1, request.UpdateBook{})
+	book, err := bookService.Update(data.Actor{}, -1, 0, request.UpdateBook{})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -198,7 +288,7 @@ func TestBookService_FailedUpdateBookEmptyDataRequest(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(1, request.UpdateBook{})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -209,7 +299,7 @@ func TestBookService_FailedUpdateBookMinTitle(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(1, request.UpdateBook{Title: "il"})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{Title: "il"})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -220,7 +310,7 @@ func TestBookService_FailedUpdateBookMinIsbn(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(1, request.UpdateBook{Isbn: "123456789"})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{Isbn: "123456789"})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -231,7 +321,7 @@ func TestBookService_FailedUpdateBookMaxIsbn(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(1, request.UpdateBook{Isbn: "12345678901234"})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{Isbn: "12345678901234"})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -242,7 +332,7 @@ func TestBookService_FailedUpdateBookAuthorIdNegative(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	book, err := bookService.Update(1, request.UpdateBook{AuthorId: -1})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{AuthorId: -1})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -253,9 +343,9 @@ func TestBookService_FailedUpdateBookIsbnExist(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	bookRepositoryMock.Mock.On("FindBookByIsbn", "1234567890").Return(response.Book{}, errors.New("isbn sudah digunakan oleh buku lain"))
+	bookRepositoryMock.Mock.On("FindBookByIsbn", "9780306406157").Return(response.Book{}, errors.New("isbn sudah digunakan oleh buku lain"))
 
-	book, err := bookService.Update(1, request.UpdateBook{Isbn: "1234567890"})
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{Isbn: "9780306406157"})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
@@ -266,10 +356,258 @@ func TestBookService_FailedUpdateBookIdBookBotFound(t *testing.T) {
 	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
 	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
 
-	bookRepositoryMock.Mock.On("Update", 1, request.UpdateBook{}).Return(nil, errors.New("gagal mengupdate data book, book tidak ditemukan"))
+	bookRepositoryMock.Mock.On("Update", 1, 0, request.UpdateBook{}).Return(nil, errors.New("gagal mengupdate data book, book tidak ditemukan"))
+
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{})
+
+	assert.Nil(t, book)
+	assert.NotNil(t, err)
+}
+
+func TestBookService_SearchFailedQueryEmpty(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book, _, err := bookService.Search(request.SearchBook{Q: "   "})
+
+	assert.Nil(t, book)
+	assert.NotNil(t, err)
+	assert.Equal(t, "kata kunci pencarian tidak boleh kosong", err.Error())
+}
+
+func TestBookService_SearchNotFound(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("Search", request.SearchBook{Q: "golang", Page: 1, Limit: 10}).Return([]response.SearchBook{}, int64(0), nil)
 
-	book, err := bookService.Update(1, request.UpdateBook{})
+	book, _, err := bookService.Search(request.SearchBook{Q: " golang "})
 
 	assert.Nil(t, book)
 	assert.NotNil(t, err)
+	assert.Equal(t, "data book kosong", err.Error())
+}
+
+func TestBookService_SearchSuccess(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	books := []response.SearchBook{
+		{
+			Id:             1,
+			Title:          "Belajar Golang",
+			Isbn:           "9780306406157",
+			AuthorId:       1,
+			AuthorName:     "Ilham Sidiq",
+			TitleHighlight: "Belajar <mark>Golang</mark>",
+			Rank:           -2.5,
+		},
+	}
+
+	bookRepositoryMock.Mock.On("Search", request.SearchBook{Q: "golang", Page: 1, Limit: 10}).Return(books, int64(1), nil)
+
+	result, pagination, err := bookService.Search(request.SearchBook{Q: "golang"})
+
+	assert.Nil(t, err)
+	assert.Len(t, *result, 1)
+	assert.Equal(t, "Belajar <mark>Golang</mark>", (*result)[0].Highlight.Title)
+	assert.Equal(t, 2.5, (*result)[0].Score)
+	assert.Equal(t, int64(1), pagination.TotalItems)
+}
+
+func TestBookService_SaveFailedLanguage(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book := request.CreateBook{
+		Title:           "ilham",
+		Isbn:            "9780306406157",
+		AuthorId:        1,
+		BookPublication: request.BookPublication{Language: "indonesia"},
+	}
+
+	_, err := bookService.Save(data.Actor{}, book)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]string{"language": "language harus berupa kode ISO 639-1, contoh : id, en"}, FieldMessages(err))
+}
+
+func TestBookService_SaveFailedFormat(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book := request.CreateBook{
+		Title:           "ilham",
+		Isbn:            "9780306406157",
+		AuthorId:        1,
+		BookPublication: request.BookPublication{Language: "ID", Format: "magazine"},
+	}
+
+	_, err := bookService.Save(data.Actor{}, book)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]string{"format": "format hanya boleh salah satu dari : hardcover, paperback, ebook, audio"}, FieldMessages(err))
+}
+
+func TestBookService_FailedUpdateBookEditionOfItself(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book, err := bookService.Update(data.Actor{}, 1, 0, request.UpdateBook{
+		Title:           "ilham",
+		Isbn:            "9780306406157",
+		AuthorId:        1,
+		BookPublication: request.BookPublication{EditionOf: 1},
+	})
+
+	assert.Nil(t, book)
+	assert.Equal(t, "edition_of tidak boleh sama dengan id book", err.Error())
+}
+
+func TestBookService_SaveFailedIsbnChecksum(t *testing.T) {
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	_, err := bookService.Save(data.Actor{}, request.CreateBook{Title: "ilham", Isbn: "ABCDEFGHIJ", AuthorId: 1})
+	assert.Equal(t, map[string]string{"isbn": "isbn hanya boleh berisi angka, dengan X sebagai check digit ISBN-10"}, FieldMessages(err))
+
+	_, err = bookService.Save(data.Actor{}, request.CreateBook{Title: "ilham", Isbn: "0306406153", AuthorId: 1})
+	assert.Equal(t, map[string]string{"isbn": "check digit isbn tidak valid"}, FieldMessages(err))
+}
+
+func TestBookService_PatchKeepsOwnIsbn(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	year := 2005
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{
+		Id:              1,
+		Title:           "Laskar Pelangi",
+		Isbn:            "9789793062792",
+		AuthorId:        1,
+		PublicationYear: &year,
+		Description:     "Novel pertama tetralogi Laskar Pelangi",
+		Contributors:    []response.BookContributor{{AuthorId: 1, Role: "author"}, {AuthorId: 2, Role: "editor"}},
+		Categories:      []response.BookCategory{{ID: 3, Name: "Fiksi"}},
+		Version:         2,
+	}, nil)
+	bookRepositoryMock.Mock.On("FindBookByIsbn", "9789793062792").Return(response.Book{Id: 1}, nil)
+
+	expected := request.UpdateBook{
+		Title:           "The Rainbow Troops",
+		Isbn:            "9789793062792",
+		AuthorId:        1,
+		Contributors:    []request.BookContributor{{AuthorId: 1, Role: "author"}, {AuthorId: 2, Role: "editor"}},
+		CategoryIds:     []int{3},
+		BookPublication: request.BookPublication{PublicationYear: 2005},
+	}
+	bookRepositoryMock.Mock.On("Update", 1, 2, expected).Return(&response.ResultBook{Id: 1, Title: "The Rainbow Troops"}, nil)
+
+	book, err := bookService.Patch(data.Actor{}, 1, 0, []byte(`{"title": "The Rainbow Troops", "description": null}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "The Rainbow Troops", book.Title)
+	bookRepositoryMock.Mock.AssertCalled(t, "Update", 1, 2, expected)
+}
+
+func TestBookService_PatchAuthorIdReplacesContributors(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{
+		Id:           1,
+		Title:        "Laskar Pelangi",
+		Isbn:         "9789793062792",
+		AuthorId:     1,
+		Contributors: []response.BookContributor{{AuthorId: 1, Role: "author"}, {AuthorId: 2, Role: "editor"}},
+		Version:      1,
+	}, nil)
+	bookRepositoryMock.Mock.On("FindBookByIsbn", "9789793062792").Return(response.Book{Id: 1}, nil)
+
+	expected := request.UpdateBook{
+		Title:        "Laskar Pelangi",
+		Isbn:         "9789793062792",
+		AuthorId:     4,
+		Contributors: []request.BookContributor{{AuthorId: 4, Role: "author"}},
+		CategoryIds:  []int{},
+	}
+	bookRepositoryMock.Mock.On("Update", 1, 1, expected).Return(&response.ResultBook{Id: 1}, nil)
+
+	_, err := bookService.Patch(data.Actor{}, 1, 0, []byte(`{"author_id": 4}`))
+
+	assert.Nil(t, err)
+	bookRepositoryMock.Mock.AssertCalled(t, "Update", 1, 1, expected)
+}
+
+func TestBookService_PatchFailedClearRequiredField(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, Title: "Laskar Pelangi", Isbn: "9789793062792", AuthorId: 1}, nil)
+
+	book, err := bookService.Patch(data.Actor{}, 1, 0, []byte(`{"title": null, "isbn": "123"}`))
+
+	assert.Nil(t, book)
+	assert.Equal(t, map[string]string{"title": "title wajib diisi", "isbn": "isbn harus terdiri dari 10 atau 13 digit"}, FieldMessages(err))
+	bookRepositoryMock.Mock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookService_PatchFailedInvalidPatch(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	_, err := bookService.Patch(data.Actor{}, 1, 0, []byte(`["title"]`))
+	assert.True(t, errors.Is(err, apperror.ErrBadRequest))
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, Title: "Laskar Pelangi", Isbn: "9789793062792", AuthorId: 1}, nil)
+
+	_, err = bookService.Patch(data.Actor{}, 1, 0, []byte(`{"subtitle": "Edisi Revisi"}`))
+	assert.True(t, errors.Is(err, apperror.ErrBadRequest))
+
+	_, err = bookService.Patch(data.Actor{}, 1, 0, []byte(`{"page_count": "banyak"}`))
+	assert.True(t, errors.Is(err, apperror.ErrBadRequest))
+}
+
+func TestBookService_PatchFailedVersionMismatch(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, Title: "Laskar Pelangi", Isbn: "9789793062792", AuthorId: 1, Version: 3}, nil)
+
+	book, err := bookService.Patch(data.Actor{}, 1, 2, []byte(`{"title": "The Rainbow Troops"}`))
+
+	assert.Nil(t, book)
+	assert.True(t, errors.Is(err, apperror.ErrPrecondition))
+	bookRepositoryMock.Mock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookService_UpdateFailedVersionMismatch(t *testing.T) {
+
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var bookService = service.BookServices{BookRepository: &bookRepositoryMock}
+
+	book := request.UpdateBook{Title: "Laskar Pelangi", Isbn: "9789793062792", AuthorId: 1}
+	expected := book
+	expected.Contributors = []request.BookContributor{{AuthorId: 1, Role: "author"}}
+
+	bookRepositoryMock.Mock.On("FindBookByIsbn", "9789793062792").Return(response.Book{Id: 1}, nil)
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, Version: 3}, nil)
+	bookRepositoryMock.Mock.On("Update", 1, 2, expected).Return(nil, repository.ErrVersionMismatch)
+
+	result, err := bookService.Update(data.Actor{}, 1, 2, book)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, apperror.ErrPrecondition))
 }
@@ -0,0 +1,99 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryService_SaveFailedNameEmpty(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	category, err := categoryService.Save(request.CreateCategory{Name: " "})
+
+	assert.Nil(t, category)
+	assert.Equal(t, "nama category tidak boleh kosong", err.Error())
+}
+
+func TestCategoryService_SaveFailedParentNotFound(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	categoryRepositoryMock.Mock.On("FindById", 9).Return(nil, errors.New("record not found"))
+
+	category, err := categoryService.Save(request.CreateCategory{Name: "Cyberpunk", ParentId: 9})
+
+	assert.Nil(t, category)
+	assert.Equal(t, "parent category tidak ditemukan", err.Error())
+}
+
+func TestCategoryService_SaveFailedNameExist(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	categoryRepositoryMock.Mock.On("FindByName", 0, "Fiksi").Return(response.Category{ID: 1, Name: "Fiksi"}, nil)
+
+	category, err := categoryService.Save(request.CreateCategory{Name: "Fiksi"})
+
+	assert.Nil(t, category)
+	assert.Equal(t, "nama category sudah digunakan pada parent yang sama", err.Error())
+}
+
+func TestCategoryService_FindTree(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	fiction, scifi := 1, 2
+
+	categoryRepositoryMock.Mock.On("FindAll").Return([]response.Category{
+		{ID: 3, Name: "Cyberpunk", ParentId: &scifi},
+		{ID: 1, Name: "Fiksi"},
+		{ID: 4, Name: "Non Fiksi"},
+		{ID: 2, Name: "Fiksi Ilmiah", ParentId: &fiction},
+	}, nil)
+
+	tree, err := categoryService.FindTree()
+
+	assert.Nil(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Fiksi", tree[0].Name)
+	assert.Equal(t, "Fiksi Ilmiah", tree[0].Children[0].Name)
+	assert.Equal(t, "Cyberpunk", tree[0].Children[0].Children[0].Name)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestCategoryService_UpdateFailedMoveIntoDescendant(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	parentId := 3
+
+	categoryRepositoryMock.Mock.On("FindById", 1).Return(response.Category{ID: 1, Name: "Fiksi"}, nil)
+	categoryRepositoryMock.Mock.On("FindById", 3).Return(response.Category{ID: 3, Name: "Cyberpunk"}, nil)
+	categoryRepositoryMock.Mock.On("Descendants", 1).Return([]int{1, 2, 3}, nil)
+
+	category, err := categoryService.UpdateById(1, request.UpdateCategory{ParentId: &parentId})
+
+	assert.Nil(t, category)
+	assert.Equal(t, "category tidak boleh dipindah ke dalam dirinya sendiri atau sub category-nya", err.Error())
+}
+
+func TestCategoryService_DeleteFailedHasChildren(t *testing.T) {
+	var categoryRepositoryMock = repomock.CategoryRepositoryMock{Mock: mock.Mock{}}
+	var categoryService = service.CategoryServices{CategoryRepository: &categoryRepositoryMock}
+
+	categoryRepositoryMock.Mock.On("DeleteById", 1).Return(nil, repository.ErrCategoryHasChildren)
+
+	category, err := categoryService.DeleteById(1)
+
+	assert.Nil(t, category)
+	assert.ErrorIs(t, err, repository.ErrCategoryHasChildren)
+}
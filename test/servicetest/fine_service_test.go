@@ -0,0 +1,163 @@
+package servicetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var fineNow = time.Date(2024, 10, 20, 9, 0, 0, 0, time.UTC)
+
+var finePolicy = service.FinePolicy{
+	GraceDays:  1,
+	MaxPerItem: 500000,
+	Rates:      map[string]int64{"regular": 100000, "student": 50000},
+}
+
+func newFineService(fineRepositoryMock *repomock.FineRepositoryMock, loanRepositoryMock *repomock.LoanRepositoryMock, userRepositoryMock *repomock.UserRepositoryMock) service.FineServices {
+	return service.FineServices{
+		FineRepository: fineRepositoryMock,
+		LoanRepository: loanRepositoryMock,
+		UserRepository: userRepositoryMock,
+		Policy:         finePolicy,
+		Now:            func() time.Time { return fineNow },
+	}
+}
+
+func TestFinePolicy_CalculateWithinGracePeriod(t *testing.T) {
+	days, amount := finePolicy.Calculate("regular", fineNow.AddDate(0, 0, -1), fineNow)
+
+	assert.Equal(t, 1, days)
+	assert.Equal(t, int64(0), amount)
+}
+
+func TestFinePolicy_CalculateNotOverdue(t *testing.T) {
+	days, amount := finePolicy.Calculate("regular", fineNow.AddDate(0, 0, 2), fineNow)
+
+	assert.Equal(t, 0, days)
+	assert.Equal(t, int64(0), amount)
+}
+
+func TestFinePolicy_CalculatePerCategory(t *testing.T) {
+	dueDate := fineNow.AddDate(0, 0, -3)
+
+	_, regular := finePolicy.Calculate("regular", dueDate, fineNow)
+	_, student := finePolicy.Calculate("student", dueDate, fineNow)
+	_, senior := finePolicy.Calculate("senior", dueDate, fineNow)
+
+	assert.Equal(t, int64(200000), regular)
+	assert.Equal(t, int64(100000), student)
+	assert.Equal(t, int64(200000), senior)
+}
+
+func TestFinePolicy_CalculateCappedPerItem(t *testing.T) {
+	days, amount := finePolicy.Calculate("regular", fineNow.AddDate(0, 0, -30), fineNow)
+
+	assert.Equal(t, 30, days)
+	assert.Equal(t, int64(500000), amount)
+}
+
+func TestFineService_FindByUsernameIncludesAccruing(t *testing.T) {
+
+	var fineRepositoryMock = repomock.FineRepositoryMock{Mock: mock.Mock{}}
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var fineService = newFineService(&fineRepositoryMock, &loanRepositoryMock, &userRepositoryMock)
+
+	returnedAt := fineNow.AddDate(0, 0, -1)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham", Category: "regular"}, nil)
+	fineRepositoryMock.Mock.On("FindAllByUserId", 3).Return([]response.FineEntry{{Id: 1, EntryType: "charge", Amount: 300000}}, nil)
+	fineRepositoryMock.Mock.On("Balance", 3, 0).Return(int64(300000), nil)
+	loanRepositoryMock.Mock.On("FindAllByUserId", 3).Return([]response.Loan{
+		{Id: 2, Title: "Belajar Golang", DueDate: fineNow.AddDate(0, 0, -2)},
+		{Id: 1, DueDate: fineNow.AddDate(0, 0, -10), ReturnedAt: &returnedAt},
+	}, nil)
+
+	result, err := fineService.FindByUsername("ilham")
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(300000), result.Balance)
+	assert.Equal(t, int64(100000), result.Accruing)
+	assert.Len(t, result.AccruingLoans, 1)
+	assert.Equal(t, 2, result.AccruingLoans[0].LoanId)
+	assert.Len(t, result.Ledger, 1)
+}
+
+func TestFineService_RecordPaymentFailedAmountZero(t *testing.T) {
+
+	var fineRepositoryMock = repomock.FineRepositoryMock{Mock: mock.Mock{}}
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var fineService = newFineService(&fineRepositoryMock, &loanRepositoryMock, &userRepositoryMock)
+
+	result, err := fineService.RecordPayment("admin", request.FineEntry{UserId: 3})
+
+	assert.Nil(t, result)
+	assert.Equal(t, "amount harus lebih dari 0", err.Error())
+}
+
+func TestFineService_RecordPaymentFailedExceedsBalance(t *testing.T) {
+
+	var fineRepositoryMock = repomock.FineRepositoryMock{Mock: mock.Mock{}}
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var fineService = newFineService(&fineRepositoryMock, &loanRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "admin").Return(response.User{ID: 1, Username: "admin"}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Username: "ilham"}, nil)
+	fineRepositoryMock.Mock.On("Save", mock.Anything).Return(nil, repository.ErrFineExceedsBalance)
+
+	result, err := fineService.RecordPayment("admin", request.FineEntry{UserId: 3, Amount: 100})
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrFineExceedsBalance, err)
+}
+
+func TestFineService_WaiveFailedLoanOfOtherUser(t *testing.T) {
+
+	var fineRepositoryMock = repomock.FineRepositoryMock{Mock: mock.Mock{}}
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var fineService = newFineService(&fineRepositoryMock, &loanRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "admin").Return(response.User{ID: 1, Username: "admin"}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Username: "ilham"}, nil)
+	loanRepositoryMock.Mock.On("FindById", 5).Return(response.Loan{Id: 5, UserId: 4}, nil)
+
+	result, err := fineService.Waive("admin", request.FineEntry{UserId: 3, LoanId: 5, Amount: 100})
+
+	assert.Nil(t, result)
+	assert.Equal(t, "peminjaman tidak ditemukan", err.Error())
+}
+
+func TestFineService_WaiveSuccess(t *testing.T) {
+
+	var fineRepositoryMock = repomock.FineRepositoryMock{Mock: mock.Mock{}}
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var fineService = newFineService(&fineRepositoryMock, &loanRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "admin").Return(response.User{ID: 1, Username: "admin"}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Username: "ilham"}, nil)
+	fineRepositoryMock.Mock.On("Save", request.FineEntry{
+		UserId:     3,
+		Amount:     100,
+		Note:       "buku rusak ringan",
+		EntryType:  "waiver",
+		RecordedBy: 1,
+		CreatedAt:  fineNow,
+	}).Return(&response.FineEntry{Id: 2, UserId: 3, EntryType: "waiver", Amount: 100}, nil)
+
+	result, err := fineService.Waive("admin", request.FineEntry{UserId: 3, Amount: 100, Note: "buku rusak ringan"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "waiver", result.EntryType)
+}
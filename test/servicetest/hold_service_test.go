@@ -0,0 +1,177 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var holdNow = time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+
+func newHoldService(holdRepositoryMock *repomock.HoldRepositoryMock, bookRepositoryMock *repomock.BookRepositoryMock, userRepositoryMock *repomock.UserRepositoryMock) service.HoldServices {
+	return service.HoldServices{
+		HoldRepository: holdRepositoryMock,
+		BookRepository: bookRepositoryMock,
+		UserRepository: userRepositoryMock,
+		PickupDays:     3,
+		Now:            func() time.Time { return holdNow },
+	}
+}
+
+func TestHoldService_PlaceHoldFailedCopyAvailable(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, TotalCopies: 2, AvailableCopies: 1}, nil)
+
+	result, err := holdService.PlaceHold("ilham", 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "masih ada eksemplar yang tersedia, silakan langsung meminjam", err.Error())
+}
+
+func TestHoldService_PlaceHoldFailedNoCopies(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1}, nil)
+
+	result, err := holdService.PlaceHold("ilham", 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, "buku belum memiliki eksemplar", err.Error())
+}
+
+func TestHoldService_PlaceHoldSuccess(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, TotalCopies: 1}, nil)
+	holdRepositoryMock.Mock.On("Save", request.CreateHold{BookId: 1, UserId: 3, CreatedAt: holdNow}).
+		Return(&response.Hold{Id: 1, BookId: 1, UserId: 3, Status: "waiting", QueuePosition: 2}, nil)
+
+	result, err := holdService.PlaceHold("ilham", 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting", result.Status)
+	assert.Equal(t, 2, result.QueuePosition)
+}
+
+func TestHoldService_PlaceHoldFailedAlreadyQueued(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	bookRepositoryMock.Mock.On("FindById", 1).Return(response.Book{Id: 1, TotalCopies: 1}, nil)
+	holdRepositoryMock.Mock.On("Save", mock.Anything).Return(nil, repository.ErrHoldExists)
+
+	result, err := holdService.PlaceHold("ilham", 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrHoldExists, err)
+}
+
+func TestHoldService_CancelFailedNotOwner(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	holdRepositoryMock.Mock.On("FindById", 1).Return(response.Hold{Id: 1, UserId: 4}, nil)
+
+	result, err := holdService.Cancel("ilham", 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrHoldNotActive, err)
+	holdRepositoryMock.Mock.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHoldService_CancelSuccess(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	holdRepositoryMock.Mock.On("FindById", 1).Return(response.Hold{Id: 1, UserId: 3, Status: "ready"}, nil)
+	holdRepositoryMock.Mock.On("Cancel", 1, holdNow, holdNow.AddDate(0, 0, 3)).
+		Return(&response.Hold{Id: 1, UserId: 3, Status: "cancelled"}, nil)
+
+	result, err := holdService.Cancel("ilham", 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cancelled", result.Status)
+}
+
+func TestHoldService_FindByUsernameEmpty(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	holdRepositoryMock.Mock.On("FindAllByUserId", 3).Return([]response.Hold{}, nil)
+
+	result, err := holdService.FindByUsername("ilham")
+
+	assert.Nil(t, result)
+	assert.Equal(t, "data antrean kosong", err.Error())
+}
+
+func TestHoldService_ExpireHoldsUsesPickupWindow(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	holdRepositoryMock.Mock.On("Expire", holdNow, holdNow.AddDate(0, 0, 3)).Return(int64(2), nil)
+
+	expired, err := holdService.ExpireHolds()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), expired)
+}
+
+func TestHoldService_ExpireHoldsFailed(t *testing.T) {
+
+	var holdRepositoryMock = repomock.HoldRepositoryMock{Mock: mock.Mock{}}
+	var bookRepositoryMock = repomock.BookRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var holdService = newHoldService(&holdRepositoryMock, &bookRepositoryMock, &userRepositoryMock)
+
+	holdRepositoryMock.Mock.On("Expire", mock.Anything, mock.Anything).Return(int64(0), errors.New("database is locked"))
+
+	expired, err := holdService.ExpireHolds()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(0), expired)
+}
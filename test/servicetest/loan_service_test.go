@@ -0,0 +1,255 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var loanNow = time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+
+var loanStaff = data.Actor{Username: "pustakawan", Role: data.RoleLibrarian}
+
+func newLoanService(loanRepositoryMock *repomock.LoanRepositoryMock, userRepositoryMock *repomock.UserRepositoryMock) service.LoanServices {
+	return service.LoanServices{
+		LoanRepository: loanRepositoryMock,
+		UserRepository: userRepositoryMock,
+		LoanDays:       14,
+		MaxRenewals:    2,
+		HoldPickupDays: 3,
+		Now:            func() time.Time { return loanNow },
+		FinePolicy: service.FinePolicy{
+			GraceDays:  1,
+			MaxPerItem: 500000,
+			Rates:      map[string]int64{"regular": 100000, "student": 50000},
+		},
+	}
+}
+
+func TestLoanService_CheckoutFailedCopyIdEmpty(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	result, err := loanService.Checkout("ilham", request.CreateLoan{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "copy_id tidak boleh kosong, negatif atau 0", err.Error())
+}
+
+func TestLoanService_CheckoutFailedUserNotFound(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindById", 7).Return(nil, errors.New("record not found"))
+
+	result, err := loanService.Checkout("ilham", request.CreateLoan{CopyId: 1, UserId: 7})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "user tidak ditemukan", err.Error())
+}
+
+func TestLoanService_CheckoutSuccessDueDate(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	dueDate := loanNow.AddDate(0, 0, 14)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	loanRepositoryMock.Mock.On("Checkout", request.CreateLoan{CopyId: 1, UserId: 3, LoanedAt: loanNow, DueDate: dueDate}).
+		Return(&response.Loan{Id: 1, CopyId: 1, UserId: 3, LoanedAt: loanNow, DueDate: dueDate}, nil)
+
+	result, err := loanService.Checkout("ilham", request.CreateLoan{CopyId: 1})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.UserId)
+	assert.Equal(t, dueDate, result.DueDate)
+	assert.False(t, result.Overdue)
+}
+
+func TestLoanService_CheckoutFailedCopyNotAvailable(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	loanRepositoryMock.Mock.On("Checkout", mock.Anything).Return(nil, repository.ErrCopyNotAvailable)
+
+	result, err := loanService.Checkout("ilham", request.CreateLoan{CopyId: 1})
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrCopyNotAvailable, err)
+}
+
+func TestLoanService_ReturnFailedNotActive(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(nil, errors.New("record not found"))
+
+	result, err := loanService.Return(1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, "peminjaman tidak ditemukan atau sudah dikembalikan", err.Error())
+	loanRepositoryMock.Mock.AssertNotCalled(t, "Return", mock.Anything, mock.Anything)
+}
+
+func TestLoanService_ReturnChargesOverdueFine(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, UserId: 3, DueDate: loanNow.AddDate(0, 0, -4)}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Category: "student"}, nil)
+	loanRepositoryMock.Mock.On("Return", 1, request.ReturnLoan{
+		ReturnedAt:    loanNow,
+		HoldExpiresAt: loanNow.AddDate(0, 0, 3),
+		Fine:          150000,
+	}).Return(&response.Loan{Id: 1, UserId: 3, ReturnedAt: &loanNow}, nil)
+
+	result, err := loanService.Return(1)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result.ReturnedAt)
+}
+
+func TestLoanService_RenewFailedMaxRenewals(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, RenewalCount: 2, DueDate: loanNow}, nil)
+
+	result, err := loanService.Renew(loanStaff, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, "peminjaman sudah mencapai batas maksimal perpanjangan", err.Error())
+}
+
+func TestLoanService_RenewExtendsDueDate(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	dueDate := loanNow.AddDate(0, 0, 3)
+	newDueDate := dueDate.AddDate(0, 0, 14)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, RenewalCount: 1, DueDate: dueDate}, nil)
+	loanRepositoryMock.Mock.On("Renew", 1, newDueDate, 2).Return(&response.Loan{Id: 1, RenewalCount: 2, DueDate: newDueDate}, nil)
+
+	result, err := loanService.Renew(loanStaff, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.RenewalCount)
+	assert.Equal(t, newDueDate, result.DueDate)
+}
+
+func TestLoanService_RenewFailedOverdueKeepsFine(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	dueDate := loanNow.AddDate(0, 0, -4)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, UserId: 3, DueDate: dueDate}, nil)
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Category: "student"}, nil)
+	loanRepositoryMock.Mock.On("Return", 1, request.ReturnLoan{
+		ReturnedAt:    loanNow,
+		HoldExpiresAt: loanNow.AddDate(0, 0, 3),
+		Fine:          150000,
+	}).Return(&response.Loan{Id: 1, UserId: 3, ReturnedAt: &loanNow}, nil)
+
+	result, err := loanService.Renew(data.Actor{Username: "ilham", Role: data.RoleMember}, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, "peminjaman sudah melewati jatuh tempo, kembalikan eksemplar agar dendanya tercatat", err.Error())
+	loanRepositoryMock.Mock.AssertNotCalled(t, "Renew", mock.Anything, mock.Anything, mock.Anything)
+
+	returned, err := loanService.Return(1)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, returned.ReturnedAt)
+	loanRepositoryMock.Mock.AssertCalled(t, "Return", 1, request.ReturnLoan{
+		ReturnedAt:    loanNow,
+		HoldExpiresAt: loanNow.AddDate(0, 0, 3),
+		Fine:          150000,
+	})
+}
+
+func TestLoanService_RenewFailedNotOwnLoan(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, UserId: 3, DueDate: loanNow.AddDate(0, 0, 3)}, nil)
+	userRepositoryMock.Mock.On("FindByUsername", "budi").Return(response.User{ID: 4, Username: "budi"}, nil)
+
+	result, err := loanService.Renew(data.Actor{Username: "budi", Role: data.RoleMember}, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrLoanNotActive, err)
+	loanRepositoryMock.Mock.AssertNotCalled(t, "Renew", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLoanService_RenewFailedHoldWaiting(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	dueDate := loanNow.AddDate(0, 0, 3)
+
+	loanRepositoryMock.Mock.On("FindById", 1).Return(response.Loan{Id: 1, UserId: 3, DueDate: dueDate}, nil)
+	loanRepositoryMock.Mock.On("Renew", 1, dueDate.AddDate(0, 0, 14), 2).Return(nil, repository.ErrLoanOnHold)
+
+	result, err := loanService.Renew(loanStaff, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrLoanOnHold, err)
+}
+
+func TestLoanService_FindByUsernameMarksOverdue(t *testing.T) {
+
+	var loanRepositoryMock = repomock.LoanRepositoryMock{Mock: mock.Mock{}}
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var loanService = newLoanService(&loanRepositoryMock, &userRepositoryMock)
+
+	returnedAt := loanNow.AddDate(0, 0, -5)
+
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 3, Username: "ilham"}, nil)
+	loanRepositoryMock.Mock.On("FindAllByUserId", 3).Return([]response.Loan{
+		{Id: 2, DueDate: loanNow.AddDate(0, 0, -1)},
+		{Id: 1, DueDate: loanNow.AddDate(0, 0, -10), ReturnedAt: &returnedAt},
+	}, nil)
+
+	result, err := loanService.FindByUsername("ilham")
+
+	assert.Nil(t, err)
+	assert.True(t, (*result)[0].Overdue)
+	assert.False(t, (*result)[1].Overdue)
+}
@@ -0,0 +1,100 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPublisherService_SaveFailedNameEmpty(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	publisher, err := publisherService.Save(request.CreatePublisher{Name: "  "})
+
+	assert.Nil(t, publisher)
+	assert.Equal(t, "nama publisher tidak boleh kosong", err.Error())
+}
+
+func TestPublisherService_SaveFailedNameExist(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	publisherRepositoryMock.Mock.On("FindByName", "Gramedia").Return(response.Publisher{ID: 1, Name: "Gramedia"}, nil)
+
+	publisher, err := publisherService.Save(request.CreatePublisher{Name: "Gramedia"})
+
+	assert.Nil(t, publisher)
+	assert.Equal(t, "nama publisher sudah digunakan", err.Error())
+}
+
+func TestPublisherService_SaveSuccess(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	publisherRequest := request.CreatePublisher{Name: "Bentang Pustaka", City: "Yogyakarta"}
+
+	publisherRepositoryMock.Mock.On("FindByName", "Bentang Pustaka").Return(nil, errors.New("record not found"))
+	publisherRepositoryMock.Mock.On("Save", publisherRequest).Return(response.Publisher{ID: 1, Name: "Bentang Pustaka", City: "Yogyakarta"}, nil)
+
+	publisher, err := publisherService.Save(publisherRequest)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, publisher.ID)
+	assert.Equal(t, "Yogyakarta", publisher.City)
+}
+
+func TestPublisherService_FindAllEmpty(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	query := request.PublisherQuery{Page: 1, Limit: 10, Sort: "id", Order: "asc"}
+
+	publisherRepositoryMock.Mock.On("FindAll", query).Return([]response.Publisher{}, int64(0), nil)
+
+	publishers, pagination, err := publisherService.FindAll(request.PublisherQuery{})
+
+	assert.Nil(t, publishers)
+	assert.Nil(t, pagination)
+	assert.Equal(t, "data publisher kosong", err.Error())
+}
+
+func TestPublisherService_FindAllFailedSort(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	_, _, err := publisherService.FindAll(request.PublisherQuery{Sort: "country"})
+
+	assert.Equal(t, "sort hanya boleh salah satu dari : id, name, city", err.Error())
+}
+
+func TestPublisherService_DeleteFailedInUse(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	publisherRepositoryMock.Mock.On("DeleteById", 1).Return(nil, repository.ErrPublisherInUse)
+
+	publisher, err := publisherService.DeleteById(1)
+
+	assert.Nil(t, publisher)
+	assert.Equal(t, "publisher masih dipakai oleh buku", err.Error())
+}
+
+func TestPublisherService_UpdateFailedNameUsedByOther(t *testing.T) {
+	var publisherRepositoryMock = repomock.PublisherRepositoryMock{Mock: mock.Mock{}}
+	var publisherService = service.PublisherServices{PublisherRepository: &publisherRepositoryMock}
+
+	publisherRepositoryMock.Mock.On("FindByName", "Gramedia").Return(response.Publisher{ID: 2, Name: "Gramedia"}, nil)
+
+	publisher, err := publisherService.UpdateById(1, request.UpdatePublisher{Name: "Gramedia"})
+
+	assert.Nil(t, publisher)
+	assert.Equal(t, "nama publisher sudah digunakan", err.Error())
+}
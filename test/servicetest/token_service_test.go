@@ -0,0 +1,123 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
+	"github.com/ilhaamms/library-api/jwtkey"
+	"github.com/ilhaamms/library-api/repository"
+	"github.com/ilhaamms/library-api/service"
+	"github.com/ilhaamms/library-api/test/repomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var tokenNow = time.Date(2024, 10, 20, 9, 0, 0, 0, time.UTC)
+
+func newTokenService(userRepositoryMock *repomock.UserRepositoryMock, tokenRepositoryMock *repomock.TokenRepositoryMock) service.UserServices {
+	return service.UserServices{
+		UserRepository:  userRepositoryMock,
+		TokenRepository: tokenRepositoryMock,
+		Keys:            jwtkey.NewHMAC([]byte("rahasia")),
+		Now:             func() time.Time { return tokenNow },
+	}
+}
+
+func TestUserService_RefreshFailedEmpty(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var tokenRepositoryMock = repomock.TokenRepositoryMock{Mock: mock.Mock{}}
+	var userService = newTokenService(&userRepositoryMock, &tokenRepositoryMock)
+
+	result, err := userService.Refresh(request.Refresh{})
+
+	assert.Nil(t, result)
+	assert.Equal(t, "refresh_token wajib diisi", err.Error())
+}
+
+func TestUserService_RefreshFailedExpired(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var tokenRepositoryMock = repomock.TokenRepositoryMock{Mock: mock.Mock{}}
+	var userService = newTokenService(&userRepositoryMock, &tokenRepositoryMock)
+
+	tokenRepositoryMock.Mock.On("FindByHash", mock.Anything).Return(response.RefreshToken{
+		Id:        1,
+		UserId:    3,
+		FamilyId:  "family",
+		ExpiresAt: tokenNow.Add(-time.Minute),
+	}, nil)
+
+	result, err := userService.Refresh(request.Refresh{RefreshToken: "lama"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrRefreshTokenInvalid, err)
+}
+
+func TestUserService_RefreshReusedRevokesFamily(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var tokenRepositoryMock = repomock.TokenRepositoryMock{Mock: mock.Mock{}}
+	var userService = newTokenService(&userRepositoryMock, &tokenRepositoryMock)
+
+	revokedAt := tokenNow.Add(-time.Hour)
+	replacedBy := 2
+
+	tokenRepositoryMock.Mock.On("FindByHash", mock.Anything).Return(response.RefreshToken{
+		Id:         1,
+		UserId:     3,
+		FamilyId:   "family",
+		ExpiresAt:  tokenNow.Add(time.Hour),
+		RevokedAt:  &revokedAt,
+		ReplacedBy: &replacedBy,
+	}, nil)
+	tokenRepositoryMock.Mock.On("RevokeFamily", "family", tokenNow).Return(nil)
+
+	result, err := userService.Refresh(request.Refresh{RefreshToken: "lama"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, repository.ErrRefreshTokenReused, err)
+	tokenRepositoryMock.Mock.AssertCalled(t, "RevokeFamily", "family", tokenNow)
+}
+
+func TestUserService_RefreshSuccess(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var tokenRepositoryMock = repomock.TokenRepositoryMock{Mock: mock.Mock{}}
+	var userService = newTokenService(&userRepositoryMock, &tokenRepositoryMock)
+
+	tokenRepositoryMock.Mock.On("FindByHash", mock.Anything).Return(response.RefreshToken{
+		Id:        1,
+		UserId:    3,
+		FamilyId:  "family",
+		ExpiresAt: tokenNow.Add(time.Hour),
+	}, nil)
+	userRepositoryMock.Mock.On("FindById", 3).Return(response.User{ID: 3, Username: "ilham", Role: "librarian"}, nil)
+	tokenRepositoryMock.Mock.On("Rotate", 1, mock.MatchedBy(func(next request.RefreshToken) bool {
+		return next.UserId == 3 && next.FamilyId == "family"
+	}), tokenNow).Return(nil)
+
+	result, err := userService.Refresh(request.Refresh{RefreshToken: "lama"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "librarian", result.Role)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEqual(t, "lama", result.RefreshToken)
+}
+
+func TestUserService_LogoutFailed(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var tokenRepositoryMock = repomock.TokenRepositoryMock{Mock: mock.Mock{}}
+	var userService = newTokenService(&userRepositoryMock, &tokenRepositoryMock)
+
+	tokenRepositoryMock.Mock.On("RevokeFamily", "family", tokenNow).Return(errors.New("database is locked"))
+
+	err := userService.Logout(&data.Claims{Session: "family"})
+
+	assert.Equal(t, "gagal logout : database is locked", err.Error())
+}
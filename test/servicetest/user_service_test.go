@@ -3,7 +3,9 @@ package servicetest
 import (
 	"testing"
 
+	"github.com/ilhaamms/library-api/entity/data"
 	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/entity/response"
 	"github.com/ilhaamms/library-api/service"
 	"github.com/ilhaamms/library-api/test/repomock"
 	"github.com/stretchr/testify/assert"
@@ -21,10 +23,10 @@ func TestUserService_SaveFailedNameAndPwEmpty(t *testing.T) {
 		Password: "",
 	}
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{}, user)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "username dan password wajib diisi", err.Error())
+	assert.Equal(t, map[string]string{"username": "username wajib diisi", "password": "password wajib diisi"}, FieldMessages(err))
 }
 
 func TestUserService_SaveFailedMinUsername(t *testing.T) {
@@ -37,10 +39,10 @@ func TestUserService_SaveFailedMinUsername(t *testing.T) {
 		Password: "12345678",
 	}
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{}, user)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "username minimal 5 karakter", err.Error())
+	assert.Equal(t, map[string]string{"username": "username minimal 5 karakter"}, FieldMessages(err))
 }
 
 func TestUserService_SaveFailedMaxUsername(t *testing.T) {
@@ -53,10 +55,27 @@ func TestUserService_SaveFailedMaxUsername(t *testing.T) {
 		Password: "12345678",
 	}
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{}, user)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "username maksimal 20 karakter", err.Error())
+	assert.Equal(t, map[string]string{"username": "username maksimal 20 karakter"}, FieldMessages(err))
+}
+
+func TestUserService_SaveFailedUsernameCharset(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	user := request.User{
+		Username: "ilham sidiq",
+		Password: "123",
+	}
+
+	_, err := userService.Save(data.Actor{}, user)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]string{"username": "username hanya boleh berisi huruf, angka, titik dan garis bawah", "password": "password minimal 8 karakter"}, FieldMessages(err))
+	userRepositoryMock.Mock.AssertNotCalled(t, "CheckUsername", user.Username)
 }
 
 func TestUserService_SaveFailedUsernameExist(t *testing.T) {
@@ -71,7 +90,7 @@ func TestUserService_SaveFailedUsernameExist(t *testing.T) {
 
 	userRepositoryMock.Mock.On("CheckUsername", user.Username).Return(true, nil)
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{}, user)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "username sudah digunakan oleh user lain", err.Error())
@@ -89,16 +108,17 @@ func TestUserService_SaveFailedMinPassword(t *testing.T) {
 
 	userRepositoryMock.Mock.On("CheckUsername", user.Username).Return(false, nil)
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{}, user)
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "harap masukkan password minimal 8 karakter", err.Error())
+	assert.Equal(t, map[string]string{"password": "password minimal 8 karakter"}, FieldMessages(err))
 }
 
 func TestUserService_SaveUserSuccessRegister(t *testing.T) {
 
 	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
-	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+	var auditRepositoryMock = repomock.AuditRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock, AuditRepository: &auditRepositoryMock}
 
 	user := request.User{
 		Username: "ilham",
@@ -108,10 +128,95 @@ func TestUserService_SaveUserSuccessRegister(t *testing.T) {
 	userRepositoryMock.Mock.On("CheckUsername", user.Username).Return(false, nil)
 
 	userRepositoryMock.Mock.On("Save", mock.MatchedBy(func(u request.User) bool {
-		return u.Username == user.Username && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(user.Password)) == nil
+		return u.Username == user.Username && u.Role == "member" && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(user.Password)) == nil
+	})).Return(nil)
+
+	userRepositoryMock.Mock.On("FindByUsername", user.Username).Return(response.User{ID: 7, Username: "ilham", Password: "$2a$10$hash", Role: "member", Category: "regular"}, nil)
+
+	// the password hash is never written to the audit trail
+	auditRepositoryMock.Mock.On("Save", mock.MatchedBy(func(entry response.AuditEntry) bool {
+		_, password := entry.Changes["password"]
+
+		return entry.Entity == "user" && entry.EntityId == 7 && entry.Action == "create" && entry.ClientIp == "10.0.0.1" &&
+			entry.Changes["username"] == response.AuditChange{After: "ilham"} && !password
 	})).Return(nil)
 
-	_, err := userService.Save(user)
+	_, err := userService.Save(data.Actor{ClientIp: "10.0.0.1"}, user)
+
+	assert.Nil(t, err)
+	auditRepositoryMock.Mock.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestUserService_SaveIgnoresRequestedRole(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	userRepositoryMock.Mock.On("CheckUsername", "ilham").Return(false, nil)
+	userRepositoryMock.Mock.On("Save", mock.MatchedBy(func(u request.User) bool {
+		return u.Role == "member"
+	})).Return(nil)
+	userRepositoryMock.Mock.On("FindByUsername", "ilham").Return(response.User{ID: 1, Username: "ilham", Role: "member"}, nil)
+
+	_, err := userService.Save(data.Actor{}, request.User{Username: "ilham", Password: "12345678", Role: "admin"})
+
+	assert.Nil(t, err)
+	userRepositoryMock.Mock.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestUserService_CreateAdmin(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	userRepositoryMock.Mock.On("CheckUsername", "admin.perpus").Return(false, nil)
+	userRepositoryMock.Mock.On("Save", mock.MatchedBy(func(u request.User) bool {
+		return u.Username == "admin.perpus" && u.Role == "admin"
+	})).Return(nil)
+	userRepositoryMock.Mock.On("FindByUsername", "admin.perpus").Return(response.User{ID: 1, Username: "admin.perpus", Role: "admin"}, nil)
+
+	err := userService.CreateAdmin(data.Actor{}, request.User{Username: "admin.perpus", Password: "12345678"})
+
+	assert.Nil(t, err)
+	userRepositoryMock.Mock.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestUserService_UpdateRoleFailedUnknownRole(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	_, err := userService.UpdateRole(data.Actor{}, 2, request.UpdateUserRole{Role: "owner"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "role hanya boleh salah satu dari : admin, librarian, member", err.Error())
+}
+
+func TestUserService_UpdateRoleFailedLastAdmin(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	userRepositoryMock.Mock.On("FindById", 1).Return(response.User{ID: 1, Username: "ilham", Role: "admin"}, nil)
+	userRepositoryMock.Mock.On("CountByRole", "admin").Return(int64(1), nil)
+
+	_, err := userService.UpdateRole(data.Actor{}, 1, request.UpdateUserRole{Role: "member"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "minimal harus ada satu admin", err.Error())
+	userRepositoryMock.Mock.AssertNotCalled(t, "UpdateRole", 1, "member")
+}
+
+func TestUserService_UpdateRoleSuccess(t *testing.T) {
+
+	var userRepositoryMock = repomock.UserRepositoryMock{Mock: mock.Mock{}}
+	var userService = service.UserServices{UserRepository: &userRepositoryMock}
+
+	userRepositoryMock.Mock.On("FindById", 2).Return(response.User{ID: 2, Username: "budiman", Role: "member"}, nil)
+	userRepositoryMock.Mock.On("UpdateRole", 2, "librarian").Return(nil)
+
+	result, err := userService.UpdateRole(data.Actor{}, 2, request.UpdateUserRole{Role: "librarian"})
 
 	assert.Nil(t, err)
+	assert.Equal(t, "librarian", result.Role)
 }
@@ -0,0 +1,161 @@
+package testdb
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+
+	"github.com/ilhaamms/library-api/config"
+	"github.com/ilhaamms/library-api/db"
+	"github.com/ilhaamms/library-api/migration"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	EnvDriver = "LIBRARY_TEST_DB_DRIVER"
+	EnvDSN    = "LIBRARY_TEST_DB_DSN"
+)
+
+// Configure points cfg at the database the tests run against: a SQLite file
+// in dir by default, or the driver and DSN from LIBRARY_TEST_DB_DRIVER and
+// LIBRARY_TEST_DB_DSN, for example a local PostgreSQL.
+func Configure(cfg *config.Config, dir string) {
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBPath = filepath.Join(dir, "library.db")
+
+	if driver := os.Getenv(EnvDriver); driver != "" {
+		cfg.DBDriver = driver
+		cfg.DBDSN = os.Getenv(EnvDSN)
+	}
+}
+
+// Migrations returns the embedded migrations of the database dialect. When
+// the sqlite driver was built without fts5 the book_search migration is
+// replaced by a no-op so the rest of the schema can still be built.
+func Migrations(database *gorm.DB) (fs.FS, error) {
+	migrations, err := db.MigrationsFor(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if database.Dialector.Name() != config.DriverSQLite {
+		return migrations, nil
+	}
+
+	available, err := db.FTS5Available(database)
+	if err != nil {
+		return nil, err
+	}
+
+	if available {
+		return migrations, nil
+	}
+
+	entries, err := fs.ReadDir(migrations, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	files := fstest.MapFS{}
+
+	for _, entry := range entries {
+		content, err := fs.ReadFile(migrations, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.Contains(string(content), "USING fts5") {
+			log.Printf("skip %s, run the tests with -tags sqlite_fts5 to enable it", entry.Name())
+			content = []byte("SELECT 1;")
+		}
+
+		files[entry.Name()] = &fstest.MapFile{Data: content}
+	}
+
+	return files, nil
+}
+
+func NewMigrator(database *gorm.DB) (*migration.Migrator, error) {
+	migrations, err := Migrations(database)
+	if err != nil {
+		return nil, err
+	}
+
+	return migration.New(database, migrations)
+}
+
+// Migrate rebuilds the schema from scratch so a shared database does not
+// carry data over from an earlier run.
+func Migrate(database *gorm.DB) error {
+	migrator, err := NewMigrator(database)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Goto(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Up()
+
+	return err
+}
+
+// appendOnly are the tables whose triggers refuse DELETE.
+var appendOnly = map[string]bool{"audit_log": true}
+
+// Truncate empties the tables in the given order and restarts their ids at 1.
+func Truncate(database *gorm.DB, tables ...string) {
+	for _, table := range tables {
+		if appendOnly[table] {
+			truncateAppendOnly(database, table)
+			continue
+		}
+
+		database.Exec("DELETE FROM ?", clause.Table{Name: table})
+
+		switch database.Dialector.Name() {
+		case config.DriverSQLite:
+			database.Exec("DELETE FROM sqlite_sequence WHERE name = ?", table)
+		case config.DriverPostgres:
+			database.Exec("ALTER TABLE ? ALTER COLUMN id RESTART WITH 1", clause.Table{Name: table})
+		case config.DriverMySQL:
+			database.Exec("ALTER TABLE ? AUTO_INCREMENT = 1", clause.Table{Name: table})
+		}
+	}
+}
+
+// truncateAppendOnly empties a table that refuses DELETE. TRUNCATE does not
+// fire row triggers, SQLite has no TRUNCATE so its triggers are dropped for
+// the delete and created again.
+func truncateAppendOnly(database *gorm.DB, table string) {
+	switch database.Dialector.Name() {
+	case config.DriverPostgres:
+		database.Exec("TRUNCATE TABLE ? RESTART IDENTITY", clause.Table{Name: table})
+	case config.DriverMySQL:
+		database.Exec("TRUNCATE TABLE ?", clause.Table{Name: table})
+	default:
+		var triggers []struct {
+			Name string
+			Sql  string
+		}
+
+		database.Raw("SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ?", table).Scan(&triggers)
+
+		for _, trigger := range triggers {
+			database.Exec("DROP TRIGGER ?", clause.Table{Name: trigger.Name})
+		}
+
+		database.Exec("DELETE FROM ?", clause.Table{Name: table})
+		database.Exec("DELETE FROM sqlite_sequence WHERE name = ?", table)
+
+		for _, trigger := range triggers {
+			database.Exec(trigger.Sql)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package validationtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/request"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func Details(t *testing.T, err error, lang string) map[string]apperror.FieldError {
+	var appErr *apperror.Error
+	if !assert.True(t, errors.As(err, &appErr)) {
+		return nil
+	}
+
+	details := map[string]apperror.FieldError{}
+	for _, field := range appErr.Details(lang) {
+		details[field.Field] = field
+	}
+
+	return details
+}
+
+func TestStructValid(t *testing.T) {
+	assert.Nil(t, validation.Struct(request.CreateAuthor{Name: "Andrea Hirata", Birthdate: "1967-10-24"}))
+	assert.Nil(t, validation.Struct(request.User{Username: "andrea.hirata_67", Password: "rahasia123"}))
+	assert.Nil(t, validation.Struct(request.CreateBook{
+		Title:           "Laskar Pelangi",
+		Isbn:            "979-3062-79-7",
+		Contributors:    []request.BookContributor{{AuthorId: 1}, {AuthorId: 2, Role: "Editor"}},
+		CategoryIds:     []int{1, 2},
+		BookPublication: request.BookPublication{Language: "ID", Format: "paperback", PublicationYear: 2005},
+	}))
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	err := validation.Struct(request.CreateBook{
+		Title:           "Go",
+		Isbn:            "9780306406158",
+		Contributors:    []request.BookContributor{{AuthorId: 1}, {AuthorId: 0, Role: "publisher"}},
+		CategoryIds:     []int{1, 1},
+		BookPublication: request.BookPublication{PageCount: -1, Language: "indonesia", Format: "magazine"},
+	})
+
+	assert.True(t, errors.Is(err, apperror.ErrValidation))
+	assert.Equal(t, "data yang dikirim tidak valid, lihat details", err.Error())
+
+	details := Details(t, err, i18n.Default)
+	assert.Len(t, details, 8)
+	assert.Equal(t, "min", details["title"].Code)
+	assert.Equal(t, "title minimal 3 karakter", details["title"].Message)
+	assert.Equal(t, "isbn", details["isbn"].Code)
+	assert.Equal(t, "check digit isbn tidak valid", details["isbn"].Message)
+	assert.Equal(t, "gt", details["contributors[1].author_id"].Code)
+	assert.Equal(t, "contributor_role", details["contributors[1].role"].Code)
+	assert.Equal(t, "contributors[1].role hanya boleh salah satu dari : author, editor, translator, illustrator", details["contributors[1].role"].Message)
+	assert.Equal(t, "unique", details["category_ids"].Code)
+	assert.Equal(t, "gte", details["page_count"].Code)
+	assert.Equal(t, "language", details["language"].Code)
+	assert.Equal(t, "format hanya boleh salah satu dari : hardcover, paperback, ebook, audio", details["format"].Message)
+}
+
+func TestStructRequiredWithout(t *testing.T) {
+	details := Details(t, validation.Struct(request.CreateBook{Title: "Laskar Pelangi", Isbn: "9789793062792"}), i18n.English)
+
+	assert.Equal(t, "required_without", details["author_id"].Code)
+	assert.Equal(t, "author_id is required when contributors is empty", details["author_id"].Message)
+}
+
+func TestStructCustomRules(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	details := Details(t, validation.Struct(request.CreateAuthor{Name: "Andrea", Birthdate: tomorrow}), i18n.English)
+	assert.Equal(t, "pastdate", details["birth_date"].Code)
+	assert.Equal(t, "birth_date must be a YYYY-MM-DD date that is not later than today", details["birth_date"].Message)
+
+	details = Details(t, validation.Struct(request.CreateAuthor{Name: "Andrea", Birthdate: "24-10-1967"}), i18n.English)
+	assert.Equal(t, "pastdate", details["birth_date"].Code)
+
+	details = Details(t, validation.Struct(request.User{Username: "andrea hirata", Password: "rahasia123"}), i18n.English)
+	assert.Equal(t, "username", details["username"].Code)
+	assert.Equal(t, "username may only contain letters, digits, dots and underscores", details["username"].Message)
+}
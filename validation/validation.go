@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ilhaamms/library-api/apperror"
+	"github.com/ilhaamms/library-api/entity/data"
+	"github.com/ilhaamms/library-api/i18n"
+	"github.com/ilhaamms/library-api/isbn"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._]+$`)
+
+// allowed lists the values accepted by the custom rules that check a value
+// against a fixed set, shown to the client when the rule fails.
+var allowed = map[string][]string{
+	"book_format":      data.BookFormats,
+	"contributor_role": data.ContributorRoles,
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// fields are reported by their json name, the name the client sent
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	v.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		return isbn.Validate(fl.Field().String()) == nil
+	})
+
+	// pastdate is a YYYY-MM-DD date that is not later than today
+	v.RegisterValidation("pastdate", func(fl validator.FieldLevel) bool {
+		date, err := time.Parse("2006-01-02", fl.Field().String())
+
+		return err == nil && !date.After(time.Now())
+	})
+
+	v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernamePattern.MatchString(fl.Field().String())
+	})
+
+	// language is an ISO 639-1 code, the list is too long to show
+	v.RegisterValidation("language", oneOf(data.Languages))
+
+	for tag, values := range allowed {
+		v.RegisterValidation(tag, oneOf(values))
+	}
+
+	return v
+}
+
+// oneOf accepts the values case insensitively, services lower case them
+// afterwards.
+func oneOf(values []string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value := strings.ToLower(strings.TrimSpace(fl.Field().String()))
+
+		for _, candidate := range values {
+			if candidate == value {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Struct checks the validate tags of a request and reports every field that
+// breaks a rule at once, so a client can point out all of them in one round
+// trip.
+func Struct(request interface{}) error {
+	err := validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return apperror.Internal("error.internal", err)
+	}
+
+	fields := make([]apperror.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, fieldError(fe))
+	}
+
+	return apperror.Validation("validation.failed", fields...)
+}
+
+func fieldError(fe validator.FieldError) apperror.FieldError {
+	field := fieldPath(fe.Namespace())
+	params := i18n.Params{"field": field, "param": fe.Param()}
+
+	key := "validation." + fe.Tag()
+
+	switch fe.Tag() {
+	case "isbn":
+		key = isbn.MessageKey(isbn.Validate(fe.Value().(string)))
+	case "required_without":
+		params["param"] = snakeCase(fe.Param())
+	case "oneof":
+		params["param"] = strings.Join(strings.Fields(fe.Param()), ", ")
+	}
+
+	if values, ok := allowed[fe.Tag()]; ok {
+		key = "validation.oneof"
+		params["param"] = strings.Join(values, ", ")
+	}
+
+	return apperror.FieldError{Field: field, Code: fe.Tag(), Key: key, Params: params}
+}
+
+// fieldPath turns a validator namespace such as
+// "CreateBook.BookPublication.page_count" into "page_count": the request type
+// and embedded structs, which keep their Go name, are not part of the json.
+func fieldPath(namespace string) string {
+	var path []string
+	for _, part := range strings.Split(namespace, ".")[1:] {
+		if part != "" && unicode.IsUpper(rune(part[0])) {
+			continue
+		}
+
+		path = append(path, part)
+	}
+
+	return strings.Join(path, ".")
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}